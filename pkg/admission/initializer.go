@@ -4,6 +4,7 @@ import (
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	routeinformers "github.com/openshift/client-go/route/informers/externalversions"
 	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/client-go/tools/cache"
 )
 
 // NewOpenShiftInformersInitializer returns an admission plugin initializer that injects
@@ -11,16 +12,19 @@ import (
 func NewOpenShiftInformersInitializer(
 	configInformers configinformers.SharedInformerFactory,
 	routeInformers routeinformers.SharedInformerFactory,
+	buildStrategyPolicyInformer cache.SharedIndexInformer,
 ) *openshiftInformersInitializer {
 	return &openshiftInformersInitializer{
-		configInformers: configInformers,
-		routeInformers:  routeInformers,
+		configInformers:             configInformers,
+		routeInformers:              routeInformers,
+		buildStrategyPolicyInformer: buildStrategyPolicyInformer,
 	}
 }
 
 type openshiftInformersInitializer struct {
-	configInformers configinformers.SharedInformerFactory
-	routeInformers  routeinformers.SharedInformerFactory
+	configInformers             configinformers.SharedInformerFactory
+	routeInformers              routeinformers.SharedInformerFactory
+	buildStrategyPolicyInformer cache.SharedIndexInformer
 }
 
 func (i *openshiftInformersInitializer) Initialize(plugin admission.Interface) {
@@ -30,4 +34,7 @@ func (i *openshiftInformersInitializer) Initialize(plugin admission.Interface) {
 	if wants, ok := plugin.(WantsOpenShiftRouteInformers); ok {
 		wants.SetOpenShiftRouteInformers(i.routeInformers)
 	}
+	if wants, ok := plugin.(WantsBuildStrategyPolicyInformer); ok {
+		wants.SetBuildStrategyPolicyInformer(i.buildStrategyPolicyInformer)
+	}
 }
@@ -3,6 +3,7 @@ package admission
 import (
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	routeinformers "github.com/openshift/client-go/route/informers/externalversions"
+	"k8s.io/client-go/tools/cache"
 )
 
 // WantsOpenShiftConfigInformers interface should be implemented by admission plugins
@@ -16,3 +17,9 @@ type WantsOpenShiftConfigInformers interface {
 type WantsOpenShiftRouteInformers interface {
 	SetOpenShiftRouteInformers(informers routeinformers.SharedInformerFactory)
 }
+
+// WantsBuildStrategyPolicyInformer interface should be implemented by admission plugins
+// that want to have the build strategy policy informer injected.
+type WantsBuildStrategyPolicyInformer interface {
+	SetBuildStrategyPolicyInformer(informer cache.SharedIndexInformer)
+}
@@ -0,0 +1,250 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+// Categories of the changes between two builds.
+const (
+	CategorySource          Category = "Source"
+	CategoryBuilderImage    Category = "BuilderImage"
+	CategoryBuildArgs       Category = "BuildArgs"
+	CategorySecrets         Category = "Secrets"
+	CategoryStrategyOptions Category = "StrategyOptions"
+	CategoryOutput          Category = "Output"
+	// CategoryTriggeredBy holds the changes of the trigger causes, which tell why a
+	// build was started rather than what it builds.
+	CategoryTriggeredBy Category = "TriggeredBy"
+)
+
+// Builds returns the changes from build from to build to. Strategy fields shared by
+// all strategies are reported under spec.strategy, regardless of the strategy type.
+// Changes of the trigger causes are reported under CategoryTriggeredBy.
+func Builds(from, to *buildv1.Build) []Change {
+	d := newDiffer()
+
+	d.compare(CategorySource, "spec.source.git.uri", gitURI(from), gitURI(to))
+	d.compare(CategorySource, "spec.source.git.ref", gitRef(from), gitRef(to))
+	d.compare(CategorySource, "spec.source.contextDir", from.Spec.Source.ContextDir, to.Spec.Source.ContextDir)
+	d.compare(CategorySource, "spec.source.dockerfile", stringValue(from.Spec.Source.Dockerfile), stringValue(to.Spec.Source.Dockerfile))
+	d.compare(CategorySource, "spec.revision.git.commit", commit(from), commit(to))
+	d.compareMaps(CategorySource, "spec.source.configMaps", configMaps(from), configMaps(to))
+
+	fromStrategy, toStrategy := strategyOf(from), strategyOf(to)
+	d.compare(CategoryStrategyOptions, "spec.strategy.type", string(from.Spec.Strategy.Type), string(to.Spec.Strategy.Type))
+	d.compare(CategoryBuilderImage, "spec.strategy.from", fromStrategy.from, toStrategy.from)
+	d.compareMaps(CategoryBuilderImage, "spec.source.images", sourceImages(from), sourceImages(to))
+	d.compareMaps(CategoryEnv, "spec.strategy.env", envMap(fromStrategy.env), envMap(toStrategy.env))
+	d.compareMaps(CategoryBuildArgs, "spec.strategy.dockerStrategy.buildArgs", envMap(fromStrategy.buildArgs), envMap(toStrategy.buildArgs))
+
+	d.compare(CategorySecrets, "spec.source.sourceSecret", localName(from.Spec.Source.SourceSecret), localName(to.Spec.Source.SourceSecret))
+	d.compare(CategorySecrets, "spec.strategy.pullSecret", localName(fromStrategy.pullSecret), localName(toStrategy.pullSecret))
+	d.compare(CategorySecrets, "spec.output.pushSecret", localName(from.Spec.Output.PushSecret), localName(to.Spec.Output.PushSecret))
+	d.compareMaps(CategorySecrets, "spec.source.secrets", sourceSecrets(from), sourceSecrets(to))
+	d.compareMaps(CategorySecrets, "spec.strategy.customStrategy.secrets", fromStrategy.secrets, toStrategy.secrets)
+
+	d.compareMaps(CategoryStrategyOptions, "spec.strategy", fromStrategy.options, toStrategy.options)
+	d.compareMaps(CategoryStrategyOptions, "spec.strategy.volumes", fromStrategy.volumes, toStrategy.volumes)
+
+	d.compare(CategoryOutput, "spec.output.to", objectReference(from.Spec.Output.To), objectReference(to.Spec.Output.To))
+	d.compare(CategoryOutput, "spec.output.additionalTags", strings.Join(from.Spec.Output.AdditionalTags, ","), strings.Join(to.Spec.Output.AdditionalTags, ","))
+
+	d.compareMaps(CategoryTriggeredBy, "spec.triggeredBy", triggerCauses(from), triggerCauses(to))
+
+	return d.changes
+}
+
+// buildStrategy holds the fields of a build strategy, whatever its type.
+type buildStrategy struct {
+	from       string
+	pullSecret *corev1.LocalObjectReference
+	env        []corev1.EnvVar
+	buildArgs  []corev1.EnvVar
+	secrets    map[string]string
+	options    map[string]string
+	volumes    map[string]string
+}
+
+func strategyOf(build *buildv1.Build) buildStrategy {
+	s := buildStrategy{options: map[string]string{}, volumes: map[string]string{}}
+	switch spec := build.Spec.Strategy; {
+	case spec.DockerStrategy != nil:
+		docker := spec.DockerStrategy
+		s.from = objectReference(docker.From)
+		s.pullSecret = docker.PullSecret
+		s.env = docker.Env
+		s.buildArgs = docker.BuildArgs
+		s.options["dockerStrategy.noCache"] = strconv.FormatBool(docker.NoCache)
+		s.options["dockerStrategy.forcePull"] = strconv.FormatBool(docker.ForcePull)
+		s.options["dockerStrategy.dockerfilePath"] = docker.DockerfilePath
+		if docker.ImageOptimizationPolicy != nil {
+			s.options["dockerStrategy.imageOptimizationPolicy"] = string(*docker.ImageOptimizationPolicy)
+		}
+		s.volumes = buildVolumes(docker.Volumes)
+	case spec.SourceStrategy != nil:
+		source := spec.SourceStrategy
+		s.from = objectReference(&source.From)
+		s.pullSecret = source.PullSecret
+		s.env = source.Env
+		s.options["sourceStrategy.forcePull"] = strconv.FormatBool(source.ForcePull)
+		if source.Incremental != nil {
+			s.options["sourceStrategy.incremental"] = strconv.FormatBool(*source.Incremental)
+		}
+		s.options["sourceStrategy.scripts"] = source.Scripts
+		s.volumes = buildVolumes(source.Volumes)
+	case spec.CustomStrategy != nil:
+		custom := spec.CustomStrategy
+		s.from = objectReference(&custom.From)
+		s.pullSecret = custom.PullSecret
+		s.env = custom.Env
+		s.options["customStrategy.forcePull"] = strconv.FormatBool(custom.ForcePull)
+		s.options["customStrategy.exposeDockerSocket"] = strconv.FormatBool(custom.ExposeDockerSocket)
+		s.secrets = map[string]string{}
+		for _, secret := range custom.Secrets {
+			s.secrets[secret.SecretSource.Name] = secret.MountPath
+		}
+	case spec.JenkinsPipelineStrategy != nil:
+		jenkins := spec.JenkinsPipelineStrategy
+		s.env = jenkins.Env
+		s.options["jenkinsPipelineStrategy.jenkinsfilePath"] = jenkins.JenkinsfilePath
+		s.options["jenkinsPipelineStrategy.jenkinsfile"] = jenkins.Jenkinsfile
+	}
+	for key, value := range s.options {
+		if len(value) == 0 {
+			delete(s.options, key)
+		}
+	}
+	return s
+}
+
+func buildVolumes(volumes []buildv1.BuildVolume) map[string]string {
+	m := map[string]string{}
+	for _, volume := range volumes {
+		source := string(volume.Source.Type)
+		switch {
+		case volume.Source.Secret != nil:
+			source += " " + volume.Source.Secret.SecretName
+		case volume.Source.ConfigMap != nil:
+			source += " " + volume.Source.ConfigMap.Name
+		case volume.Source.CSI != nil:
+			source += " " + volume.Source.CSI.Driver
+		}
+		m[volume.Name] = source
+	}
+	return m
+}
+
+// triggerCauses returns the descriptions of the trigger causes of build by index.
+func triggerCauses(build *buildv1.Build) map[string]string {
+	m := map[string]string{}
+	for i, cause := range build.Spec.TriggeredBy {
+		m[strconv.Itoa(i)] = triggerCause(cause)
+	}
+	return m
+}
+
+// triggerCause describes cause by its type and revision or image, or by its message
+// if it has no type.
+func triggerCause(cause buildv1.BuildTriggerCause) string {
+	switch {
+	case cause.GenericWebHook != nil:
+		return "generic webhook" + revisionOf(cause.GenericWebHook.Revision)
+	case cause.GitHubWebHook != nil:
+		return "GitHub webhook" + revisionOf(cause.GitHubWebHook.Revision)
+	case cause.GitLabWebHook != nil:
+		return "GitLab webhook" + revisionOf(cause.GitLabWebHook.Revision)
+	case cause.BitbucketWebHook != nil:
+		return "Bitbucket webhook" + revisionOf(cause.BitbucketWebHook.Revision)
+	case cause.GiteaWebHook != nil:
+		return "Gitea webhook" + revisionOf(cause.GiteaWebHook.Revision)
+	case cause.AzureDevOpsWebHook != nil:
+		return "Azure DevOps webhook" + revisionOf(cause.AzureDevOpsWebHook.Revision)
+	case cause.ImageChangeBuild != nil:
+		return strings.TrimSpace(fmt.Sprintf("image change %s %s", objectReference(cause.ImageChangeBuild.FromRef), cause.ImageChangeBuild.ImageID))
+	case cause.BuildRetry != nil:
+		return fmt.Sprintf("attempt %d retrying build %s", cause.BuildRetry.Attempt, cause.BuildRetry.BuildName)
+	}
+	return cause.Message
+}
+
+func revisionOf(revision *buildv1.SourceRevision) string {
+	if revision == nil || revision.Git == nil || len(revision.Git.Commit) == 0 {
+		return ""
+	}
+	return " commit " + revision.Git.Commit
+}
+
+func sourceImages(build *buildv1.Build) map[string]string {
+	m := map[string]string{}
+	for i, image := range build.Spec.Source.Images {
+		m[strconv.Itoa(i)] = objectReference(&image.From)
+	}
+	return m
+}
+
+func sourceSecrets(build *buildv1.Build) map[string]string {
+	m := map[string]string{}
+	for _, secret := range build.Spec.Source.Secrets {
+		m[secret.Secret.Name] = secret.DestinationDir
+	}
+	return m
+}
+
+func configMaps(build *buildv1.Build) map[string]string {
+	m := map[string]string{}
+	for _, configMap := range build.Spec.Source.ConfigMaps {
+		m[configMap.ConfigMap.Name] = configMap.DestinationDir
+	}
+	return m
+}
+
+func gitURI(build *buildv1.Build) string {
+	if build.Spec.Source.Git == nil {
+		return ""
+	}
+	return build.Spec.Source.Git.URI
+}
+
+func gitRef(build *buildv1.Build) string {
+	if build.Spec.Source.Git == nil {
+		return ""
+	}
+	return build.Spec.Source.Git.Ref
+}
+
+func commit(build *buildv1.Build) string {
+	if build.Spec.Revision == nil || build.Spec.Revision.Git == nil {
+		return ""
+	}
+	return build.Spec.Revision.Git.Commit
+}
+
+func objectReference(ref *corev1.ObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	if len(ref.Namespace) > 0 {
+		return fmt.Sprintf("%s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return fmt.Sprintf("%s %s", ref.Kind, ref.Name)
+}
+
+func localName(ref *corev1.LocalObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+func testBuild(commit, image string, env []corev1.EnvVar, noCache bool) *buildv1.Build {
+	return &buildv1.Build{
+		Spec: buildv1.BuildSpec{
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Git:     &buildv1.GitBuildSource{URI: "https://github.com/openshift/ruby-hello-world", Ref: "main"},
+					Secrets: []buildv1.SecretBuildSource{{Secret: corev1.LocalObjectReference{Name: "netrc"}, DestinationDir: "auth"}},
+				},
+				Revision: &buildv1.SourceRevision{Git: &buildv1.GitSourceRevision{Commit: commit}},
+				Strategy: buildv1.BuildStrategy{
+					Type: buildv1.DockerBuildStrategyType,
+					DockerStrategy: &buildv1.DockerBuildStrategy{
+						From:      &corev1.ObjectReference{Kind: "DockerImage", Name: image},
+						Env:       env,
+						BuildArgs: []corev1.EnvVar{{Name: "VERSION", Value: "1"}},
+						NoCache:   noCache,
+					},
+				},
+				Output: buildv1.BuildOutput{To: &corev1.ObjectReference{Kind: "ImageStreamTag", Name: "app:latest"}},
+			},
+		},
+	}
+}
+
+func TestBuilds(t *testing.T) {
+	from := testBuild("abc", "registry/ruby@sha256:1111", []corev1.EnvVar{
+		{Name: "A", Value: "1"},
+		{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "token"}, Key: "value"}}},
+	}, false)
+	from.Spec.TriggeredBy = []buildv1.BuildTriggerCause{{Message: "Manually triggered"}}
+	to := testBuild("def", "registry/ruby@sha256:2222", []corev1.EnvVar{
+		{Name: "A", Value: "2"},
+		{Name: "B", Value: "3"},
+	}, true)
+	to.Spec.Source.Secrets = nil
+	to.Spec.TriggeredBy = []buildv1.BuildTriggerCause{{
+		Message:       "GitHub WebHook",
+		GitHubWebHook: &buildv1.GitHubWebHookCause{Revision: &buildv1.SourceRevision{Git: &buildv1.GitSourceRevision{Commit: "def"}}},
+	}}
+
+	expected := []Change{
+		{Category: CategorySource, Field: "spec.revision.git.commit", From: "abc", To: "def"},
+		{Category: CategoryBuilderImage, Field: "spec.strategy.from", From: "DockerImage registry/ruby@sha256:1111", To: "DockerImage registry/ruby@sha256:2222"},
+		{Category: CategoryEnv, Field: "spec.strategy.env[A]", From: "1", To: "2"},
+		{Category: CategoryEnv, Field: "spec.strategy.env[B]", To: "3"},
+		{Category: CategoryEnv, Field: "spec.strategy.env[TOKEN]", From: "secretKeyRef token/value"},
+		{Category: CategorySecrets, Field: "spec.source.secrets[netrc]", From: "auth"},
+		{Category: CategoryStrategyOptions, Field: "spec.strategy[dockerStrategy.noCache]", From: "false", To: "true"},
+		{Category: CategoryTriggeredBy, Field: "spec.triggeredBy[0]", From: "Manually triggered", To: "GitHub webhook commit def"},
+	}
+	if changes := Builds(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes\n%#v\ngot\n%#v", expected, changes)
+	}
+
+	if changes := Builds(from, from.DeepCopy()); len(changes) != 0 {
+		t.Errorf("expected no changes between identical builds, got %#v", changes)
+	}
+}
+
+func TestBuildsStrategyType(t *testing.T) {
+	from := testBuild("abc", "registry/ruby@sha256:1111", nil, false)
+	to := &buildv1.Build{Spec: buildv1.BuildSpec{CommonSpec: buildv1.CommonSpec{
+		Source:   from.Spec.Source,
+		Revision: from.Spec.Revision,
+		Output:   from.Spec.Output,
+		Strategy: buildv1.BuildStrategy{
+			Type:           buildv1.SourceBuildStrategyType,
+			SourceStrategy: &buildv1.SourceBuildStrategy{From: corev1.ObjectReference{Kind: "DockerImage", Name: "registry/ruby@sha256:1111"}},
+		},
+	}}}
+
+	expected := []Change{
+		{Category: CategoryStrategyOptions, Field: "spec.strategy.type", From: "Docker", To: "Source"},
+		{Category: CategoryBuildArgs, Field: "spec.strategy.dockerStrategy.buildArgs[VERSION]", From: "1"},
+		{Category: CategoryStrategyOptions, Field: "spec.strategy[dockerStrategy.forcePull]", From: "false"},
+		{Category: CategoryStrategyOptions, Field: "spec.strategy[dockerStrategy.noCache]", From: "false"},
+		{Category: CategoryStrategyOptions, Field: "spec.strategy[sourceStrategy.forcePull]", To: "false"},
+	}
+	if changes := Builds(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes\n%#v\ngot\n%#v", expected, changes)
+	}
+}
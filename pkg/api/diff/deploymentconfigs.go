@@ -0,0 +1,252 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+// Categories of the changes between two deployment config revisions.
+const (
+	CategoryReplication Category = "Replication"
+	CategoryImage       Category = "Image"
+	CategoryResources   Category = "Resources"
+	CategoryContainer   Category = "Container"
+	CategoryVolumes     Category = "Volumes"
+	CategoryPod         Category = "Pod"
+	CategoryStrategy    Category = "Strategy"
+	CategoryTrigger     Category = "Trigger"
+)
+
+// DeploymentConfigs returns the changes from deployment config from to deployment
+// config to that cause a new revision: the pod template, the strategy and the
+// triggers. Containers are matched by name. The replica count is not compared,
+// since scaling does not create a revision.
+func DeploymentConfigs(from, to *appsv1.DeploymentConfig) []Change {
+	d := newDiffer()
+
+	fromTemplate, toTemplate := podTemplate(from), podTemplate(to)
+	d.compareMaps(CategoryPod, "spec.template.metadata.labels", fromTemplate.Labels, toTemplate.Labels)
+	d.compareContainers("spec.template.spec.initContainers", fromTemplate.Spec.InitContainers, toTemplate.Spec.InitContainers)
+	d.compareContainers("spec.template.spec.containers", fromTemplate.Spec.Containers, toTemplate.Spec.Containers)
+	d.compareMaps(CategoryVolumes, "spec.template.spec.volumes", podVolumes(fromTemplate.Spec.Volumes), podVolumes(toTemplate.Spec.Volumes))
+	d.compare(CategoryPod, "spec.template.spec.serviceAccountName", fromTemplate.Spec.ServiceAccountName, toTemplate.Spec.ServiceAccountName)
+	d.compareMaps(CategoryPod, "spec.template.spec.nodeSelector", fromTemplate.Spec.NodeSelector, toTemplate.Spec.NodeSelector)
+	d.compareMaps(CategoryPod, "spec.template.spec.imagePullSecrets", localNames(fromTemplate.Spec.ImagePullSecrets), localNames(toTemplate.Spec.ImagePullSecrets))
+
+	d.compare(CategoryStrategy, "spec.strategy.type", string(from.Spec.Strategy.Type), string(to.Spec.Strategy.Type))
+	d.compareMaps(CategoryStrategy, "spec.strategy", strategyOptions(from.Spec.Strategy), strategyOptions(to.Spec.Strategy))
+	d.compare(CategoryStrategy, "spec.minReadySeconds", strconv.Itoa(int(from.Spec.MinReadySeconds)), strconv.Itoa(int(to.Spec.MinReadySeconds)))
+
+	d.compareMaps(CategoryTrigger, "spec.triggers", triggers(from.Spec.Triggers), triggers(to.Spec.Triggers))
+	return d.changes
+}
+
+// ReplicationMeta returns the changes to the replica count and the selector from
+// deployment config from to deployment config to.
+func ReplicationMeta(from, to *appsv1.DeploymentConfig) []Change {
+	d := newDiffer()
+	d.compare(CategoryReplication, "spec.replicas", strconv.Itoa(int(from.Spec.Replicas)), strconv.Itoa(int(to.Spec.Replicas)))
+	d.compareMaps(CategoryReplication, "spec.selector", from.Spec.Selector, to.Spec.Selector)
+	return d.changes
+}
+
+// compareContainers compares containers by name. A container only present in one
+// of the revisions is reported by its image.
+func (d *differ) compareContainers(field string, from, to []corev1.Container) {
+	fromByName, toByName := map[string]corev1.Container{}, map[string]corev1.Container{}
+	for _, container := range from {
+		fromByName[container.Name] = container
+	}
+	for _, container := range to {
+		toByName[container.Name] = container
+	}
+	images := func(containers map[string]corev1.Container) map[string]string {
+		m := map[string]string{}
+		for name, container := range containers {
+			m[name] = container.Image
+		}
+		return m
+	}
+	d.compareMaps(CategoryImage, field, images(fromByName), images(toByName))
+
+	names := []string{}
+	for name := range fromByName {
+		if _, ok := toByName[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fromContainer, toContainer := fromByName[name], toByName[name]
+		containerField := fmt.Sprintf("%s[%s]", field, name)
+		d.compareMaps(CategoryEnv, containerField+".env", envMap(fromContainer.Env), envMap(toContainer.Env))
+		d.compareMaps(CategoryEnv, containerField+".envFrom", envFrom(fromContainer.EnvFrom), envFrom(toContainer.EnvFrom))
+		d.compareMaps(CategoryResources, containerField+".resources", resources(fromContainer.Resources), resources(toContainer.Resources))
+		d.compare(CategoryContainer, containerField+".command", strings.Join(fromContainer.Command, " "), strings.Join(toContainer.Command, " "))
+		d.compare(CategoryContainer, containerField+".args", strings.Join(fromContainer.Args, " "), strings.Join(toContainer.Args, " "))
+		d.compareMaps(CategoryContainer, containerField+".ports", ports(fromContainer.Ports), ports(toContainer.Ports))
+		d.compareMaps(CategoryVolumes, containerField+".volumeMounts", volumeMounts(fromContainer.VolumeMounts), volumeMounts(toContainer.VolumeMounts))
+	}
+}
+
+func podTemplate(config *appsv1.DeploymentConfig) *corev1.PodTemplateSpec {
+	if config.Spec.Template == nil {
+		return &corev1.PodTemplateSpec{}
+	}
+	return config.Spec.Template
+}
+
+func envFrom(sources []corev1.EnvFromSource) map[string]string {
+	m := map[string]string{}
+	for _, source := range sources {
+		switch {
+		case source.SecretRef != nil:
+			m["secret "+source.SecretRef.Name] = source.Prefix
+		case source.ConfigMapRef != nil:
+			m["configMap "+source.ConfigMapRef.Name] = source.Prefix
+		}
+	}
+	return m
+}
+
+func resources(requirements corev1.ResourceRequirements) map[string]string {
+	m := map[string]string{}
+	for name, quantity := range requirements.Limits {
+		m["limits."+string(name)] = quantity.String()
+	}
+	for name, quantity := range requirements.Requests {
+		m["requests."+string(name)] = quantity.String()
+	}
+	return m
+}
+
+func ports(containerPorts []corev1.ContainerPort) map[string]string {
+	m := map[string]string{}
+	for _, port := range containerPorts {
+		protocol := port.Protocol
+		if len(protocol) == 0 {
+			protocol = corev1.ProtocolTCP
+		}
+		m[fmt.Sprintf("%d/%s", port.ContainerPort, protocol)] = port.Name
+	}
+	return m
+}
+
+func volumeMounts(mounts []corev1.VolumeMount) map[string]string {
+	m := map[string]string{}
+	for _, mount := range mounts {
+		m[mount.MountPath] = mount.Name
+	}
+	return m
+}
+
+func podVolumes(volumes []corev1.Volume) map[string]string {
+	m := map[string]string{}
+	for _, volume := range volumes {
+		source := "other"
+		switch {
+		case volume.Secret != nil:
+			source = "secret " + volume.Secret.SecretName
+		case volume.ConfigMap != nil:
+			source = "configMap " + volume.ConfigMap.Name
+		case volume.PersistentVolumeClaim != nil:
+			source = "persistentVolumeClaim " + volume.PersistentVolumeClaim.ClaimName
+		case volume.EmptyDir != nil:
+			source = "emptyDir"
+		case volume.HostPath != nil:
+			source = "hostPath " + volume.HostPath.Path
+		case volume.Projected != nil:
+			source = "projected"
+		case volume.DownwardAPI != nil:
+			source = "downwardAPI"
+		}
+		m[volume.Name] = source
+	}
+	return m
+}
+
+func localNames(refs []corev1.LocalObjectReference) map[string]string {
+	m := map[string]string{}
+	for _, ref := range refs {
+		m[ref.Name] = ref.Name
+	}
+	return m
+}
+
+func strategyOptions(strategy appsv1.DeploymentStrategy) map[string]string {
+	m := map[string]string{}
+	if strategy.ActiveDeadlineSeconds != nil {
+		m["activeDeadlineSeconds"] = strconv.FormatInt(*strategy.ActiveDeadlineSeconds, 10)
+	}
+	if params := strategy.RollingParams; params != nil {
+		if params.MaxSurge != nil {
+			m["rollingParams.maxSurge"] = params.MaxSurge.String()
+		}
+		if params.MaxUnavailable != nil {
+			m["rollingParams.maxUnavailable"] = params.MaxUnavailable.String()
+		}
+		if params.TimeoutSeconds != nil {
+			m["rollingParams.timeoutSeconds"] = strconv.FormatInt(*params.TimeoutSeconds, 10)
+		}
+		m["rollingParams.pre"] = hook(params.Pre)
+		m["rollingParams.post"] = hook(params.Post)
+	}
+	if params := strategy.RecreateParams; params != nil {
+		if params.TimeoutSeconds != nil {
+			m["recreateParams.timeoutSeconds"] = strconv.FormatInt(*params.TimeoutSeconds, 10)
+		}
+		m["recreateParams.pre"] = hook(params.Pre)
+		m["recreateParams.mid"] = hook(params.Mid)
+		m["recreateParams.post"] = hook(params.Post)
+	}
+	if params := strategy.CustomParams; params != nil {
+		m["customParams.image"] = params.Image
+		m["customParams.command"] = strings.Join(params.Command, " ")
+	}
+	for key, value := range m {
+		if len(value) == 0 {
+			delete(m, key)
+		}
+	}
+	return m
+}
+
+func hook(lifecycleHook *appsv1.LifecycleHook) string {
+	if lifecycleHook == nil {
+		return ""
+	}
+	switch {
+	case lifecycleHook.ExecNewPod != nil:
+		return fmt.Sprintf("execNewPod %s: %s (%s)", lifecycleHook.ExecNewPod.ContainerName, strings.Join(lifecycleHook.ExecNewPod.Command, " "), lifecycleHook.FailurePolicy)
+	case len(lifecycleHook.TagImages) > 0:
+		return fmt.Sprintf("tagImages (%s)", lifecycleHook.FailurePolicy)
+	}
+	return string(lifecycleHook.FailurePolicy)
+}
+
+func triggers(policies []appsv1.DeploymentTriggerPolicy) map[string]string {
+	m := map[string]string{}
+	for _, trigger := range policies {
+		switch trigger.Type {
+		case appsv1.DeploymentTriggerOnImageChange:
+			params := trigger.ImageChangeParams
+			if params == nil {
+				continue
+			}
+			from := params.From.Name
+			if len(params.From.Namespace) > 0 {
+				from = params.From.Namespace + "/" + from
+			}
+			m[fmt.Sprintf("%s %s", trigger.Type, from)] = fmt.Sprintf("%s automatic=%t", strings.Join(params.ContainerNames, ","), params.Automatic)
+		default:
+			m[string(trigger.Type)] = string(trigger.Type)
+		}
+	}
+	return m
+}
@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+
+	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
+)
+
+func TestDeploymentConfigs(t *testing.T) {
+	from := appstest.OkDeploymentConfig(1)
+	from.Spec.Template.Spec.Containers[0].Env = append(from.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:      "TOKEN",
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "token"}, Key: "value"}},
+	})
+	from.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "config-v1"}}}}
+
+	to := appstest.OkDeploymentConfig(2)
+	to.Spec.Replicas = 5
+	to.Spec.Template.Spec.Containers[0].Image = "registry:8080/repo1:ref3"
+	to.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "ENV1", Value: "VAL2"}}
+	to.Spec.Template.Spec.Containers[1].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")}
+	to.Spec.Template.Spec.Containers = append(to.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar", Image: "registry:8080/proxy:1"})
+	to.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "config-v2"}}}}
+	to.Spec.Triggers[0].ImageChangeParams.Automatic = false
+
+	expected := []Change{
+		{Category: CategoryImage, Field: "spec.template.spec.containers[container1]", From: "registry:8080/repo1:ref1", To: "registry:8080/repo1:ref3"},
+		{Category: CategoryImage, Field: "spec.template.spec.containers[sidecar]", To: "registry:8080/proxy:1"},
+		{Category: CategoryEnv, Field: "spec.template.spec.containers[container1].env[ENV1]", From: "VAL1", To: "VAL2"},
+		{Category: CategoryEnv, Field: "spec.template.spec.containers[container1].env[TOKEN]", From: "secretKeyRef token/value"},
+		{Category: CategoryResources, Field: "spec.template.spec.containers[container2].resources[limits.memory]", To: "512Mi"},
+		{Category: CategoryVolumes, Field: "spec.template.spec.volumes[config]", From: "secret config-v1", To: "secret config-v2"},
+		{Category: CategoryTrigger, Field: "spec.triggers[ImageChange " + appstest.ImageStreamName + ":latest]", From: "container1 automatic=true", To: "container1 automatic=false"},
+	}
+	if changes := DeploymentConfigs(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes\n%#v\ngot\n%#v", expected, changes)
+	}
+
+	if changes := DeploymentConfigs(from, from.DeepCopy()); len(changes) != 0 {
+		t.Errorf("expected no changes between identical configs, got %#v", changes)
+	}
+}
+
+func TestDeploymentConfigsStrategy(t *testing.T) {
+	from := appstest.OkDeploymentConfig(1)
+	to := appstest.OkDeploymentConfig(2)
+	timeout := int64(600)
+	to.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type:          appsv1.DeploymentStrategyTypeRolling,
+		RollingParams: &appsv1.RollingDeploymentStrategyParams{TimeoutSeconds: &timeout},
+	}
+	to.Spec.Template = nil
+
+	expected := []Change{
+		{Category: CategoryPod, Field: "spec.template.metadata.labels[a]", From: "b"},
+		{Category: CategoryImage, Field: "spec.template.spec.containers[container1]", From: "registry:8080/repo1:ref1"},
+		{Category: CategoryImage, Field: "spec.template.spec.containers[container2]", From: "registry:8080/repo1:ref2"},
+		{Category: CategoryStrategy, Field: "spec.strategy.type", From: "Recreate", To: "Rolling"},
+		{Category: CategoryStrategy, Field: "spec.strategy[activeDeadlineSeconds]", From: "21600"},
+		{Category: CategoryStrategy, Field: "spec.strategy[recreateParams.timeoutSeconds]", From: "20"},
+		{Category: CategoryStrategy, Field: "spec.strategy[rollingParams.timeoutSeconds]", To: "600"},
+	}
+	if changes := DeploymentConfigs(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes\n%#v\ngot\n%#v", expected, changes)
+	}
+}
+
+func TestReplicationMeta(t *testing.T) {
+	from := appstest.OkDeploymentConfig(1)
+	to := appstest.OkDeploymentConfig(2)
+	to.Spec.Replicas = 3
+	to.Spec.Selector = map[string]string{"a": "c"}
+
+	expected := []Change{
+		{Category: CategoryReplication, Field: "spec.replicas", From: "1", To: "3"},
+		{Category: CategoryReplication, Field: "spec.selector[a]", From: "b", To: "c"},
+	}
+	if changes := ReplicationMeta(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes\n%#v\ngot\n%#v", expected, changes)
+	}
+}
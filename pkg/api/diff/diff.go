@@ -0,0 +1,78 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Category groups the changes between two objects by what they affect.
+type Category string
+
+const (
+	// CategoryEnv is shared by the environment of builds and of containers.
+	CategoryEnv Category = "Env"
+)
+
+// Change is a field that differs between two objects. From or To is empty if the
+// field is not set in the respective object.
+type Change struct {
+	Category Category `json:"category"`
+	Field    string   `json:"field"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+}
+
+type differ struct {
+	changes []Change
+}
+
+func newDiffer() *differ {
+	return &differ{changes: []Change{}}
+}
+
+func (d *differ) compare(category Category, field, from, to string) {
+	if from != to {
+		d.changes = append(d.changes, Change{Category: category, Field: field, From: from, To: to})
+	}
+}
+
+func (d *differ) compareMaps(category Category, field string, from, to map[string]string) {
+	keys := []string{}
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		d.compare(category, fmt.Sprintf("%s[%s]", field, key), from[key], to[key])
+	}
+}
+
+// envMap returns the values of env by name. Values from references are described
+// by their reference, since they are resolved only when the pods start.
+func envMap(env []corev1.EnvVar) map[string]string {
+	m := map[string]string{}
+	for _, e := range env {
+		value := e.Value
+		if from := e.ValueFrom; from != nil {
+			switch {
+			case from.SecretKeyRef != nil:
+				value = fmt.Sprintf("secretKeyRef %s/%s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+			case from.ConfigMapKeyRef != nil:
+				value = fmt.Sprintf("configMapKeyRef %s/%s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+			case from.FieldRef != nil:
+				value = fmt.Sprintf("fieldRef %s", from.FieldRef.FieldPath)
+			case from.ResourceFieldRef != nil:
+				value = fmt.Sprintf("resourceFieldRef %s", from.ResourceFieldRef.Resource)
+			}
+		}
+		m[e.Name] = value
+	}
+	return m
+}
@@ -0,0 +1,3 @@
+// Package diff describes the changes between two builds or between two deployment
+// config revisions as a list of fields, grouped by what they affect.
+package diff
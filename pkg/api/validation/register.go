@@ -49,7 +49,10 @@ func registerAll() {
 	Validator.MustRegister(&buildapi.Build{}, true, buildvalidation.ValidateBuild, buildvalidation.ValidateBuildUpdate)
 	Validator.MustRegister(&buildapi.BuildConfig{}, true, buildvalidation.ValidateBuildConfig, buildvalidation.ValidateBuildConfigUpdate)
 	Validator.MustRegister(&buildapi.BuildRequest{}, true, buildvalidation.ValidateBuildRequest, nil)
+	Validator.MustRegister(&buildapi.BuildCancelRequest{}, true, buildvalidation.ValidateBuildCancelRequest, nil)
 	Validator.MustRegister(&buildapi.BuildLogOptions{}, true, buildvalidation.ValidateBuildLogOptions, nil)
+	Validator.MustRegister(&buildapi.BuildDiffOptions{}, true, buildvalidation.ValidateBuildDiffOptions, nil)
+	Validator.MustRegister(&buildapi.BuildStrategyPolicy{}, false, buildvalidation.ValidateBuildStrategyPolicy, buildvalidation.ValidateBuildStrategyPolicyUpdate)
 
 	Validator.MustRegister(&appsapi.DeploymentConfig{}, true, appsvalidation.ValidateDeploymentConfig, appsvalidation.ValidateDeploymentConfigUpdate)
 	Validator.MustRegister(&appsapi.DeploymentConfigRollback{}, true, appsvalidation.ValidateDeploymentConfigRollback, nil)
@@ -38,9 +38,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DeploymentConfig{},
 		&DeploymentConfigList{},
 		&DeploymentConfigRollback{},
+		&DeploymentConfigRollbackPreview{},
+		&DeploymentConfigConversion{},
 		&DeploymentRequest{},
 		&DeploymentLog{},
 		&DeploymentLogOptions{},
+		&DeploymentConfigHistory{},
 		&autoscaling.Scale{},
 	)
 	return nil
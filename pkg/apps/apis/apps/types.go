@@ -3,6 +3,7 @@ package apps
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	kapps "k8s.io/kubernetes/pkg/apis/apps"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 )
 
@@ -84,6 +85,11 @@ type DeploymentConfigSpec struct {
 	// Template is the object that describes the pod that will be created if
 	// insufficient replicas are detected.
 	Template *kapi.PodTemplateSpec
+
+	// AutoRollback if true rolls the deployment config back to its last complete revision when
+	// its latest rollout fails. A rollout deployed by an automatic rollback, or deploying the pod
+	// template of a revision which was already rolled back automatically, is not rolled back.
+	AutoRollback bool
 }
 
 // DeploymentStrategy describes how to perform a deployment.
@@ -268,6 +274,12 @@ const (
 	// DeploymentTriggerOnConfigChange will create new deployments in response to changes to
 	// the ControllerTemplate of a DeploymentConfig.
 	DeploymentTriggerOnConfigChange DeploymentTriggerType = "ConfigChange"
+	// DeploymentTriggerRollback is only used as the cause of deployments created by
+	// rolling back to an earlier revision, it is not a valid trigger policy.
+	DeploymentTriggerRollback DeploymentTriggerType = "Rollback"
+	// DeploymentTriggerAutoRollback is only used as the cause of deployments created
+	// by automatically rolling back a failed rollout, it is not a valid trigger policy.
+	DeploymentTriggerAutoRollback DeploymentTriggerType = "AutoRollback"
 )
 
 // DeploymentTriggerImageChangeParams represents the parameters to the ImageChange trigger.
@@ -324,6 +336,9 @@ type DeploymentCause struct {
 	Type DeploymentTriggerType
 	// ImageTrigger contains the image trigger details, if this trigger was fired based on an image change
 	ImageTrigger *DeploymentCauseImageTrigger
+	// RollbackParams contains the rollback details, if the deployment was created by rolling
+	// back to an earlier revision
+	RollbackParams *DeploymentCauseRollbackParams
 }
 
 // DeploymentCauseImageTrigger contains information about a deployment caused by an image trigger
@@ -333,6 +348,17 @@ type DeploymentCauseImageTrigger struct {
 	From kapi.ObjectReference
 }
 
+// DeploymentCauseRollbackParams contains information about a deployment caused by a rollback
+type DeploymentCauseRollbackParams struct {
+	// FromRevision is the revision that was rolled back.
+	FromRevision int64
+	// ToRevision is the revision rolled back to.
+	ToRevision int64
+	// User is the name of the user who requested the rollback, empty if the rollback was
+	// automatic.
+	User string
+}
+
 type DeploymentConditionType string
 type DeploymentConditionReason string
 
@@ -376,6 +402,22 @@ type DeploymentConfigRollback struct {
 	Spec DeploymentConfigRollbackSpec
 }
 
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeploymentConfigRollbackPreview is returned by a dry-run rollback. It holds the deployment
+// config the rollback would produce, the changes it would make and what it refers to that no
+// longer exists.
+type DeploymentConfigRollbackPreview struct {
+	metav1.TypeMeta
+	// Config is the deployment config the rollback would produce.
+	Config DeploymentConfig
+	// Changes are the changes from the current deployment config to Config.
+	Changes []DeploymentConfigChange
+	// Warnings describe the images and secrets Config refers to that no longer exist, and
+	// the references which could not be checked because the requester cannot read them.
+	Warnings []string
+}
+
 // DeploymentConfigRollbackSpec represents the options for rollback generation.
 type DeploymentConfigRollbackSpec struct {
 	// From points to a ReplicationController which is a deployment.
@@ -390,6 +432,11 @@ type DeploymentConfigRollbackSpec struct {
 	IncludeReplicationMeta bool
 	// IncludeStrategy specifies whether to include the deployment Strategy.
 	IncludeStrategy bool
+	// KeepImageTriggers specifies whether the automatic image change triggers are kept enabled.
+	KeepImageTriggers bool
+	// Rollout specifies whether the rollback is deployed by the server instead of only being
+	// returned.
+	Rollout bool
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -408,6 +455,19 @@ type DeploymentRequest struct {
 	// This field overrides the triggers from latest and allows clients to control specific
 	// logic.
 	ExcludeTriggers []DeploymentTriggerType
+	// ImageOverrides set the images of containers for the deployment, instead of the
+	// images resolved by their image change triggers. The image of an override must
+	// be an image of the image stream tag followed by the trigger of its container,
+	// referenced by digest.
+	ImageOverrides []DeploymentImageOverride
+}
+
+// DeploymentImageOverride sets the image of a container for a deployment.
+type DeploymentImageOverride struct {
+	// ContainerName is the name of the container or init container.
+	ContainerName string
+	// Image is the pull spec of the image, referenced by digest.
+	Image string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -457,4 +517,108 @@ type DeploymentLogOptions struct {
 
 	// Version of the deployment for which to view logs.
 	Version *int64
+
+	// Hook selects the logs of the lifecycle hook pod of a phase of the deployment,
+	// one of pre, mid or post, instead of the deployer pod.
+	Hook DeploymentLogHook
+	// AllPods if true returns the logs of the deployer, hook and application pods of
+	// the deployment, each line prefixed with the name of its pod.
+	AllPods bool
+}
+
+// DeploymentLogHook is the phase of a lifecycle hook whose pod logs are selected.
+type DeploymentLogHook string
+
+const (
+	DeploymentLogHookPre  DeploymentLogHook = "pre"
+	DeploymentLogHookMid  DeploymentLogHook = "mid"
+	DeploymentLogHookPost DeploymentLogHook = "post"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeploymentConfigConversion is a deployment config converted to the equivalent apps/v1
+// deployment, to help migrating off deployment configs.
+type DeploymentConfigConversion struct {
+	metav1.TypeMeta
+	// Deployment is the deployment equivalent to the deployment config.
+	Deployment kapps.Deployment
+	// Issues are the fields of the deployment config that need review, because they
+	// were not converted exactly or not converted at all.
+	Issues []DeploymentConfigConversionIssue
+}
+
+// DeploymentConfigConversionIssueSeverity is how much of a field of a deployment config is
+// lost in its conversion to a deployment.
+type DeploymentConfigConversionIssueSeverity string
+
+const (
+	// DeploymentConfigConversionIssueLossy is set for fields converted to something close,
+	// but not equivalent, in the deployment.
+	DeploymentConfigConversionIssueLossy DeploymentConfigConversionIssueSeverity = "Lossy"
+	// DeploymentConfigConversionIssueUnsupported is set for fields with no equivalent in the
+	// deployment, which are dropped.
+	DeploymentConfigConversionIssueUnsupported DeploymentConfigConversionIssueSeverity = "Unsupported"
+)
+
+// DeploymentConfigConversionIssue describes a field of a deployment config that did not
+// convert exactly.
+type DeploymentConfigConversionIssue struct {
+	// Field is the path of the field in the deployment config.
+	Field string
+	// Severity is how much of the field is lost.
+	Severity DeploymentConfigConversionIssueSeverity
+	// Message describes what is lost and what to review.
+	Message string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeploymentConfigHistory is the revision history of a deployment config, as recorded by the
+// replication controllers of its deployments.
+type DeploymentConfigHistory struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Revisions are the revisions still retained, oldest revision first.
+	Revisions []DeploymentConfigRevision
+}
+
+// DeploymentConfigRevision is a deployment of a deployment config.
+type DeploymentConfigRevision struct {
+	// Revision is the version of the deployment config the deployment was created for.
+	Revision int64
+	// Deployment is the name of the replication controller of the revision.
+	Deployment string
+	// Status is the phase of the deployment.
+	Status string
+	// StatusReason is the reason the deployment is in its phase, if any.
+	StatusReason string
+	// Cancelled is true if the deployment was cancelled.
+	Cancelled bool
+	// Replicas is the current number of replicas of the revision.
+	Replicas int32
+	// CreationTimestamp is the time the deployment was created.
+	CreationTimestamp metav1.Time
+	// Details are the details of what caused the deployment.
+	Details *DeploymentDetails
+	// Template is the pod template of the revision.
+	Template *kapi.PodTemplateSpec
+	// Changes are the changes from the previous revision. They are not set for the
+	// oldest revision returned.
+	Changes []DeploymentConfigChange
+	// Error is set if the deployment config of the revision could not be decoded.
+	Error string
+}
+
+// DeploymentConfigChange is a field which differs between two revisions of a deployment config.
+type DeploymentConfigChange struct {
+	// Category groups the change by what it affects.
+	Category string
+	// Field is the path of the field which differs.
+	Field string
+	// From is the value of the field in the earlier revision, empty if it is not set.
+	From string
+	// To is the value of the field in the later revision, empty if it is not set.
+	To string
 }
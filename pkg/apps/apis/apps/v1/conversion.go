@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"strings"
 
+	kappsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -174,6 +175,30 @@ func Convert_url_Values_To_v1_DeploymentLogOptions(in *url.Values, out *v1.Deplo
 		}
 	}
 
+	if values, ok := map[string][]string(*in)["hook"]; ok && len(values) > 0 {
+		hook := ""
+		if err := runtime.Convert_Slice_string_To_string(&values, &hook, s); err != nil {
+			return err
+		}
+		out.Hook = v1.DeploymentLogHook(hook)
+	}
+	if values, ok := map[string][]string(*in)["allPods"]; ok && len(values) > 0 {
+		if err := runtime.Convert_Slice_string_To_bool(&values, &out.AllPods, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Convert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion sets the
+// type of the converted deployment, which the internal deployment does not carry, so
+// that it can be created as is.
+func Convert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion(in *newer.DeploymentConfigConversion, out *v1.DeploymentConfigConversion, s conversion.Scope) error {
+	if err := autoConvert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion(in, out, s); err != nil {
+		return err
+	}
+	out.Deployment.TypeMeta = metav1.TypeMeta{APIVersion: kappsv1.SchemeGroupVersion.String(), Kind: "Deployment"}
 	return nil
 }
 
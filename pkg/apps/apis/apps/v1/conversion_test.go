@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"net/url"
 	"reflect"
 	"testing"
 
@@ -232,3 +233,39 @@ func newInt32(val int32) *int32 {
 func newIntOrString(ios intstr.IntOrString) *intstr.IntOrString {
 	return &ios
 }
+
+func TestDeploymentLogOptionsSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected newer.DeploymentLogOptions
+	}{
+		{
+			name:     "container",
+			query:    url.Values{"container": {"app"}, "follow": {"true"}},
+			expected: newer.DeploymentLogOptions{Container: "app", Follow: true},
+		},
+		{
+			name:     "hook",
+			query:    url.Values{"hook": {"pre"}},
+			expected: newer.DeploymentLogOptions{Hook: newer.DeploymentLogHookPre},
+		},
+		{
+			name:     "all pods",
+			query:    url.Values{"allPods": {"true"}, "version": {"2"}},
+			expected: newer.DeploymentLogOptions{AllPods: true, Version: func(v int64) *int64 { return &v }(2)},
+		},
+	}
+	codec := runtime.NewParameterCodec(scheme)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &newer.DeploymentLogOptions{}
+			if err := codec.DecodeParameters(test.query, v1.GroupVersion, out); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*out, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, *out)
+			}
+		})
+	}
+}
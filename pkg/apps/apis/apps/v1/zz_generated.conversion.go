@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	apisappsv1 "k8s.io/kubernetes/pkg/apis/apps/v1"
 	core "k8s.io/kubernetes/pkg/apis/core"
 	corev1 "k8s.io/kubernetes/pkg/apis/core/v1"
 )
@@ -56,6 +57,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentCauseRollbackParams)(nil), (*apps.DeploymentCauseRollbackParams)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentCauseRollbackParams_To_apps_DeploymentCauseRollbackParams(a.(*v1.DeploymentCauseRollbackParams), b.(*apps.DeploymentCauseRollbackParams), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentCauseRollbackParams)(nil), (*v1.DeploymentCauseRollbackParams)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentCauseRollbackParams_To_v1_DeploymentCauseRollbackParams(a.(*apps.DeploymentCauseRollbackParams), b.(*v1.DeploymentCauseRollbackParams), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.DeploymentCondition)(nil), (*apps.DeploymentCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_DeploymentCondition_To_apps_DeploymentCondition(a.(*v1.DeploymentCondition), b.(*apps.DeploymentCondition), scope)
 	}); err != nil {
@@ -76,6 +87,41 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigChange)(nil), (*apps.DeploymentConfigChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigChange_To_apps_DeploymentConfigChange(a.(*v1.DeploymentConfigChange), b.(*apps.DeploymentConfigChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentConfigChange)(nil), (*v1.DeploymentConfigChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigChange_To_v1_DeploymentConfigChange(a.(*apps.DeploymentConfigChange), b.(*v1.DeploymentConfigChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigConversion)(nil), (*apps.DeploymentConfigConversion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion(a.(*v1.DeploymentConfigConversion), b.(*apps.DeploymentConfigConversion), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigConversionIssue)(nil), (*apps.DeploymentConfigConversionIssue)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigConversionIssue_To_apps_DeploymentConfigConversionIssue(a.(*v1.DeploymentConfigConversionIssue), b.(*apps.DeploymentConfigConversionIssue), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentConfigConversionIssue)(nil), (*v1.DeploymentConfigConversionIssue)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigConversionIssue_To_v1_DeploymentConfigConversionIssue(a.(*apps.DeploymentConfigConversionIssue), b.(*v1.DeploymentConfigConversionIssue), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigHistory)(nil), (*apps.DeploymentConfigHistory)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory(a.(*v1.DeploymentConfigHistory), b.(*apps.DeploymentConfigHistory), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentConfigHistory)(nil), (*v1.DeploymentConfigHistory)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigHistory_To_v1_DeploymentConfigHistory(a.(*apps.DeploymentConfigHistory), b.(*v1.DeploymentConfigHistory), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigList)(nil), (*apps.DeploymentConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_DeploymentConfigList_To_apps_DeploymentConfigList(a.(*v1.DeploymentConfigList), b.(*apps.DeploymentConfigList), scope)
 	}); err != nil {
@@ -86,6 +132,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigRevision)(nil), (*apps.DeploymentConfigRevision)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision(a.(*v1.DeploymentConfigRevision), b.(*apps.DeploymentConfigRevision), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentConfigRevision)(nil), (*v1.DeploymentConfigRevision)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision(a.(*apps.DeploymentConfigRevision), b.(*v1.DeploymentConfigRevision), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigRollback)(nil), (*apps.DeploymentConfigRollback)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_DeploymentConfigRollback_To_apps_DeploymentConfigRollback(a.(*v1.DeploymentConfigRollback), b.(*apps.DeploymentConfigRollback), scope)
 	}); err != nil {
@@ -96,6 +152,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigRollbackPreview)(nil), (*apps.DeploymentConfigRollbackPreview)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentConfigRollbackPreview_To_apps_DeploymentConfigRollbackPreview(a.(*v1.DeploymentConfigRollbackPreview), b.(*apps.DeploymentConfigRollbackPreview), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentConfigRollbackPreview)(nil), (*v1.DeploymentConfigRollbackPreview)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigRollbackPreview_To_v1_DeploymentConfigRollbackPreview(a.(*apps.DeploymentConfigRollbackPreview), b.(*v1.DeploymentConfigRollbackPreview), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.DeploymentConfigRollbackSpec)(nil), (*apps.DeploymentConfigRollbackSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_DeploymentConfigRollbackSpec_To_apps_DeploymentConfigRollbackSpec(a.(*v1.DeploymentConfigRollbackSpec), b.(*apps.DeploymentConfigRollbackSpec), scope)
 	}); err != nil {
@@ -136,6 +202,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.DeploymentImageOverride)(nil), (*apps.DeploymentImageOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_DeploymentImageOverride_To_apps_DeploymentImageOverride(a.(*v1.DeploymentImageOverride), b.(*apps.DeploymentImageOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apps.DeploymentImageOverride)(nil), (*v1.DeploymentImageOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentImageOverride_To_v1_DeploymentImageOverride(a.(*apps.DeploymentImageOverride), b.(*v1.DeploymentImageOverride), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.DeploymentLog)(nil), (*apps.DeploymentLog)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_DeploymentLog_To_apps_DeploymentLog(a.(*v1.DeploymentLog), b.(*apps.DeploymentLog), scope)
 	}); err != nil {
@@ -226,6 +302,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*apps.DeploymentConfigConversion)(nil), (*v1.DeploymentConfigConversion)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion(a.(*apps.DeploymentConfigConversion), b.(*v1.DeploymentConfigConversion), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*apps.DeploymentTriggerImageChangeParams)(nil), (*v1.DeploymentTriggerImageChangeParams)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_apps_DeploymentTriggerImageChangeParams_To_v1_DeploymentTriggerImageChangeParams(a.(*apps.DeploymentTriggerImageChangeParams), b.(*v1.DeploymentTriggerImageChangeParams), scope)
 	}); err != nil {
@@ -309,6 +390,7 @@ func autoConvert_v1_DeploymentCause_To_apps_DeploymentCause(in *v1.DeploymentCau
 	} else {
 		out.ImageTrigger = nil
 	}
+	out.RollbackParams = (*apps.DeploymentCauseRollbackParams)(unsafe.Pointer(in.RollbackParams))
 	return nil
 }
 
@@ -328,6 +410,7 @@ func autoConvert_apps_DeploymentCause_To_v1_DeploymentCause(in *apps.DeploymentC
 	} else {
 		out.ImageTrigger = nil
 	}
+	out.RollbackParams = (*v1.DeploymentCauseRollbackParams)(unsafe.Pointer(in.RollbackParams))
 	return nil
 }
 
@@ -360,6 +443,30 @@ func Convert_apps_DeploymentCauseImageTrigger_To_v1_DeploymentCauseImageTrigger(
 	return autoConvert_apps_DeploymentCauseImageTrigger_To_v1_DeploymentCauseImageTrigger(in, out, s)
 }
 
+func autoConvert_v1_DeploymentCauseRollbackParams_To_apps_DeploymentCauseRollbackParams(in *v1.DeploymentCauseRollbackParams, out *apps.DeploymentCauseRollbackParams, s conversion.Scope) error {
+	out.FromRevision = in.FromRevision
+	out.ToRevision = in.ToRevision
+	out.User = in.User
+	return nil
+}
+
+// Convert_v1_DeploymentCauseRollbackParams_To_apps_DeploymentCauseRollbackParams is an autogenerated conversion function.
+func Convert_v1_DeploymentCauseRollbackParams_To_apps_DeploymentCauseRollbackParams(in *v1.DeploymentCauseRollbackParams, out *apps.DeploymentCauseRollbackParams, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentCauseRollbackParams_To_apps_DeploymentCauseRollbackParams(in, out, s)
+}
+
+func autoConvert_apps_DeploymentCauseRollbackParams_To_v1_DeploymentCauseRollbackParams(in *apps.DeploymentCauseRollbackParams, out *v1.DeploymentCauseRollbackParams, s conversion.Scope) error {
+	out.FromRevision = in.FromRevision
+	out.ToRevision = in.ToRevision
+	out.User = in.User
+	return nil
+}
+
+// Convert_apps_DeploymentCauseRollbackParams_To_v1_DeploymentCauseRollbackParams is an autogenerated conversion function.
+func Convert_apps_DeploymentCauseRollbackParams_To_v1_DeploymentCauseRollbackParams(in *apps.DeploymentCauseRollbackParams, out *v1.DeploymentCauseRollbackParams, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentCauseRollbackParams_To_v1_DeploymentCauseRollbackParams(in, out, s)
+}
+
 func autoConvert_v1_DeploymentCondition_To_apps_DeploymentCondition(in *v1.DeploymentCondition, out *apps.DeploymentCondition, s conversion.Scope) error {
 	out.Type = apps.DeploymentConditionType(in.Type)
 	out.Status = core.ConditionStatus(in.Status)
@@ -422,6 +529,119 @@ func Convert_apps_DeploymentConfig_To_v1_DeploymentConfig(in *apps.DeploymentCon
 	return autoConvert_apps_DeploymentConfig_To_v1_DeploymentConfig(in, out, s)
 }
 
+func autoConvert_v1_DeploymentConfigChange_To_apps_DeploymentConfigChange(in *v1.DeploymentConfigChange, out *apps.DeploymentConfigChange, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Field = in.Field
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_v1_DeploymentConfigChange_To_apps_DeploymentConfigChange is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigChange_To_apps_DeploymentConfigChange(in *v1.DeploymentConfigChange, out *apps.DeploymentConfigChange, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigChange_To_apps_DeploymentConfigChange(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigChange_To_v1_DeploymentConfigChange(in *apps.DeploymentConfigChange, out *v1.DeploymentConfigChange, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Field = in.Field
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_apps_DeploymentConfigChange_To_v1_DeploymentConfigChange is an autogenerated conversion function.
+func Convert_apps_DeploymentConfigChange_To_v1_DeploymentConfigChange(in *apps.DeploymentConfigChange, out *v1.DeploymentConfigChange, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentConfigChange_To_v1_DeploymentConfigChange(in, out, s)
+}
+
+func autoConvert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion(in *v1.DeploymentConfigConversion, out *apps.DeploymentConfigConversion, s conversion.Scope) error {
+	if err := apisappsv1.Convert_v1_Deployment_To_apps_Deployment(&in.Deployment, &out.Deployment, s); err != nil {
+		return err
+	}
+	out.Issues = *(*[]apps.DeploymentConfigConversionIssue)(unsafe.Pointer(&in.Issues))
+	return nil
+}
+
+// Convert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion(in *v1.DeploymentConfigConversion, out *apps.DeploymentConfigConversion, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion(in *apps.DeploymentConfigConversion, out *v1.DeploymentConfigConversion, s conversion.Scope) error {
+	if err := apisappsv1.Convert_apps_Deployment_To_v1_Deployment(&in.Deployment, &out.Deployment, s); err != nil {
+		return err
+	}
+	out.Issues = *(*[]v1.DeploymentConfigConversionIssue)(unsafe.Pointer(&in.Issues))
+	return nil
+}
+
+func autoConvert_v1_DeploymentConfigConversionIssue_To_apps_DeploymentConfigConversionIssue(in *v1.DeploymentConfigConversionIssue, out *apps.DeploymentConfigConversionIssue, s conversion.Scope) error {
+	out.Field = in.Field
+	out.Severity = apps.DeploymentConfigConversionIssueSeverity(in.Severity)
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1_DeploymentConfigConversionIssue_To_apps_DeploymentConfigConversionIssue is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigConversionIssue_To_apps_DeploymentConfigConversionIssue(in *v1.DeploymentConfigConversionIssue, out *apps.DeploymentConfigConversionIssue, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigConversionIssue_To_apps_DeploymentConfigConversionIssue(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigConversionIssue_To_v1_DeploymentConfigConversionIssue(in *apps.DeploymentConfigConversionIssue, out *v1.DeploymentConfigConversionIssue, s conversion.Scope) error {
+	out.Field = in.Field
+	out.Severity = v1.DeploymentConfigConversionIssueSeverity(in.Severity)
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_apps_DeploymentConfigConversionIssue_To_v1_DeploymentConfigConversionIssue is an autogenerated conversion function.
+func Convert_apps_DeploymentConfigConversionIssue_To_v1_DeploymentConfigConversionIssue(in *apps.DeploymentConfigConversionIssue, out *v1.DeploymentConfigConversionIssue, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentConfigConversionIssue_To_v1_DeploymentConfigConversionIssue(in, out, s)
+}
+
+func autoConvert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory(in *v1.DeploymentConfigHistory, out *apps.DeploymentConfigHistory, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]apps.DeploymentConfigRevision, len(*in))
+		for i := range *in {
+			if err := Convert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Revisions = nil
+	}
+	return nil
+}
+
+// Convert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory(in *v1.DeploymentConfigHistory, out *apps.DeploymentConfigHistory, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigHistory_To_v1_DeploymentConfigHistory(in *apps.DeploymentConfigHistory, out *v1.DeploymentConfigHistory, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]v1.DeploymentConfigRevision, len(*in))
+		for i := range *in {
+			if err := Convert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Revisions = nil
+	}
+	return nil
+}
+
+// Convert_apps_DeploymentConfigHistory_To_v1_DeploymentConfigHistory is an autogenerated conversion function.
+func Convert_apps_DeploymentConfigHistory_To_v1_DeploymentConfigHistory(in *apps.DeploymentConfigHistory, out *v1.DeploymentConfigHistory, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentConfigHistory_To_v1_DeploymentConfigHistory(in, out, s)
+}
+
 func autoConvert_v1_DeploymentConfigList_To_apps_DeploymentConfigList(in *v1.DeploymentConfigList, out *apps.DeploymentConfigList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -464,6 +684,78 @@ func Convert_apps_DeploymentConfigList_To_v1_DeploymentConfigList(in *apps.Deplo
 	return autoConvert_apps_DeploymentConfigList_To_v1_DeploymentConfigList(in, out, s)
 }
 
+func autoConvert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision(in *v1.DeploymentConfigRevision, out *apps.DeploymentConfigRevision, s conversion.Scope) error {
+	out.Revision = in.Revision
+	out.Deployment = in.Deployment
+	out.Status = string(in.Status)
+	out.StatusReason = in.StatusReason
+	out.Cancelled = in.Cancelled
+	out.Replicas = in.Replicas
+	out.CreationTimestamp = in.CreationTimestamp
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = new(apps.DeploymentDetails)
+		if err := Convert_v1_DeploymentDetails_To_apps_DeploymentDetails(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Details = nil
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(core.PodTemplateSpec)
+		if err := corev1.Convert_v1_PodTemplateSpec_To_core_PodTemplateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Template = nil
+	}
+	out.Changes = *(*[]apps.DeploymentConfigChange)(unsafe.Pointer(&in.Changes))
+	out.Error = in.Error
+	return nil
+}
+
+// Convert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision(in *v1.DeploymentConfigRevision, out *apps.DeploymentConfigRevision, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigRevision_To_apps_DeploymentConfigRevision(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision(in *apps.DeploymentConfigRevision, out *v1.DeploymentConfigRevision, s conversion.Scope) error {
+	out.Revision = in.Revision
+	out.Deployment = in.Deployment
+	out.Status = v1.DeploymentStatus(in.Status)
+	out.StatusReason = in.StatusReason
+	out.Cancelled = in.Cancelled
+	out.Replicas = in.Replicas
+	out.CreationTimestamp = in.CreationTimestamp
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = new(v1.DeploymentDetails)
+		if err := Convert_apps_DeploymentDetails_To_v1_DeploymentDetails(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Details = nil
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(apicorev1.PodTemplateSpec)
+		if err := corev1.Convert_core_PodTemplateSpec_To_v1_PodTemplateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Template = nil
+	}
+	out.Changes = *(*[]v1.DeploymentConfigChange)(unsafe.Pointer(&in.Changes))
+	out.Error = in.Error
+	return nil
+}
+
+// Convert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision is an autogenerated conversion function.
+func Convert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision(in *apps.DeploymentConfigRevision, out *v1.DeploymentConfigRevision, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentConfigRevision_To_v1_DeploymentConfigRevision(in, out, s)
+}
+
 func autoConvert_v1_DeploymentConfigRollback_To_apps_DeploymentConfigRollback(in *v1.DeploymentConfigRollback, out *apps.DeploymentConfigRollback, s conversion.Scope) error {
 	out.Name = in.Name
 	out.UpdatedAnnotations = *(*map[string]string)(unsafe.Pointer(&in.UpdatedAnnotations))
@@ -492,6 +784,34 @@ func Convert_apps_DeploymentConfigRollback_To_v1_DeploymentConfigRollback(in *ap
 	return autoConvert_apps_DeploymentConfigRollback_To_v1_DeploymentConfigRollback(in, out, s)
 }
 
+func autoConvert_v1_DeploymentConfigRollbackPreview_To_apps_DeploymentConfigRollbackPreview(in *v1.DeploymentConfigRollbackPreview, out *apps.DeploymentConfigRollbackPreview, s conversion.Scope) error {
+	if err := Convert_v1_DeploymentConfig_To_apps_DeploymentConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	out.Changes = *(*[]apps.DeploymentConfigChange)(unsafe.Pointer(&in.Changes))
+	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
+	return nil
+}
+
+// Convert_v1_DeploymentConfigRollbackPreview_To_apps_DeploymentConfigRollbackPreview is an autogenerated conversion function.
+func Convert_v1_DeploymentConfigRollbackPreview_To_apps_DeploymentConfigRollbackPreview(in *v1.DeploymentConfigRollbackPreview, out *apps.DeploymentConfigRollbackPreview, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentConfigRollbackPreview_To_apps_DeploymentConfigRollbackPreview(in, out, s)
+}
+
+func autoConvert_apps_DeploymentConfigRollbackPreview_To_v1_DeploymentConfigRollbackPreview(in *apps.DeploymentConfigRollbackPreview, out *v1.DeploymentConfigRollbackPreview, s conversion.Scope) error {
+	if err := Convert_apps_DeploymentConfig_To_v1_DeploymentConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	out.Changes = *(*[]v1.DeploymentConfigChange)(unsafe.Pointer(&in.Changes))
+	out.Warnings = *(*[]string)(unsafe.Pointer(&in.Warnings))
+	return nil
+}
+
+// Convert_apps_DeploymentConfigRollbackPreview_To_v1_DeploymentConfigRollbackPreview is an autogenerated conversion function.
+func Convert_apps_DeploymentConfigRollbackPreview_To_v1_DeploymentConfigRollbackPreview(in *apps.DeploymentConfigRollbackPreview, out *v1.DeploymentConfigRollbackPreview, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentConfigRollbackPreview_To_v1_DeploymentConfigRollbackPreview(in, out, s)
+}
+
 func autoConvert_v1_DeploymentConfigRollbackSpec_To_apps_DeploymentConfigRollbackSpec(in *v1.DeploymentConfigRollbackSpec, out *apps.DeploymentConfigRollbackSpec, s conversion.Scope) error {
 	if err := corev1.Convert_v1_ObjectReference_To_core_ObjectReference(&in.From, &out.From, s); err != nil {
 		return err
@@ -501,6 +821,8 @@ func autoConvert_v1_DeploymentConfigRollbackSpec_To_apps_DeploymentConfigRollbac
 	out.IncludeTemplate = in.IncludeTemplate
 	out.IncludeReplicationMeta = in.IncludeReplicationMeta
 	out.IncludeStrategy = in.IncludeStrategy
+	out.KeepImageTriggers = in.KeepImageTriggers
+	out.Rollout = in.Rollout
 	return nil
 }
 
@@ -518,6 +840,8 @@ func autoConvert_apps_DeploymentConfigRollbackSpec_To_v1_DeploymentConfigRollbac
 	out.IncludeTemplate = in.IncludeTemplate
 	out.IncludeReplicationMeta = in.IncludeReplicationMeta
 	out.IncludeStrategy = in.IncludeStrategy
+	out.KeepImageTriggers = in.KeepImageTriggers
+	out.Rollout = in.Rollout
 	return nil
 }
 
@@ -556,6 +880,7 @@ func autoConvert_v1_DeploymentConfigSpec_To_apps_DeploymentConfigSpec(in *v1.Dep
 	} else {
 		out.Template = nil
 	}
+	out.AutoRollback = in.AutoRollback
 	return nil
 }
 
@@ -594,6 +919,7 @@ func autoConvert_apps_DeploymentConfigSpec_To_v1_DeploymentConfigSpec(in *apps.D
 	} else {
 		out.Template = nil
 	}
+	out.AutoRollback = in.AutoRollback
 	return nil
 }
 
@@ -696,6 +1022,28 @@ func Convert_apps_DeploymentDetails_To_v1_DeploymentDetails(in *apps.DeploymentD
 	return autoConvert_apps_DeploymentDetails_To_v1_DeploymentDetails(in, out, s)
 }
 
+func autoConvert_v1_DeploymentImageOverride_To_apps_DeploymentImageOverride(in *v1.DeploymentImageOverride, out *apps.DeploymentImageOverride, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_v1_DeploymentImageOverride_To_apps_DeploymentImageOverride is an autogenerated conversion function.
+func Convert_v1_DeploymentImageOverride_To_apps_DeploymentImageOverride(in *v1.DeploymentImageOverride, out *apps.DeploymentImageOverride, s conversion.Scope) error {
+	return autoConvert_v1_DeploymentImageOverride_To_apps_DeploymentImageOverride(in, out, s)
+}
+
+func autoConvert_apps_DeploymentImageOverride_To_v1_DeploymentImageOverride(in *apps.DeploymentImageOverride, out *v1.DeploymentImageOverride, s conversion.Scope) error {
+	out.ContainerName = in.ContainerName
+	out.Image = in.Image
+	return nil
+}
+
+// Convert_apps_DeploymentImageOverride_To_v1_DeploymentImageOverride is an autogenerated conversion function.
+func Convert_apps_DeploymentImageOverride_To_v1_DeploymentImageOverride(in *apps.DeploymentImageOverride, out *v1.DeploymentImageOverride, s conversion.Scope) error {
+	return autoConvert_apps_DeploymentImageOverride_To_v1_DeploymentImageOverride(in, out, s)
+}
+
 func autoConvert_v1_DeploymentLog_To_apps_DeploymentLog(in *v1.DeploymentLog, out *apps.DeploymentLog, s conversion.Scope) error {
 	return nil
 }
@@ -725,6 +1073,8 @@ func autoConvert_v1_DeploymentLogOptions_To_apps_DeploymentLogOptions(in *v1.Dep
 	out.LimitBytes = (*int64)(unsafe.Pointer(in.LimitBytes))
 	out.NoWait = in.NoWait
 	out.Version = (*int64)(unsafe.Pointer(in.Version))
+	out.Hook = apps.DeploymentLogHook(in.Hook)
+	out.AllPods = in.AllPods
 	return nil
 }
 
@@ -744,6 +1094,8 @@ func autoConvert_apps_DeploymentLogOptions_To_v1_DeploymentLogOptions(in *apps.D
 	out.LimitBytes = (*int64)(unsafe.Pointer(in.LimitBytes))
 	out.NoWait = in.NoWait
 	out.Version = (*int64)(unsafe.Pointer(in.Version))
+	out.Hook = v1.DeploymentLogHook(in.Hook)
+	out.AllPods = in.AllPods
 	return nil
 }
 
@@ -757,6 +1109,7 @@ func autoConvert_v1_DeploymentRequest_To_apps_DeploymentRequest(in *v1.Deploymen
 	out.Latest = in.Latest
 	out.Force = in.Force
 	out.ExcludeTriggers = *(*[]apps.DeploymentTriggerType)(unsafe.Pointer(&in.ExcludeTriggers))
+	out.ImageOverrides = *(*[]apps.DeploymentImageOverride)(unsafe.Pointer(&in.ImageOverrides))
 	return nil
 }
 
@@ -770,6 +1123,7 @@ func autoConvert_apps_DeploymentRequest_To_v1_DeploymentRequest(in *apps.Deploym
 	out.Latest = in.Latest
 	out.Force = in.Force
 	out.ExcludeTriggers = *(*[]v1.DeploymentTriggerType)(unsafe.Pointer(&in.ExcludeTriggers))
+	out.ImageOverrides = *(*[]v1.DeploymentImageOverride)(unsafe.Pointer(&in.ImageOverrides))
 	return nil
 }
 
@@ -563,6 +563,21 @@ func ValidateDeploymentRequest(req *appsapi.DeploymentRequest) field.ErrorList {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("name"), req.Name, "name of the deployment config is invalid"))
 	}
 
+	containers := sets.NewString()
+	for i, override := range req.ImageOverrides {
+		overridePath := field.NewPath("imageOverrides").Index(i)
+		switch {
+		case len(override.ContainerName) == 0:
+			allErrs = append(allErrs, field.Required(overridePath.Child("containerName"), ""))
+		case containers.Has(override.ContainerName):
+			allErrs = append(allErrs, field.Duplicate(overridePath.Child("containerName"), override.ContainerName))
+		}
+		containers.Insert(override.ContainerName)
+		if len(override.Image) == 0 {
+			allErrs = append(allErrs, field.Required(overridePath.Child("image"), ""))
+		}
+	}
+
 	return allErrs
 }
 
@@ -595,5 +610,17 @@ func ValidateDeploymentLogOptions(opts *appsapi.DeploymentLogOptions) field.Erro
 		allErrs = append(allErrs, field.Invalid(field.NewPath("previous"), opts.Previous, "cannot use previous when a version is specified"))
 	}
 
+	switch opts.Hook {
+	case "", appsapi.DeploymentLogHookPre, appsapi.DeploymentLogHookMid, appsapi.DeploymentLogHookPost:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("hook"), opts.Hook, []string{string(appsapi.DeploymentLogHookPre), string(appsapi.DeploymentLogHookMid), string(appsapi.DeploymentLogHookPost)}))
+	}
+	if opts.AllPods && len(opts.Hook) > 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("allPods"), opts.AllPods, "cannot select a hook when all pods are selected"))
+	}
+	if opts.AllPods && len(opts.Container) > 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("allPods"), opts.AllPods, "cannot select a container when all pods are selected"))
+	}
+
 	return allErrs
 }
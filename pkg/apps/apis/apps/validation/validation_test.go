@@ -961,3 +961,60 @@ func TestValidateSelectorMatchesPodTemplateLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateDeploymentLogOptionsSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   appsapi.DeploymentLogOptions
+		errors []string
+	}{
+		{name: "hook", opts: appsapi.DeploymentLogOptions{Hook: appsapi.DeploymentLogHookPost}},
+		{name: "all pods", opts: appsapi.DeploymentLogOptions{AllPods: true, Follow: true}},
+		{name: "unknown hook", opts: appsapi.DeploymentLogOptions{Hook: "pre-start"}, errors: []string{"hook"}},
+		{name: "hook and all pods", opts: appsapi.DeploymentLogOptions{Hook: appsapi.DeploymentLogHookPre, AllPods: true}, errors: []string{"allPods"}},
+		{name: "container and all pods", opts: appsapi.DeploymentLogOptions{Container: "app", AllPods: true}, errors: []string{"allPods"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateDeploymentLogOptions(&test.opts)
+			if len(errs) != len(test.errors) {
+				t.Fatalf("expected errors for %v, got %v", test.errors, errs)
+			}
+			for i := range errs {
+				if errs[i].Field != test.errors[i] {
+					t.Errorf("expected error for %s, got %v", test.errors[i], errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDeploymentRequestImageOverrides(t *testing.T) {
+	image := "registry/ns/app@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	tests := []struct {
+		name      string
+		overrides []appsapi.DeploymentImageOverride
+		errors    []string
+	}{
+		{name: "valid", overrides: []appsapi.DeploymentImageOverride{{ContainerName: "app", Image: image}, {ContainerName: "sidecar", Image: image}}},
+		{name: "missing fields", overrides: []appsapi.DeploymentImageOverride{{}}, errors: []string{"imageOverrides[0].containerName", "imageOverrides[0].image"}},
+		{name: "duplicate container", overrides: []appsapi.DeploymentImageOverride{{ContainerName: "app", Image: image}, {ContainerName: "app", Image: image}}, errors: []string{"imageOverrides[1].containerName"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := ValidateDeploymentRequest(&appsapi.DeploymentRequest{Name: "config", ImageOverrides: test.overrides})
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if len(fields) != len(test.errors) {
+				t.Fatalf("expected errors for %v, got %v", test.errors, errs)
+			}
+			for i := range fields {
+				if fields[i] != test.errors[i] {
+					t.Errorf("expected error for %s, got %v", test.errors[i], errs[i])
+				}
+			}
+		})
+	}
+}
@@ -46,6 +46,11 @@ func (in *DeploymentCause) DeepCopyInto(out *DeploymentCause) {
 		*out = new(DeploymentCauseImageTrigger)
 		**out = **in
 	}
+	if in.RollbackParams != nil {
+		in, out := &in.RollbackParams, &out.RollbackParams
+		*out = new(DeploymentCauseRollbackParams)
+		**out = **in
+	}
 	return
 }
 
@@ -76,6 +81,22 @@ func (in *DeploymentCauseImageTrigger) DeepCopy() *DeploymentCauseImageTrigger {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentCauseRollbackParams) DeepCopyInto(out *DeploymentCauseRollbackParams) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentCauseRollbackParams.
+func (in *DeploymentCauseRollbackParams) DeepCopy() *DeploymentCauseRollbackParams {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentCauseRollbackParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentCondition) DeepCopyInto(out *DeploymentCondition) {
 	*out = *in
@@ -122,6 +143,102 @@ func (in *DeploymentConfig) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigChange) DeepCopyInto(out *DeploymentConfigChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigChange.
+func (in *DeploymentConfigChange) DeepCopy() *DeploymentConfigChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigConversion) DeepCopyInto(out *DeploymentConfigConversion) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Deployment.DeepCopyInto(&out.Deployment)
+	if in.Issues != nil {
+		in, out := &in.Issues, &out.Issues
+		*out = make([]DeploymentConfigConversionIssue, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigConversion.
+func (in *DeploymentConfigConversion) DeepCopy() *DeploymentConfigConversion {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigConversion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeploymentConfigConversion) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigConversionIssue) DeepCopyInto(out *DeploymentConfigConversionIssue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigConversionIssue.
+func (in *DeploymentConfigConversionIssue) DeepCopy() *DeploymentConfigConversionIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigConversionIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigHistory) DeepCopyInto(out *DeploymentConfigHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]DeploymentConfigRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigHistory.
+func (in *DeploymentConfigHistory) DeepCopy() *DeploymentConfigHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeploymentConfigHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentConfigList) DeepCopyInto(out *DeploymentConfigList) {
 	*out = *in
@@ -155,6 +272,38 @@ func (in *DeploymentConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigRevision) DeepCopyInto(out *DeploymentConfigRevision) {
+	*out = *in
+	in.CreationTimestamp.DeepCopyInto(&out.CreationTimestamp)
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = new(DeploymentDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(core.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]DeploymentConfigChange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigRevision.
+func (in *DeploymentConfigRevision) DeepCopy() *DeploymentConfigRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentConfigRollback) DeepCopyInto(out *DeploymentConfigRollback) {
 	*out = *in
@@ -188,6 +337,42 @@ func (in *DeploymentConfigRollback) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentConfigRollbackPreview) DeepCopyInto(out *DeploymentConfigRollbackPreview) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Config.DeepCopyInto(&out.Config)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]DeploymentConfigChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentConfigRollbackPreview.
+func (in *DeploymentConfigRollbackPreview) DeepCopy() *DeploymentConfigRollbackPreview {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentConfigRollbackPreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeploymentConfigRollbackPreview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentConfigRollbackSpec) DeepCopyInto(out *DeploymentConfigRollbackSpec) {
 	*out = *in
@@ -297,6 +482,22 @@ func (in *DeploymentDetails) DeepCopy() *DeploymentDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentImageOverride) DeepCopyInto(out *DeploymentImageOverride) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentImageOverride.
+func (in *DeploymentImageOverride) DeepCopy() *DeploymentImageOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentImageOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentLog) DeepCopyInto(out *DeploymentLog) {
 	*out = *in
@@ -380,6 +581,11 @@ func (in *DeploymentRequest) DeepCopyInto(out *DeploymentRequest) {
 		*out = make([]DeploymentTriggerType, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make([]DeploymentImageOverride, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
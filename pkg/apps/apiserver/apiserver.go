@@ -1,6 +1,8 @@
 package apiserver
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -11,16 +13,25 @@ import (
 	restclient "k8s.io/client-go/rest"
 
 	appsv1 "github.com/openshift/api/apps/v1"
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
 	appsclient "github.com/openshift/client-go/apps/clientset/versioned"
 	imagev1client "github.com/openshift/client-go/image/clientset/versioned"
 	deployconfigetcd "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/deployconfig/etcd"
+	deployconvertregistry "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/deployconvert"
+	deployhistoryregistry "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/deployhistory"
 	deploylogregistry "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/deploylog"
 	deployconfiginstantiate "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/instantiate"
 	deployrollback "github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/rollback"
+	"github.com/openshift/openshift-apiserver/pkg/image/apis/image/validation/whitelist"
+	"github.com/openshift/openshift-apiserver/pkg/image/apiserver/registryhostname"
 )
 
 type ExtraConfig struct {
 	KubeAPIServerClientConfig *restclient.Config
+	// RegistryHostnameRetriever and AllowedRegistriesForImport restrict the images
+	// deployment configs can be instantiated with.
+	RegistryHostnameRetriever  registryhostname.RegistryHostnameRetriever
+	AllowedRegistriesForImport openshiftcontrolplanev1.AllowedRegistries
 
 	// TODO these should all become local eventually
 	Scheme *runtime.Scheme
@@ -29,6 +40,7 @@ type ExtraConfig struct {
 	makeV1Storage sync.Once
 	v1Storage     map[string]rest.Storage
 	v1StorageErr  error
+	startFns      []func(<-chan struct{})
 }
 
 type AppsServerConfig struct {
@@ -84,6 +96,15 @@ func (c completedConfig) New(delegationTarget genericapiserver.DelegationTarget)
 		return nil, err
 	}
 
+	if err := s.GenericAPIServer.AddPostStartHook("apps.openshift.io-apiserver-controllers", func(context genericapiserver.PostStartHookContext) error {
+		for _, fn := range c.ExtraConfig.startFns {
+			go fn(context.StopCh)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -114,13 +135,26 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 	if err != nil {
 		return nil, err
 	}
+	var whitelister whitelist.RegistryWhitelister
+	if len(c.ExtraConfig.AllowedRegistriesForImport) > 0 {
+		whitelister, err = whitelist.NewRegistryWhitelister(
+			c.ExtraConfig.AllowedRegistriesForImport,
+			c.ExtraConfig.RegistryHostnameRetriever)
+		if err != nil {
+			return nil, fmt.Errorf("error building registry whitelister: %v", err)
+		}
+	} else {
+		whitelister = whitelist.WhitelistAllRegistries(context.TODO())
+	}
 	dcInstantiateStorage := deployconfiginstantiate.NewREST(
 		*deployConfigStorage.Store,
 		openshiftImageClient,
 		kubeClient,
 		c.GenericConfig.AdmissionControl,
+		whitelister,
 	)
-	deployConfigRollbackStorage := deployrollback.NewREST(openshiftAppsClient, kubeClient)
+	deployConfigRollbackStorage := deployrollback.NewREST(openshiftAppsClient, kubeClient, openshiftImageClient, dcInstantiateStorage)
+	c.ExtraConfig.startFns = append(c.ExtraConfig.startFns, runAppsControllers(kubeClient, deployrollback.NewAutoRollbackController(deployConfigRollbackStorage)))
 
 	v1Storage := map[string]rest.Storage{}
 	v1Storage["deploymentconfigs"] = deployConfigStorage
@@ -129,5 +163,7 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 	v1Storage["deploymentconfigs/rollback"] = deployConfigRollbackStorage
 	v1Storage["deploymentconfigs/log"] = deploylogregistry.NewREST(openshiftAppsClient.AppsV1(), kubeClient)
 	v1Storage["deploymentconfigs/instantiate"] = dcInstantiateStorage
+	v1Storage["deploymentconfigs/history"] = deployhistoryregistry.NewREST(openshiftAppsClient.AppsV1(), kubeClient.CoreV1())
+	v1Storage["deploymentconfigs/convert"] = deployconvertregistry.NewREST(openshiftAppsClient.AppsV1())
 	return v1Storage, nil
 }
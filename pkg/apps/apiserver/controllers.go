@@ -0,0 +1,104 @@
+package apiserver
+
+import (
+	"context"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+const (
+	// appsControllersLeaseNamespace and appsControllersLeaseName identify the
+	// lease electing the apiserver instance which runs the apps controllers.
+	appsControllersLeaseNamespace = "openshift-apiserver"
+	appsControllersLeaseName      = "openshift-apiserver-apps-controllers"
+)
+
+// deploymentController is a controller reacting to changes of the deployments
+// of deployment configs. It runs until stopCh is closed and may be run again
+// with a new informer afterwards.
+type deploymentController interface {
+	Run(informer cache.SharedIndexInformer, stopCh <-chan struct{})
+}
+
+// runAppsControllers returns a start function running controllers in the
+// apiserver instance holding the apps controllers lease only, so that every
+// change of a deployment is acted upon once rather than once per instance. The
+// controllers share a single informer of the replication controllers owned by
+// deployment configs, which is recreated every time the lease is acquired.
+func runAppsControllers(kubeClient kubernetes.Interface, controllers ...deploymentController) func(<-chan struct{}) {
+	return func(stopCh <-chan struct{}) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "openshift-apiserver"
+		}
+		lock := &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: appsControllersLeaseNamespace,
+				Name:      appsControllersLeaseName,
+			},
+			Client: kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: hostname + "_" + string(uuid.NewUUID()),
+			},
+		}
+		ctx := wait.ContextForChannel(stopCh)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+				Lock:            lock,
+				Name:            appsControllersLeaseName,
+				LeaseDuration:   137 * time.Second,
+				RenewDeadline:   107 * time.Second,
+				RetryPeriod:     26 * time.Second,
+				ReleaseOnCancel: true,
+				Callbacks: leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(ctx context.Context) {
+						klog.Infof("Starting apps controllers")
+						informer := newDeploymentInformer(kubeClient)
+						for _, controller := range controllers {
+							go controller.Run(informer, ctx.Done())
+						}
+						informer.Run(ctx.Done())
+					},
+					OnStoppedLeading: func() {
+						klog.Infof("Stopped apps controllers")
+					},
+				},
+			})
+		}, time.Second)
+	}
+}
+
+func newDeploymentInformer(kubeClient kubernetes.Interface) cache.SharedIndexInformer {
+	selector := func(options *metav1.ListOptions) {
+		options.LabelSelector = appsv1.DeploymentConfigAnnotation
+	}
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				selector(&options)
+				return kubeClient.CoreV1().ReplicationControllers(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				selector(&options)
+				return kubeClient.CoreV1().ReplicationControllers(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		},
+		&corev1.ReplicationController{},
+		0,
+		cache.Indexers{},
+	)
+}
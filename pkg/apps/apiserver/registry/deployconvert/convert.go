@@ -0,0 +1,241 @@
+package deployconvert
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	corev1conversions "k8s.io/kubernetes/pkg/apis/core/v1"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+)
+
+// ImageTriggersAnnotation holds the image triggers of a deployment, which set the
+// image of a container to the image of an image stream tag when it changes.
+const ImageTriggersAnnotation = "image.openshift.io/triggers"
+
+// imageTrigger is an image trigger held by the ImageTriggersAnnotation.
+type imageTrigger struct {
+	From      imageTriggerSource `json:"from"`
+	FieldPath string             `json:"fieldPath"`
+	Paused    bool               `json:"paused,omitempty"`
+}
+
+// imageTriggerSource is the image stream tag an image trigger follows.
+type imageTriggerSource struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ToDeployment converts config to an equivalent apps/v1 deployment, returning the
+// issues with the fields of config that could not be converted exactly.
+func ToDeployment(config *appsapi.DeploymentConfig) (*kappsv1.Deployment, []appsv1.DeploymentConfigConversionIssue, error) {
+	if config.Spec.Template == nil {
+		return nil, nil, fmt.Errorf("deployment config %s/%s has no pod template", config.Namespace, config.Name)
+	}
+	c := &converter{}
+	specPath := field.NewPath("spec")
+
+	deployment := &kappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: kappsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.Name,
+			Namespace: config.Namespace,
+			Labels:    copyMap(config.Labels),
+		},
+		Spec: kappsv1.DeploymentSpec{
+			Replicas:             &config.Spec.Replicas,
+			MinReadySeconds:      config.Spec.MinReadySeconds,
+			RevisionHistoryLimit: config.Spec.RevisionHistoryLimit,
+			Paused:               config.Spec.Paused,
+		},
+	}
+	for key, value := range config.Annotations {
+		// The annotations of the apps.openshift.io group are only acted upon for
+		// deployment configs.
+		if strings.HasPrefix(key, appsv1.GroupName+"/") {
+			continue
+		}
+		if deployment.Annotations == nil {
+			deployment.Annotations = map[string]string{}
+		}
+		deployment.Annotations[key] = value
+	}
+
+	if err := corev1conversions.Convert_core_PodTemplateSpec_To_v1_PodTemplateSpec(config.Spec.Template, &deployment.Spec.Template, nil); err != nil {
+		return nil, nil, err
+	}
+	selector := config.Spec.Selector
+	if len(selector) == 0 {
+		selector = config.Spec.Template.Labels
+	}
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: copyMap(selector)}
+
+	if config.Spec.Test {
+		c.add(specPath.Child("test"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments cannot scale down to zero replicas after each rollout")
+	}
+	if config.Spec.AutoRollback {
+		c.add(specPath.Child("autoRollback"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments are not rolled back when a rollout fails")
+	}
+	c.strategy(&config.Spec.Strategy, &deployment.Spec, specPath.Child("strategy"))
+
+	if triggers := c.triggers(config, specPath.Child("triggers")); len(triggers) > 0 {
+		data, err := json.Marshal(triggers)
+		if err != nil {
+			return nil, nil, err
+		}
+		if deployment.Annotations == nil {
+			deployment.Annotations = map[string]string{}
+		}
+		deployment.Annotations[ImageTriggersAnnotation] = string(data)
+	}
+
+	sort.SliceStable(c.issues, func(i, j int) bool { return c.issues[i].Field < c.issues[j].Field })
+	return deployment, c.issues, nil
+}
+
+type converter struct {
+	issues []appsv1.DeploymentConfigConversionIssue
+}
+
+func (c *converter) add(fldPath *field.Path, severity appsv1.DeploymentConfigConversionIssueSeverity, message string) {
+	c.issues = append(c.issues, appsv1.DeploymentConfigConversionIssue{Field: fldPath.String(), Severity: severity, Message: message})
+}
+
+// strategy converts the strategy of a deployment config to the strategy of spec.
+func (c *converter) strategy(strategy *appsapi.DeploymentStrategy, spec *kappsv1.DeploymentSpec, fldPath *field.Path) {
+	var timeoutSeconds *int64
+	switch strategy.Type {
+	case appsapi.DeploymentStrategyTypeRolling:
+		spec.Strategy.Type = kappsv1.RollingUpdateDeploymentStrategyType
+		if params := strategy.RollingParams; params != nil {
+			paramsPath := fldPath.Child("rollingParams")
+			maxUnavailable, maxSurge := params.MaxUnavailable, params.MaxSurge
+			spec.Strategy.RollingUpdate = &kappsv1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge}
+			if params.UpdatePeriodSeconds != nil {
+				c.add(paramsPath.Child("updatePeriodSeconds"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments do not wait between pod updates, use minReadySeconds to slow down rollouts")
+			}
+			if params.IntervalSeconds != nil {
+				c.add(paramsPath.Child("intervalSeconds"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments have no deployer polling the rollout")
+			}
+			timeoutSeconds = params.TimeoutSeconds
+			c.hook(params.Pre, paramsPath.Child("pre"))
+			c.hook(params.Post, paramsPath.Child("post"))
+			if timeoutSeconds != nil {
+				c.add(paramsPath.Child("timeoutSeconds"), appsv1.DeploymentConfigConversionIssueLossy, "converted to progressDeadlineSeconds, which limits the time a rollout makes no progress rather than its duration")
+			}
+		}
+	case appsapi.DeploymentStrategyTypeRecreate:
+		spec.Strategy.Type = kappsv1.RecreateDeploymentStrategyType
+		if params := strategy.RecreateParams; params != nil {
+			paramsPath := fldPath.Child("recreateParams")
+			timeoutSeconds = params.TimeoutSeconds
+			c.hook(params.Pre, paramsPath.Child("pre"))
+			c.hook(params.Mid, paramsPath.Child("mid"))
+			c.hook(params.Post, paramsPath.Child("post"))
+			if timeoutSeconds != nil {
+				c.add(paramsPath.Child("timeoutSeconds"), appsv1.DeploymentConfigConversionIssueLossy, "converted to progressDeadlineSeconds, which limits the time a rollout makes no progress rather than its duration")
+			}
+		}
+	default:
+		spec.Strategy.Type = kappsv1.RollingUpdateDeploymentStrategyType
+		c.add(fldPath.Child("type"), appsv1.DeploymentConfigConversionIssueUnsupported, fmt.Sprintf("deployments have no %s strategy, the RollingUpdate strategy is used", strategy.Type))
+	}
+	if timeoutSeconds != nil && *timeoutSeconds > 0 {
+		progressDeadlineSeconds := int32(*timeoutSeconds)
+		spec.ProgressDeadlineSeconds = &progressDeadlineSeconds
+	}
+
+	if strategy.CustomParams != nil {
+		c.add(fldPath.Child("customParams"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments cannot run a custom deployer, review what it does")
+	}
+	if len(strategy.Resources.Limits) > 0 || len(strategy.Resources.Requests) > 0 {
+		c.add(fldPath.Child("resources"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments have no deployer pod")
+	}
+	if len(strategy.Labels) > 0 {
+		c.add(fldPath.Child("labels"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments have no deployer pod")
+	}
+	if len(strategy.Annotations) > 0 {
+		c.add(fldPath.Child("annotations"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments have no deployer pod")
+	}
+	if strategy.ActiveDeadlineSeconds != nil {
+		c.add(fldPath.Child("activeDeadlineSeconds"), appsv1.DeploymentConfigConversionIssueUnsupported, "deployments have no deployer pod")
+	}
+}
+
+// hook reports a lifecycle hook, deployments have no hooks.
+func (c *converter) hook(hook *appsapi.LifecycleHook, fldPath *field.Path) {
+	if hook == nil {
+		return
+	}
+	message := "deployments have no lifecycle hooks, run the hook as a job or an init container"
+	if len(hook.TagImages) > 0 {
+		message = "deployments have no lifecycle hooks, tag the images from a pipeline"
+	}
+	c.add(fldPath, appsv1.DeploymentConfigConversionIssueUnsupported, message)
+}
+
+// triggers converts the image change triggers of config to the image triggers of
+// the deployment.
+func (c *converter) triggers(config *appsapi.DeploymentConfig, fldPath *field.Path) []imageTrigger {
+	containerPaths := map[string]string{}
+	for _, container := range config.Spec.Template.Spec.InitContainers {
+		containerPaths[container.Name] = fmt.Sprintf("spec.template.spec.initContainers[?(@.name==%q)].image", container.Name)
+	}
+	for _, container := range config.Spec.Template.Spec.Containers {
+		containerPaths[container.Name] = fmt.Sprintf("spec.template.spec.containers[?(@.name==%q)].image", container.Name)
+	}
+
+	triggers := []imageTrigger{}
+	configChange := false
+	for i, trigger := range config.Spec.Triggers {
+		triggerPath := fldPath.Index(i)
+		switch trigger.Type {
+		case appsapi.DeploymentTriggerOnConfigChange:
+			configChange = true
+		case appsapi.DeploymentTriggerOnImageChange:
+			params := trigger.ImageChangeParams
+			if params == nil {
+				continue
+			}
+			if params.From.Kind != "ImageStreamTag" {
+				c.add(triggerPath.Child("imageChangeParams", "from", "kind"), appsv1.DeploymentConfigConversionIssueUnsupported, fmt.Sprintf("image triggers of deployments only follow image stream tags, not %s", params.From.Kind))
+				continue
+			}
+			from := imageTriggerSource{Kind: params.From.Kind, Name: params.From.Name}
+			if params.From.Namespace != config.Namespace {
+				from.Namespace = params.From.Namespace
+			}
+			for _, name := range params.ContainerNames {
+				fieldPath, ok := containerPaths[name]
+				if !ok {
+					c.add(triggerPath.Child("imageChangeParams", "containerNames"), appsv1.DeploymentConfigConversionIssueUnsupported, fmt.Sprintf("container %q does not exist", name))
+					continue
+				}
+				triggers = append(triggers, imageTrigger{From: from, FieldPath: fieldPath, Paused: !params.Automatic})
+			}
+		}
+	}
+	if !configChange {
+		c.add(fldPath, appsv1.DeploymentConfigConversionIssueLossy, "deployments always roll out when their pod template changes, the deployment config only rolled out when triggered")
+	}
+	return triggers
+}
+
+func copyMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for key, value := range in {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package deployconvert
+
+import (
+	"reflect"
+	"testing"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	appstest "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/internaltest"
+)
+
+func TestToDeployment(t *testing.T) {
+	config := appstest.OkDeploymentConfig(1)
+	config.Labels = map[string]string{"app": "config"}
+	config.Annotations = map[string]string{"owner": "team", "apps.openshift.io/legacy": "true"}
+	config.Spec.AutoRollback = true
+	config.Spec.Replicas = 3
+	config.Spec.Triggers = append(config.Spec.Triggers, appsapi.DeploymentTriggerPolicy{
+		Type: appsapi.DeploymentTriggerOnImageChange,
+		ImageChangeParams: &appsapi.DeploymentTriggerImageChangeParams{
+			ContainerNames: []string{"container2"},
+			From:           kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: "shared", Name: "base:stable"},
+		},
+	}, appsapi.DeploymentTriggerPolicy{
+		Type: appsapi.DeploymentTriggerOnImageChange,
+		ImageChangeParams: &appsapi.DeploymentTriggerImageChangeParams{
+			ContainerNames: []string{"container1"},
+			From:           kapi.ObjectReference{Kind: "DockerImage", Name: "registry:8080/repo1:ref1"},
+		},
+	})
+	config.Spec.Strategy.RecreateParams.Mid = &appsapi.LifecycleHook{FailurePolicy: appsapi.LifecycleHookFailurePolicyAbort}
+
+	deployment, issues, err := ToDeployment(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if *deployment.Spec.Replicas != 3 || deployment.Spec.Strategy.Type != kappsv1.RecreateDeploymentStrategyType {
+		t.Errorf("unexpected spec %#v", deployment.Spec)
+	}
+	if deployment.Spec.ProgressDeadlineSeconds == nil || *deployment.Spec.ProgressDeadlineSeconds != 20 {
+		t.Errorf("expected the timeout as progress deadline, got %v", deployment.Spec.ProgressDeadlineSeconds)
+	}
+	if !reflect.DeepEqual(deployment.Spec.Selector.MatchLabels, appstest.OkSelector()) {
+		t.Errorf("unexpected selector %v", deployment.Spec.Selector)
+	}
+	if len(deployment.Spec.Template.Spec.Containers) != 2 || deployment.Spec.Template.Spec.Containers[0].Image != "registry:8080/repo1:ref1" {
+		t.Errorf("unexpected pod template %#v", deployment.Spec.Template)
+	}
+	expectedAnnotations := map[string]string{
+		"owner":                 "team",
+		ImageTriggersAnnotation: `[{"from":{"kind":"ImageStreamTag","name":"test-image-stream:latest"},"fieldPath":"spec.template.spec.containers[?(@.name==\"container1\")].image"},` + `{"from":{"kind":"ImageStreamTag","name":"base:stable","namespace":"shared"},"fieldPath":"spec.template.spec.containers[?(@.name==\"container2\")].image","paused":true}]`,
+	}
+	if !reflect.DeepEqual(deployment.Annotations, expectedAnnotations) {
+		t.Errorf("expected annotations\n%v\ngot\n%v", expectedAnnotations, deployment.Annotations)
+	}
+
+	expectedIssues := []appsv1.DeploymentConfigConversionIssue{
+		{Field: "spec.autoRollback", Severity: appsv1.DeploymentConfigConversionIssueUnsupported},
+		{Field: "spec.strategy.activeDeadlineSeconds", Severity: appsv1.DeploymentConfigConversionIssueUnsupported},
+		{Field: "spec.strategy.recreateParams.mid", Severity: appsv1.DeploymentConfigConversionIssueUnsupported},
+		{Field: "spec.strategy.recreateParams.timeoutSeconds", Severity: appsv1.DeploymentConfigConversionIssueLossy},
+		{Field: "spec.strategy.resources", Severity: appsv1.DeploymentConfigConversionIssueUnsupported},
+		{Field: "spec.triggers[3].imageChangeParams.from.kind", Severity: appsv1.DeploymentConfigConversionIssueUnsupported},
+	}
+	if len(issues) != len(expectedIssues) {
+		t.Fatalf("expected issues %v, got %v", expectedIssues, issues)
+	}
+	for i := range issues {
+		if issues[i].Field != expectedIssues[i].Field || issues[i].Severity != expectedIssues[i].Severity || len(issues[i].Message) == 0 {
+			t.Errorf("expected issue %v, got %v", expectedIssues[i], issues[i])
+		}
+	}
+}
+
+func TestToDeploymentRolling(t *testing.T) {
+	config := appstest.OkDeploymentConfig(1)
+	config.Spec.Triggers = nil
+	config.Spec.Strategy = appsapi.DeploymentStrategy{
+		Type: appsapi.DeploymentStrategyTypeRolling,
+		RollingParams: &appsapi.RollingDeploymentStrategyParams{
+			MaxUnavailable: intstr.FromInt(0),
+			MaxSurge:       intstr.FromString("50%"),
+			Pre:            &appsapi.LifecycleHook{TagImages: []appsapi.TagImageHook{{ContainerName: "container1"}}},
+		},
+	}
+
+	deployment, issues, err := ToDeployment(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &kappsv1.RollingUpdateDeployment{MaxUnavailable: &config.Spec.Strategy.RollingParams.MaxUnavailable, MaxSurge: &config.Spec.Strategy.RollingParams.MaxSurge}
+	if deployment.Spec.Strategy.Type != kappsv1.RollingUpdateDeploymentStrategyType || !reflect.DeepEqual(deployment.Spec.Strategy.RollingUpdate, expected) {
+		t.Errorf("unexpected strategy %#v", deployment.Spec.Strategy)
+	}
+	if _, ok := deployment.Annotations[ImageTriggersAnnotation]; ok {
+		t.Errorf("expected no image triggers, got %v", deployment.Annotations)
+	}
+	fields := []string{}
+	for _, issue := range issues {
+		fields = append(fields, issue.Field)
+	}
+	if expectedFields := []string{"spec.strategy.rollingParams.pre", "spec.triggers"}; !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("expected issues for %v, got %v", expectedFields, issues)
+	}
+}
@@ -0,0 +1,2 @@
+// Package deployconvert provides the conversion of deployment configs to deployments
+package deployconvert
@@ -0,0 +1,62 @@
+package deployconvert
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsclient "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
+)
+
+// REST implements deploymentconfigs/convert, which returns the apps/v1 deployment
+// equivalent to a deployment config, to help migrating off deployment configs.
+// Nothing is created.
+type REST struct {
+	dcClient appsclient.DeploymentConfigsGetter
+}
+
+var _ rest.Getter = &REST{}
+var _ rest.Storage = &REST{}
+
+// NewREST creates the storage converting deployment configs.
+func NewREST(dcClient appsclient.DeploymentConfigsGetter) *REST {
+	return &REST{dcClient: dcClient}
+}
+
+// New returns a new DeploymentConfigConversion.
+func (r *REST) New() runtime.Object {
+	return &appsapi.DeploymentConfigConversion{}
+}
+
+func (r *REST) Destroy() {}
+
+// Get returns the conversion of the named deployment config.
+func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	namespace := apirequest.NamespaceValue(ctx)
+	external, err := r.dcClient.DeploymentConfigs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	config := &appsapi.DeploymentConfig{}
+	if err := v1.Convert_v1_DeploymentConfig_To_apps_DeploymentConfig(external, config, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	deployment, issues, err := ToDeployment(config)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	conversion := &appsv1.DeploymentConfigConversion{Deployment: *deployment, Issues: issues}
+	internalConversion := &appsapi.DeploymentConfigConversion{}
+	if err := v1.Convert_v1_DeploymentConfigConversion_To_apps_DeploymentConfigConversion(conversion, internalConversion, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return internalConversion, nil
+}
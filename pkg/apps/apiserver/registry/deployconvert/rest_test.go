@@ -0,0 +1,46 @@
+package deployconvert
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
+	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
+)
+
+func TestGet(t *testing.T) {
+	config := appstest.OkDeploymentConfig(1)
+	config.Annotations = map[string]string{"owner": "team"}
+	config.Spec.AutoRollback = true
+	r := NewREST(appsfake.NewSimpleClientset(config).AppsV1())
+
+	obj, err := r.Get(apirequest.NewDefaultContext(), config.Name, &metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conversion, ok := obj.(*appsapi.DeploymentConfigConversion)
+	if !ok {
+		t.Fatalf("expected a DeploymentConfigConversion, got %T", obj)
+	}
+	if len(conversion.Issues) == 0 || conversion.Issues[0].Field != "spec.autoRollback" || conversion.Issues[0].Severity != appsapi.DeploymentConfigConversionIssueUnsupported {
+		t.Errorf("unexpected issues %#v", conversion.Issues)
+	}
+
+	external := &appsv1.DeploymentConfigConversion{}
+	if err := v1.Convert_apps_DeploymentConfigConversion_To_v1_DeploymentConfigConversion(conversion, external, nil); err != nil {
+		t.Fatal(err)
+	}
+	deployment := external.Deployment
+	if deployment.APIVersion != "apps/v1" || deployment.Kind != "Deployment" {
+		t.Errorf("expected an apps/v1 Deployment, got %s %s", deployment.APIVersion, deployment.Kind)
+	}
+	if deployment.Name != config.Name || deployment.Annotations["owner"] != "team" || *deployment.Spec.Replicas != config.Spec.Replicas {
+		t.Errorf("unexpected deployment %#v", deployment)
+	}
+}
@@ -0,0 +1,2 @@
+// Package deployhistory provides the revision history of deployment configs
+package deployhistory
@@ -0,0 +1,124 @@
+package deployhistory
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsclient "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	"github.com/openshift/library-go/pkg/apps/appsserialization"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+
+	"github.com/openshift/openshift-apiserver/pkg/api/diff"
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
+)
+
+// REST implements deploymentconfigs/history, which returns the revisions of a
+// deployment config still retained as replication controllers.
+type REST struct {
+	dcClient appsclient.DeploymentConfigsGetter
+	rcClient corev1client.ReplicationControllersGetter
+}
+
+var _ rest.Getter = &REST{}
+var _ rest.Storage = &REST{}
+
+// NewREST creates the storage serving deployment config histories.
+func NewREST(dcClient appsclient.DeploymentConfigsGetter, rcClient corev1client.ReplicationControllersGetter) *REST {
+	return &REST{dcClient: dcClient, rcClient: rcClient}
+}
+
+// New returns a new DeploymentConfigHistory.
+func (r *REST) New() runtime.Object {
+	return &appsapi.DeploymentConfigHistory{}
+}
+
+func (r *REST) Destroy() {}
+
+// Get returns the history of the named deployment config.
+func (r *REST) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	namespace := apirequest.NamespaceValue(ctx)
+	config, err := r.dcClient.DeploymentConfigs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	history, err := r.history(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	internalHistory := &appsapi.DeploymentConfigHistory{}
+	if err := v1.Convert_v1_DeploymentConfigHistory_To_apps_DeploymentConfigHistory(history, internalHistory, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return internalHistory, nil
+}
+
+// history returns the revisions of config. Only the latest revision and as many
+// earlier revisions as the revision history limit retains are returned, even if
+// older replication controllers were not pruned yet.
+func (r *REST) history(ctx context.Context, config *appsv1.DeploymentConfig) (*appsv1.DeploymentConfigHistory, error) {
+	list, err := r.rcClient.ReplicationControllers(config.Namespace).List(ctx, metav1.ListOptions{LabelSelector: appsutil.ConfigSelector(config.Name).String()})
+	if err != nil {
+		return nil, err
+	}
+	deployments := []*corev1.ReplicationController{}
+	for i := range list.Items {
+		deployments = append(deployments, &list.Items[i])
+	}
+	sort.Sort(appsutil.ByLatestVersionAsc(deployments))
+	if limit := config.Spec.RevisionHistoryLimit; limit != nil && len(deployments) > int(*limit)+1 {
+		deployments = deployments[len(deployments)-int(*limit)-1:]
+	}
+
+	history := &appsv1.DeploymentConfigHistory{
+		ObjectMeta: metav1.ObjectMeta{Namespace: config.Namespace, Name: config.Name},
+		Revisions:  []appsv1.DeploymentConfigRevision{},
+	}
+	var previous *appsv1.DeploymentConfig
+	for _, deployment := range deployments {
+		revision := appsv1.DeploymentConfigRevision{
+			Revision:          appsutil.DeploymentVersionFor(deployment),
+			Deployment:        deployment.Name,
+			Status:            appsutil.DeploymentStatusFor(deployment),
+			StatusReason:      appsutil.DeploymentStatusReasonFor(deployment),
+			Cancelled:         appsutil.IsDeploymentCancelled(deployment),
+			Replicas:          deployment.Status.Replicas,
+			CreationTimestamp: deployment.CreationTimestamp,
+		}
+		decoded, err := appsserialization.DecodeDeploymentConfig(deployment)
+		if err != nil {
+			revision.Error = err.Error()
+			previous = nil
+			history.Revisions = append(history.Revisions, revision)
+			continue
+		}
+		// the details describe the latest version of the encoded config, only
+		// report them for the revision they were recorded for
+		if decoded.Status.LatestVersion == revision.Revision {
+			revision.Details = decoded.Status.Details
+		}
+		revision.Template = decoded.Spec.Template
+		if previous != nil {
+			for _, change := range diff.DeploymentConfigs(previous, decoded) {
+				revision.Changes = append(revision.Changes, appsv1.DeploymentConfigChange{
+					Category: string(change.Category),
+					Field:    change.Field,
+					From:     change.From,
+					To:       change.To,
+				})
+			}
+		}
+		previous = decoded
+		history.Revisions = append(history.Revisions, revision)
+	}
+	return history, nil
+}
@@ -0,0 +1,166 @@
+package deployhistory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+
+	"github.com/openshift/openshift-apiserver/pkg/api/diff"
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
+
+	// install all APIs
+	_ "github.com/openshift/openshift-apiserver/pkg/api/install"
+)
+
+func makeDeployment(t *testing.T, config *appsv1.DeploymentConfig, status appsv1.DeploymentStatus) *corev1.ReplicationController {
+	deployment, err := appsutil.MakeDeployment(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment.Annotations[appsv1.DeploymentStatusAnnotation] = string(status)
+	return deployment
+}
+
+func TestHistory(t *testing.T) {
+	limit := int32(2)
+	config := appstest.OkDeploymentConfig(4)
+	config.Spec.RevisionHistoryLimit = &limit
+
+	objects := []*corev1.ReplicationController{}
+	for version := int64(1); version <= 4; version++ {
+		revision := appstest.OkDeploymentConfig(version)
+		revision.Status.Details = &appsv1.DeploymentDetails{Causes: []appsv1.DeploymentCause{{Type: appsv1.DeploymentTriggerOnConfigChange}}}
+		if version == 3 {
+			revision.Spec.Template.Spec.Containers[0].Image = "registry:8080/repo1:ref3"
+		}
+		if version == 4 {
+			revision.Status.Details = &appsv1.DeploymentDetails{Causes: []appsv1.DeploymentCause{{
+				Type:           "Rollback",
+				RollbackParams: &appsv1.DeploymentCauseRollbackParams{FromRevision: 3, ToRevision: 2, User: "alice"},
+			}}}
+		}
+		status := appsv1.DeploymentStatusComplete
+		if version == 4 {
+			status = appsv1.DeploymentStatusRunning
+		}
+		objects = append(objects, makeDeployment(t, revision, status))
+	}
+	// an unrelated deployment config is not part of the history
+	other := appstest.OkDeploymentConfig(1)
+	other.Name = "other"
+	objects = append(objects, makeDeployment(t, other, appsv1.DeploymentStatusComplete))
+
+	kc := fake.NewSimpleClientset()
+	// replication controllers are listed in reverse order, the history is sorted
+	for i := len(objects) - 1; i >= 0; i-- {
+		if err := kc.Tracker().Add(objects[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewREST(appsfake.NewSimpleClientset(config).AppsV1(), kc.CoreV1())
+
+	obj, err := r.Get(apirequest.NewDefaultContext(), config.Name, &metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history := obj.(*appsapi.DeploymentConfigHistory)
+	if history.Name != "config" || history.Namespace != metav1.NamespaceDefault {
+		t.Errorf("unexpected history metadata %#v", history.ObjectMeta)
+	}
+	versions := []int64{}
+	for _, revision := range history.Revisions {
+		versions = append(versions, revision.Revision)
+	}
+	if !reflect.DeepEqual(versions, []int64{2, 3, 4}) {
+		t.Fatalf("expected revisions 2, 3 and 4 retained by the history limit, got %v", versions)
+	}
+
+	oldest, changed, rolledBack := history.Revisions[0], history.Revisions[1], history.Revisions[2]
+	if oldest.Changes != nil {
+		t.Errorf("expected no changes for the oldest revision, got %#v", oldest.Changes)
+	}
+	if oldest.Deployment != "config-2" || oldest.Status != string(appsv1.DeploymentStatusComplete) || oldest.Template == nil {
+		t.Errorf("unexpected oldest revision %#v", oldest)
+	}
+	expected := []appsapi.DeploymentConfigChange{{Category: string(diff.CategoryImage), Field: "spec.template.spec.containers[container1]", From: "registry:8080/repo1:ref1", To: "registry:8080/repo1:ref3"}}
+	if !reflect.DeepEqual(changed.Changes, expected) {
+		t.Errorf("expected changes %#v, got %#v", expected, changed.Changes)
+	}
+	expected = []appsapi.DeploymentConfigChange{{Category: string(diff.CategoryImage), Field: "spec.template.spec.containers[container1]", From: "registry:8080/repo1:ref3", To: "registry:8080/repo1:ref1"}}
+	if !reflect.DeepEqual(rolledBack.Changes, expected) {
+		t.Errorf("expected changes %#v, got %#v", expected, rolledBack.Changes)
+	}
+	if rolledBack.Status != string(appsv1.DeploymentStatusRunning) {
+		t.Errorf("expected the latest revision to be running, got %q", rolledBack.Status)
+	}
+	if rolledBack.Details == nil || len(rolledBack.Details.Causes) != 1 || rolledBack.Details.Causes[0].Type != appsapi.DeploymentTriggerRollback {
+		t.Fatalf("expected a rollback cause, got %#v", rolledBack.Details)
+	}
+	if params := rolledBack.Details.Causes[0].RollbackParams; params == nil || params.ToRevision != 2 || params.User != "alice" {
+		t.Errorf("unexpected rollback params %#v", params)
+	}
+}
+
+func TestHistoryUndecodableRevision(t *testing.T) {
+	config := appstest.OkDeploymentConfig(3)
+	kc := fake.NewSimpleClientset()
+	for version := int64(1); version <= 3; version++ {
+		deployment := makeDeployment(t, appstest.OkDeploymentConfig(version), appsv1.DeploymentStatusComplete)
+		if version == 2 {
+			deployment.Annotations[appsv1.DeploymentEncodedConfigAnnotation] = "{"
+		}
+		if err := kc.Tracker().Add(deployment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewREST(appsfake.NewSimpleClientset(config).AppsV1(), kc.CoreV1())
+
+	history, err := r.history(context.TODO(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Revisions) != 3 {
+		t.Fatalf("expected 3 revisions, got %#v", history.Revisions)
+	}
+	if len(history.Revisions[1].Error) == 0 || history.Revisions[1].Template != nil {
+		t.Errorf("expected a decoding error for revision 2, got %#v", history.Revisions[1])
+	}
+	if history.Revisions[2].Changes != nil || history.Revisions[2].Template == nil {
+		t.Errorf("expected revision 3 without changes from an undecodable revision, got %#v", history.Revisions[2])
+	}
+}
+
+func TestHistoryDetailsOfOtherVersion(t *testing.T) {
+	config := appstest.OkDeploymentConfig(2)
+	kc := fake.NewSimpleClientset()
+	encoded := appstest.OkDeploymentConfig(1)
+	encoded.Status.Details = &appsv1.DeploymentDetails{Causes: []appsv1.DeploymentCause{{Type: appsv1.DeploymentTriggerOnConfigChange}}}
+	deployment := makeDeployment(t, encoded, appsv1.DeploymentStatusComplete)
+	deployment.Name = appsutil.DeploymentNameForConfigVersion(config.Name, 2)
+	deployment.Annotations[appsv1.DeploymentVersionAnnotation] = "2"
+	if err := kc.Tracker().Add(deployment); err != nil {
+		t.Fatal(err)
+	}
+	r := NewREST(appsfake.NewSimpleClientset(config).AppsV1(), kc.CoreV1())
+
+	history, err := r.history(context.TODO(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history.Revisions) != 1 || history.Revisions[0].Revision != 2 {
+		t.Fatalf("expected revision 2, got %#v", history.Revisions)
+	}
+	if details := history.Revisions[0].Details; details != nil {
+		t.Errorf("expected no details recorded for version 1 in revision 2, got %#v", details)
+	}
+}
@@ -0,0 +1,205 @@
+package deploylog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	genericrest "k8s.io/apiserver/pkg/registry/generic/rest"
+	"k8s.io/apiserver/pkg/registry/rest"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+	"github.com/openshift/library-go/pkg/build/naming"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+)
+
+var hookPodSuffixes = map[appsapi.DeploymentLogHook]string{
+	appsapi.DeploymentLogHookPre:  appsutil.PreHookPodSuffix,
+	appsapi.DeploymentLogHookMid:  appsutil.MidHookPodSuffix,
+	appsapi.DeploymentLogHookPost: appsutil.PostHookPodSuffix,
+}
+
+// getHookLogs returns the logs of the lifecycle hook pod of the selected phase of
+// the deployment, waiting for the pod to start unless NoWait is set.
+func (r *REST) getHookLogs(ctx context.Context, target *corev1.ReplicationController, opts *appsapi.DeploymentLogOptions) (runtime.Object, error) {
+	podName := naming.GetPodName(target.Name, hookPodSuffixes[opts.Hook])
+
+	var pod *corev1.Pod
+	condition := func() (bool, error) {
+		var err error
+		pod, err = r.podClient.Pods(target.Namespace).Get(ctx, podName, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		return pod.Status.Phase != corev1.PodPending, nil
+	}
+	if opts.NoWait {
+		started, err := condition()
+		if err != nil {
+			return nil, err
+		}
+		if !started {
+			return &genericrest.LocationStreamer{}, nil
+		}
+	} else {
+		err := wait.PollImmediate(r.interval, r.timeout, condition)
+		if err == wait.ErrWaitTimeout {
+			if pod == nil {
+				return nil, apierrors.NewNotFound(kapi.Resource("pods"), podName)
+			}
+			return nil, apierrors.NewServerTimeout(kapi.Resource("pods"), "get", 2)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.getLogsFn(ctx, target.Namespace, podName, DeploymentToPodLogOptions(opts))
+}
+
+// getAllPodLogs returns the logs of all init and regular containers of the
+// deployer, hook and application pods of the deployment that exist when the logs
+// are requested. Each line is prefixed with the name of its pod, and of its
+// container if the pod has several.
+func (r *REST) getAllPodLogs(ctx context.Context, target *corev1.ReplicationController, opts *appsapi.DeploymentLogOptions) (runtime.Object, error) {
+	deployerPods, err := r.podClient.Pods(target.Namespace).List(ctx, metav1.ListOptions{LabelSelector: appsutil.DeployerPodSelector(target.Name).String()})
+	if err != nil {
+		return nil, err
+	}
+	applicationPods, err := r.podClient.Pods(target.Namespace).List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromValidatedSet(labels.Set(target.Spec.Selector)).String()})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []corev1.Pod{}
+	seen := map[string]bool{}
+	for _, list := range []*corev1.PodList{deployerPods, applicationPods} {
+		items := list.Items
+		sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+		for _, pod := range items {
+			if !seen[pod.Name] {
+				seen[pod.Name] = true
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	streamer := &multiplexStreamer{flush: opts.Follow}
+	for _, pod := range pods {
+		containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+		for _, container := range containers {
+			prefix := fmt.Sprintf("[%s] ", pod.Name)
+			if len(containers) > 1 {
+				prefix = fmt.Sprintf("[%s/%s] ", pod.Name, container.Name)
+			}
+			logOpts := DeploymentToPodLogOptions(opts)
+			logOpts.Container = container.Name
+			namespace, name := pod.Namespace, pod.Name
+			streamer.sources = append(streamer.sources, logSource{
+				prefix: prefix,
+				open: func(ctx context.Context) (io.ReadCloser, error) {
+					return r.podClient.Pods(namespace).GetLogs(name, logOpts).Stream(ctx)
+				},
+			})
+		}
+	}
+	return streamer, nil
+}
+
+// logSource is the log stream of a container, whose lines are prefixed when
+// multiplexed. The stream is only opened when it is read.
+type logSource struct {
+	prefix string
+	open   func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// multiplexStreamer streams the lines of several log sources, each with its prefix.
+// When following, all sources are opened at once and lines are streamed as they
+// are read from any of them, otherwise the sources are opened and streamed one
+// after the other.
+type multiplexStreamer struct {
+	sources []logSource
+	flush   bool
+}
+
+var _ rest.ResourceStreamer = &multiplexStreamer{}
+
+func (s *multiplexStreamer) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+func (s *multiplexStreamer) DeepCopyObject() runtime.Object {
+	out := *s
+	out.sources = append([]logSource(nil), s.sources...)
+	return &out
+}
+
+// InputStream returns a stream with the prefixed lines of all sources.
+func (s *multiplexStreamer) InputStream(ctx context.Context, apiVersion, acceptHeader string) (io.ReadCloser, bool, string, error) {
+	reader, writer := io.Pipe()
+	lock := &sync.Mutex{}
+	write := func(prefix string, line []byte) error {
+		lock.Lock()
+		defer lock.Unlock()
+		_, err := writer.Write(append([]byte(prefix), line...))
+		return err
+	}
+	copyLines := func(source logSource) {
+		stream, err := source.open(ctx)
+		if err != nil {
+			write(source.prefix, []byte(fmt.Sprintf("unable to get logs: %v\n", err)))
+			return
+		}
+		defer stream.Close()
+		in := bufio.NewReader(stream)
+		for {
+			line, err := in.ReadBytes('\n')
+			if len(line) > 0 {
+				if line[len(line)-1] != '\n' {
+					line = append(line, '\n')
+				}
+				if write(source.prefix, line) != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	go func() {
+		defer writer.Close()
+		if !s.flush {
+			for _, source := range s.sources {
+				copyLines(source)
+			}
+			return
+		}
+		wg := sync.WaitGroup{}
+		for _, source := range s.sources {
+			wg.Add(1)
+			go func(source logSource) {
+				defer wg.Done()
+				copyLines(source)
+			}(source)
+		}
+		wg.Wait()
+	}()
+	return reader, s.flush, "text/plain", nil
+}
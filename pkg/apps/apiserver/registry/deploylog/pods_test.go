@@ -0,0 +1,147 @@
+package deploylog
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericrest "k8s.io/apiserver/pkg/registry/generic/rest"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes/fake"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
+)
+
+func testPod(name string, podLabels map[string]string, phase corev1.PodPhase, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: metav1.NamespaceDefault, Labels: podLabels},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+	}
+	return pod
+}
+
+func testLogREST(t *testing.T, pods ...runtime.Object) (*REST, *corev1.ReplicationController) {
+	config := appstest.OkDeploymentConfig(1)
+	deployment, err := appsutil.MakeDeployment(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment.Annotations[appsv1.DeploymentStatusAnnotation] = string(appsv1.DeploymentStatusRunning)
+	r := NewREST(appsfake.NewSimpleClientset(config).AppsV1(), fake.NewSimpleClientset(append(pods, deployment)...))
+	r.interval = 10 * time.Millisecond
+	r.timeout = 50 * time.Millisecond
+	return r, deployment
+}
+
+func TestRESTGetHookLogs(t *testing.T) {
+	deployerLabels := map[string]string{appsv1.DeployerPodForDeploymentLabel: "config-1"}
+
+	r, _ := testLogREST(t, testPod("config-1-hook-mid", deployerLabels, corev1.PodRunning, "lifecycle"))
+	var logPod string
+	var logOpts *corev1.PodLogOptions
+	r.getLogsFn = func(ctx context.Context, podNamespace, podName string, opts *corev1.PodLogOptions) (runtime.Object, error) {
+		logPod, logOpts = podName, opts
+		return &genericrest.LocationStreamer{}, nil
+	}
+	if _, err := r.Get(apirequest.NewDefaultContext(), "config", &appsapi.DeploymentLogOptions{Hook: appsapi.DeploymentLogHookMid, Follow: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logPod != "config-1-hook-mid" || !logOpts.Follow {
+		t.Errorf("expected to follow the logs of the mid hook pod, got %s %#v", logPod, logOpts)
+	}
+
+	obj, err := r.Get(apirequest.NewDefaultContext(), "config", &appsapi.DeploymentLogOptions{Hook: appsapi.DeploymentLogHookPre, NoWait: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := obj.(*genericrest.LocationStreamer); !ok {
+		t.Errorf("expected no logs for a hook pod that does not exist, got %#v", obj)
+	}
+
+	if _, err := r.Get(apirequest.NewDefaultContext(), "config", &appsapi.DeploymentLogOptions{Hook: appsapi.DeploymentLogHookPost}); err == nil {
+		t.Errorf("expected an error waiting for a hook pod that does not exist")
+	}
+}
+
+func TestRESTGetAllPodLogs(t *testing.T) {
+	deployerLabels := map[string]string{appsv1.DeployerPodForDeploymentLabel: "config-1"}
+	r, deployment := testLogREST(t,
+		testPod("config-1-hook-pre", deployerLabels, corev1.PodSucceeded, "lifecycle"),
+		testPod("config-1-deploy", deployerLabels, corev1.PodRunning, "deployment"),
+		testPod("config-1-b", nil, corev1.PodRunning, "app"),
+		testPod("config-2-a", map[string]string{"other": "deployment"}, corev1.PodRunning, "app"),
+	)
+	appPod := testPod("config-1-a", nil, corev1.PodRunning, "app", "proxy")
+	appPod.Labels = deployment.Spec.Selector
+	appPod.Spec.InitContainers = []corev1.Container{{Name: "setup"}}
+	if _, err := r.podClient.Pods(metav1.NamespaceDefault).Create(context.TODO(), appPod, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	other, err := r.podClient.Pods(metav1.NamespaceDefault).Get(context.TODO(), "config-1-b", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Labels = deployment.Spec.Selector
+	if _, err := r.podClient.Pods(metav1.NamespaceDefault).Update(context.TODO(), other, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := r.Get(apirequest.NewDefaultContext(), "config", &appsapi.DeploymentLogOptions{AllPods: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logs := logRequests(r); logs != 0 {
+		t.Errorf("expected no log streams to be opened before reading, got %d", logs)
+	}
+	in, flush, contentType, err := obj.(rest.ResourceStreamer).InputStream(context.TODO(), "v1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flush || contentType != "text/plain" {
+		t.Errorf("unexpected flush %t or content type %q", flush, contentType)
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[config-1-deploy] fake logs\n" +
+		"[config-1-hook-pre] fake logs\n" +
+		"[config-1-a/setup] fake logs\n" +
+		"[config-1-a/app] fake logs\n" +
+		"[config-1-a/proxy] fake logs\n" +
+		"[config-1-b] fake logs\n"
+	if string(data) != expected {
+		t.Errorf("expected logs\n%s\ngot\n%s", expected, data)
+	}
+	if logs := logRequests(r); logs != 6 {
+		t.Errorf("expected 6 log streams, got %d", logs)
+	}
+	if _, ok := obj.DeepCopyObject().(*multiplexStreamer); !ok {
+		t.Errorf("expected a copy of the streamer")
+	}
+}
+
+// logRequests returns the number of pod logs requested through the client of r.
+func logRequests(r *REST) int {
+	count := 0
+	for _, action := range r.podClient.(*fakecorev1.FakeCoreV1).Actions() {
+		if action.GetSubresource() == "log" {
+			count++
+		}
+	}
+	return count
+}
@@ -142,6 +142,14 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 	if err != nil {
 		return nil, err
 	}
+
+	switch {
+	case len(deployLogOpts.Hook) > 0:
+		return r.getHookLogs(ctx, target, deployLogOpts)
+	case deployLogOpts.AllPods:
+		return r.getAllPodLogs(ctx, target, deployLogOpts)
+	}
+
 	podName := appsutil.DeployerPodNameForDeployment(target.Name)
 	labelForDeployment := fmt.Sprintf("%s/%s", target.Namespace, target.Name)
 
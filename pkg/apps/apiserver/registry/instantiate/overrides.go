@@ -0,0 +1,135 @@
+package instantiate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/openshift/api/apps"
+	imagev1 "github.com/openshift/api/image/v1"
+	imagev1typedclient "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/openshift/library-go/pkg/image/imageutil"
+	"github.com/openshift/library-go/pkg/image/reference"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	"github.com/openshift/openshift-apiserver/pkg/image/apis/image/validation/whitelist"
+)
+
+// applyImageOverrides sets the images of the containers of config to the images of
+// overrides. The container of an override must have an image change trigger, and
+// the image must be an image of the image stream tag the trigger follows, referenced
+// by digest from an allowed registry. The container and the last triggered image of
+// the trigger are set to the reference the image stream resolved the image to.
+func applyImageOverrides(ctx context.Context, config *appsapi.DeploymentConfig, overrides []appsapi.DeploymentImageOverride, is imagev1typedclient.ImageStreamsGetter, whitelister whitelist.RegistryWhitelister) error {
+	errs := field.ErrorList{}
+	for i, override := range overrides {
+		fldPath := field.NewPath("imageOverrides").Index(i)
+		container := findContainer(&config.Spec.Template.Spec, override.ContainerName)
+		if container == nil {
+			errs = append(errs, field.NotFound(fldPath.Child("containerName"), override.ContainerName))
+			continue
+		}
+		params := imageChangeParamsFor(config, override.ContainerName)
+		if params == nil {
+			errs = append(errs, field.Invalid(fldPath.Child("containerName"), override.ContainerName, "only the image of a container with an image change trigger can be overridden"))
+			continue
+		}
+
+		imagePath := fldPath.Child("image")
+		ref, err := reference.Parse(override.Image)
+		if err != nil {
+			errs = append(errs, field.Invalid(imagePath, override.Image, err.Error()))
+			continue
+		}
+		if len(ref.ID) == 0 {
+			errs = append(errs, field.Invalid(imagePath, override.Image, "the image must be referenced by digest"))
+			continue
+		}
+		if err := whitelister.AdmitDockerImageReference(ctx, ref, whitelist.WhitelistTransportSecure); err != nil {
+			errs = append(errs, field.Forbidden(imagePath, err.Error()))
+			continue
+		}
+
+		name, tag, _ := imageutil.SplitImageStreamTag(params.From.Name)
+		stream, err := is.ImageStreams(params.From.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			errs = append(errs, field.Invalid(imagePath, override.Image, fmt.Sprintf("image stream %s/%s of the image change trigger of the container does not exist", params.From.Namespace, name)))
+			continue
+		}
+		if err != nil {
+			return errors.NewInternalError(err)
+		}
+		resolved, ok := resolveStreamImage(stream, tag, ref.ID)
+		if !ok {
+			errs = append(errs, field.Invalid(imagePath, override.Image, fmt.Sprintf("not an image of image stream tag %s/%s:%s of the image change trigger of the container", params.From.Namespace, name, tag)))
+			continue
+		}
+
+		container.Image = resolved
+		params.LastTriggeredImage = resolved
+	}
+	if len(errs) > 0 {
+		return errors.NewInvalid(apps.Kind("DeploymentRequest"), config.Name, errs)
+	}
+	return nil
+}
+
+// imageOverridesMessage describes the image overrides of a deployment.
+func imageOverridesMessage(overrides []appsapi.DeploymentImageOverride) string {
+	images := []string{}
+	for _, override := range overrides {
+		images = append(images, fmt.Sprintf("%s=%s", override.ContainerName, override.Image))
+	}
+	return "image override " + strings.Join(images, ", ")
+}
+
+func findContainer(spec *core.PodSpec, name string) *core.Container {
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			return &spec.Containers[i]
+		}
+	}
+	for i := range spec.InitContainers {
+		if spec.InitContainers[i].Name == name {
+			return &spec.InitContainers[i]
+		}
+	}
+	return nil
+}
+
+// imageChangeParamsFor returns the parameters of the image change trigger of the
+// named container of config, nil if it has none.
+func imageChangeParamsFor(config *appsapi.DeploymentConfig, name string) *appsapi.DeploymentTriggerImageChangeParams {
+	for _, trigger := range config.Spec.Triggers {
+		if trigger.Type != appsapi.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		for _, containerName := range trigger.ImageChangeParams.ContainerNames {
+			if containerName == name {
+				return trigger.ImageChangeParams
+			}
+		}
+	}
+	return nil
+}
+
+// resolveStreamImage returns the reference of the image with the digest in the
+// history of tag in stream, and false if the image was never tagged as tag.
+func resolveStreamImage(stream *imagev1.ImageStream, tag, digest string) (string, bool) {
+	for _, history := range stream.Status.Tags {
+		if history.Tag != tag {
+			continue
+		}
+		for _, event := range history.Items {
+			if event.Image == digest {
+				return event.DockerImageReference, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,103 @@
+package instantiate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+	imagev1fakeclient "github.com/openshift/client-go/image/clientset/versioned/fake"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	appstest "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/internaltest"
+	"github.com/openshift/openshift-apiserver/pkg/image/apis/image/validation/whitelist"
+)
+
+func TestApplyImageOverrides(t *testing.T) {
+	const (
+		digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+		other  = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		// resolved is the reference the image stream resolved the image to.
+		resolved = "image-registry.openshift-image-registry.svc:5000/default/app@" + digest
+	)
+	tests := []struct {
+		name     string
+		override appsapi.DeploymentImageOverride
+		// streamTag is the tag of the image in the stream, the trigger follows latest.
+		streamTag string
+		errorFor  string
+	}{
+		{
+			name:     "image of the stream",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container1", Image: "registry.example.com/default/app@" + digest},
+		},
+		{
+			name:     "unknown container",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container3", Image: "registry.example.com/default/app@" + digest},
+			errorFor: "imageOverrides[0].containerName",
+		},
+		{
+			name:     "container without trigger",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container2", Image: "registry.example.com/default/app@" + digest},
+			errorFor: "imageOverrides[0].containerName",
+		},
+		{
+			name:     "image by tag",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container1", Image: "registry.example.com/default/app:hotfix"},
+			errorFor: "imageOverrides[0].image",
+		},
+		{
+			name:     "image not in the stream",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container1", Image: "registry.example.com/default/app@" + other},
+			errorFor: "imageOverrides[0].image",
+		},
+		{
+			name:      "image of another tag of the stream",
+			override:  appsapi.DeploymentImageOverride{ContainerName: "container1", Image: "registry.example.com/default/app@" + digest},
+			streamTag: "canary",
+			errorFor:  "imageOverrides[0].image",
+		},
+		{
+			name:     "registry not allowed",
+			override: appsapi.DeploymentImageOverride{ContainerName: "container1", Image: "untrusted.example.com/default/app@" + digest},
+			errorFor: "imageOverrides[0].image",
+		},
+	}
+
+	whitelister, err := whitelist.NewRegistryWhitelister(openshiftcontrolplanev1.AllowedRegistries{{DomainName: "registry.example.com"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := appstest.OkDeploymentConfig(1)
+			config.Namespace = metav1.NamespaceDefault
+			config.Spec.Triggers[0].ImageChangeParams.From.Namespace = metav1.NamespaceDefault
+			streamTag := test.streamTag
+			if len(streamTag) == 0 {
+				streamTag = "latest"
+			}
+			stream := fakeStream(appstest.ImageStreamName, streamTag, resolved, digest)
+			client := imagev1fakeclient.NewSimpleClientset(stream)
+
+			err := applyImageOverrides(context.TODO(), config, []appsapi.DeploymentImageOverride{test.override}, client.ImageV1(), whitelister)
+			if len(test.errorFor) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.errorFor) {
+					t.Fatalf("expected an error for %s, got %v", test.errorFor, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if image := config.Spec.Template.Spec.Containers[0].Image; image != resolved {
+				t.Errorf("expected the image of the container to be the resolved image, got %s", image)
+			}
+			if image := config.Spec.Triggers[0].ImageChangeParams.LastTriggeredImage; image != resolved {
+				t.Errorf("expected the last triggered image to be the resolved image, got %s", image)
+			}
+		})
+	}
+}
@@ -31,12 +31,14 @@ import (
 	"github.com/openshift/library-go/pkg/image/imageutil"
 	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
 	"github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/validation"
+	"github.com/openshift/openshift-apiserver/pkg/image/apis/image/validation/whitelist"
 )
 
-// NewREST provides new REST storage for the apps API group.
-func NewREST(store registry.Store, imagesclient imagev1client.Interface, kc kubernetes.Interface, admission admission.Interface) *REST {
+// NewREST provides new REST storage for the apps API group. The images of image
+// overrides must be allowed by whitelister.
+func NewREST(store registry.Store, imagesclient imagev1client.Interface, kc kubernetes.Interface, admission admission.Interface, whitelister whitelist.RegistryWhitelister) *REST {
 	store.UpdateStrategy = Strategy
-	return &REST{store: &store, is: imagesclient.ImageV1(), rn: kc.CoreV1(), admit: admission}
+	return &REST{store: &store, is: imagesclient.ImageV1(), rn: kc.CoreV1(), admit: admission, whitelister: whitelister}
 }
 
 // REST implements the Creater & Storage interfaces.
@@ -45,17 +47,21 @@ var _ rest.Storage = &REST{}
 var _ rest.SingularNameProvider = &REST{}
 
 type REST struct {
-	store *registry.Store
-	is    imagev1typedclient.ImageStreamsGetter
-	rn    corev1client.ReplicationControllersGetter
-	admit admission.Interface
+	store       *registry.Store
+	is          imagev1typedclient.ImageStreamsGetter
+	rn          corev1client.ReplicationControllersGetter
+	admit       admission.Interface
+	whitelister whitelist.RegistryWhitelister
 }
 
 func (s *REST) New() runtime.Object {
 	return &appsapi.DeploymentRequest{}
 }
 
-// Create instantiates a deployment config
+// Create instantiates a deployment config. The images of the image overrides of the
+// request replace the images resolved by the triggers, and always cause a new
+// deployment. On dry-run, the deployment config that would be rolled out is
+// returned and nothing is stored.
 func (s *REST) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
 	req, ok := obj.(*appsapi.DeploymentRequest)
 	if !ok {
@@ -81,8 +87,13 @@ func (s *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 				return err
 			}
 		}
+		if len(req.ImageOverrides) > 0 {
+			if err := applyImageOverrides(ctx, config, req.ImageOverrides, s.is, s.whitelister); err != nil {
+				return err
+			}
+		}
 
-		canTrigger, causes, err := canTrigger(ctx, config, s.rn, req.Force)
+		canTrigger, causes, err := canTrigger(ctx, config, s.rn, req.Force || len(req.ImageOverrides) > 0)
 		if err != nil {
 			return err
 		}
@@ -106,34 +117,69 @@ func (s *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 		case appsapi.DeploymentTriggerManual:
 			config.Status.Details.Message = "manual change"
 		}
-		config.Status.LatestVersion++
-
-		userInfo, _ := apirequest.UserFrom(ctx)
-		attrs := admission.NewAttributesRecord(config, old, apps.Kind("DeploymentConfig").WithVersion("v1"), config.Namespace, config.Name, apps.Resource("DeploymentConfig").WithVersion("v1"), "", admission.Update,
-			options, false, userInfo)
-		objectInterfaces := admission.NewObjectInterfacesFromScheme(legacyscheme.Scheme)
-		if err := s.admit.(admission.MutationInterface).Admit(ctx, attrs, objectInterfaces); err != nil {
-			return err
-		}
-		if err := s.admit.(admission.ValidationInterface).Validate(ctx, attrs, objectInterfaces); err != nil {
-			return err
+		if len(req.ImageOverrides) > 0 {
+			config.Status.Details.Message = imageOverridesMessage(req.ImageOverrides)
 		}
+		config.Status.LatestVersion++
 
-		ret, _, err = s.store.Update(
-			ctx,
-			config.Name,
-			rest.DefaultUpdatedObjectInfo(config),
-			rest.AdmissionToValidateObjectFunc(s.admit, attrs, objectInterfaces),
-			rest.AdmissionToValidateObjectUpdateFunc(s.admit, attrs, objectInterfaces),
-			false,
-			&metav1.UpdateOptions{},
-		)
+		ret, err = s.update(ctx, config, old, options)
 		return err
 	})
 
 	return ret, err
 }
 
+// Rollout updates the deployment config to config and starts a new deployment of it
+// with details as its cause, as instantiating it does. The resource version of
+// config must be the current one.
+func (s *REST) Rollout(ctx context.Context, config *appsapi.DeploymentConfig, details *appsapi.DeploymentDetails, options *metav1.CreateOptions) (*appsapi.DeploymentConfig, error) {
+	oldObj, err := s.store.Get(ctx, config.Name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	old := oldObj.(*appsapi.DeploymentConfig)
+
+	config = config.DeepCopy()
+	config.Status.Details = details
+	config.Status.LatestVersion = old.Status.LatestVersion + 1
+	klog.V(4).Infof("New deployment for %q caused by %#v", config.Name, details.Causes)
+
+	obj, err := s.update(ctx, config, old, options)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*appsapi.DeploymentConfig), nil
+}
+
+// update admits and stores the instantiated config.
+func (s *REST) update(ctx context.Context, config, old *appsapi.DeploymentConfig, options *metav1.CreateOptions) (runtime.Object, error) {
+	updateOptions := &metav1.UpdateOptions{}
+	if options != nil {
+		updateOptions.DryRun = options.DryRun
+	}
+	userInfo, _ := apirequest.UserFrom(ctx)
+	attrs := admission.NewAttributesRecord(config, old, apps.Kind("DeploymentConfig").WithVersion("v1"), config.Namespace, config.Name, apps.Resource("DeploymentConfig").WithVersion("v1"), "", admission.Update,
+		options, len(updateOptions.DryRun) > 0, userInfo)
+	objectInterfaces := admission.NewObjectInterfacesFromScheme(legacyscheme.Scheme)
+	if err := s.admit.(admission.MutationInterface).Admit(ctx, attrs, objectInterfaces); err != nil {
+		return nil, err
+	}
+	if err := s.admit.(admission.ValidationInterface).Validate(ctx, attrs, objectInterfaces); err != nil {
+		return nil, err
+	}
+
+	obj, _, err := s.store.Update(
+		ctx,
+		config.Name,
+		rest.DefaultUpdatedObjectInfo(config),
+		rest.AdmissionToValidateObjectFunc(s.admit, attrs, objectInterfaces),
+		rest.AdmissionToValidateObjectUpdateFunc(s.admit, attrs, objectInterfaces),
+		false,
+		updateOptions,
+	)
+	return obj, err
+}
+
 func (s *REST) Destroy() {
 	s.store.Destroy()
 }
@@ -0,0 +1,237 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/openshift/library-go/pkg/apps/appsserialization"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
+)
+
+const maxAutoRollbackRetries = 5
+
+// AutoRollbackController rolls deployment configs with spec.autoRollback set
+// back to their last complete revision when their latest rollout fails, be it
+// because it exceeded its deadline or because a lifecycle hook with the Abort
+// failure policy failed. The image change triggers of the deployment config are
+// kept. A rollout deployed by an automatic rollback, or deploying the pod
+// template of a revision which was already rolled back automatically, is not
+// rolled back, so that a trigger deploying the same broken image again does not
+// cause a rollback loop.
+type AutoRollbackController struct {
+	rollback *REST
+
+	queue workqueue.TypedRateLimitingInterface[string]
+}
+
+// NewAutoRollbackController creates an AutoRollbackController rolling back
+// through r.
+func NewAutoRollbackController(r *REST) *AutoRollbackController {
+	return &AutoRollbackController{rollback: r}
+}
+
+// Run rolls back the deployment configs of the failed deployments observed by
+// informer until stopCh is closed.
+func (c *AutoRollbackController) Run(informer cache.SharedIndexInformer, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	c.queue = workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "deploymentconfigautorollback"},
+	)
+	defer c.queue.ShutDown()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueDeployment,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if appsutil.DeploymentStatusFor(oldObj.(*corev1.ReplicationController)) != appsv1.DeploymentStatusFailed {
+				c.enqueueDeployment(newObj)
+			}
+		},
+	})
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return
+	}
+
+	klog.Infof("Starting deployment config auto rollback controller")
+	go wait.Until(c.worker, time.Second, stopCh)
+	<-stopCh
+	klog.Infof("Shutting down deployment config auto rollback controller")
+}
+
+// enqueueDeployment queues the deployment config of a failed deployment.
+func (c *AutoRollbackController) enqueueDeployment(obj interface{}) {
+	deployment, ok := obj.(*corev1.ReplicationController)
+	if !ok || appsutil.DeploymentStatusFor(deployment) != appsv1.DeploymentStatusFailed {
+		return
+	}
+	name := appsutil.DeploymentConfigNameFor(deployment)
+	if len(name) == 0 {
+		return
+	}
+	c.queue.Add(deployment.Namespace + "/" + name)
+}
+
+func (c *AutoRollbackController) worker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *AutoRollbackController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.sync(context.TODO(), key)
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	if c.queue.NumRequeues(key) < maxAutoRollbackRetries {
+		klog.V(4).Infof("retrying the automatic rollback of deployment config %s: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("unable to roll back deployment config %s after its rollout failed: %v", key, err))
+	c.queue.Forget(key)
+	return true
+}
+
+// sync rolls the deployment config identified by key back to its last complete
+// revision if it opted in and its latest rollout failed.
+func (c *AutoRollbackController) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	// Rollbacks are instantiated, and admitted, on behalf of the apiserver.
+	ctx = apirequest.WithNamespace(ctx, namespace)
+	ctx = apirequest.WithUser(ctx, &user.DefaultInfo{Name: user.APIServerUser, Groups: []string{user.SystemPrivilegedGroup}})
+
+	r := c.rollback
+	from, err := r.dn.DeploymentConfigs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !from.Spec.AutoRollback || from.Spec.Paused {
+		return nil
+	}
+	failed := from.Status.LatestVersion
+
+	// Never roll back a rollback: if the rollout deployed by an automatic rollback
+	// fails too, the deployment config is left for a person to fix.
+	if details := from.Status.Details; details != nil {
+		for _, cause := range details.Causes {
+			if cause.Type == appsv1.DeploymentTriggerType(appsapi.DeploymentTriggerAutoRollback) {
+				klog.V(2).Infof("Not rolling back deployment config %s/%s: revision %d was deployed by an automatic rollback", namespace, name, failed)
+				return nil
+			}
+		}
+	}
+
+	deployments, err := r.rn.ReplicationControllers(namespace).List(ctx, metav1.ListOptions{LabelSelector: appsutil.ConfigSelector(name).String()})
+	if err != nil {
+		return err
+	}
+	var latest, target *corev1.ReplicationController
+	byVersion := map[int64]*corev1.ReplicationController{}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		version := appsutil.DeploymentVersionFor(deployment)
+		byVersion[version] = deployment
+		switch {
+		case version == failed:
+			latest = deployment
+		case version < failed && appsutil.DeploymentStatusFor(deployment) == appsv1.DeploymentStatusComplete:
+			if target == nil || version > appsutil.DeploymentVersionFor(target) {
+				target = deployment
+			}
+		}
+	}
+	// The rollout may have been cancelled, or replaced by a newer one since it failed.
+	if latest == nil || appsutil.DeploymentStatusFor(latest) != appsv1.DeploymentStatusFailed || appsutil.IsDeploymentCancelled(latest) {
+		return nil
+	}
+	if target == nil {
+		klog.V(2).Infof("Not rolling back deployment config %s/%s: no complete revision to roll back revision %d to", namespace, name, failed)
+		return nil
+	}
+	latestConfig, err := appsserialization.DecodeDeploymentConfig(latest)
+	if err != nil {
+		return fmt.Errorf("couldn't decode deployment config from deployment %s: %v", latest.Name, err)
+	}
+	if revision, ok := rolledBackBefore(latestConfig, deployments.Items, byVersion); ok {
+		klog.V(2).Infof("Not rolling back deployment config %s/%s: revision %d deploys the pod template of revision %d, which was already rolled back automatically", namespace, name, failed, revision)
+		return nil
+	}
+
+	decoded, err := appsserialization.DecodeDeploymentConfig(target)
+	if err != nil {
+		return fmt.Errorf("couldn't decode deployment config from deployment %s: %v", target.Name, err)
+	}
+	toConfig := &appsapi.DeploymentConfig{}
+	if err := v1.Convert_v1_DeploymentConfig_To_apps_DeploymentConfig(decoded, toConfig, nil); err != nil {
+		return err
+	}
+
+	revision := appsutil.DeploymentVersionFor(target)
+	spec := &appsapi.DeploymentConfigRollbackSpec{IncludeTemplate: true, KeepImageTriggers: true, Rollout: true}
+	cause := appsapi.DeploymentCause{
+		Type:           appsapi.DeploymentTriggerAutoRollback,
+		RollbackParams: &appsapi.DeploymentCauseRollbackParams{FromRevision: failed, ToRevision: revision},
+	}
+	if _, err := r.rollback(ctx, from, toConfig, spec, cause, &metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	klog.V(2).Infof("Rolled back deployment config %s/%s from failed revision %d to revision %d", namespace, name, failed, revision)
+	return nil
+}
+
+// rolledBackBefore returns the revision an automatic rollback recorded in
+// deployments rolled back from, if its pod template is the one of config.
+func rolledBackBefore(config *appsv1.DeploymentConfig, deployments []corev1.ReplicationController, byVersion map[int64]*corev1.ReplicationController) (int64, bool) {
+	for i := range deployments {
+		decoded, err := appsserialization.DecodeDeploymentConfig(&deployments[i])
+		if err != nil || decoded.Status.Details == nil {
+			continue
+		}
+		for _, cause := range decoded.Status.Details.Causes {
+			if cause.Type != appsv1.DeploymentTriggerType(appsapi.DeploymentTriggerAutoRollback) || cause.RollbackParams == nil {
+				continue
+			}
+			revision := cause.RollbackParams.FromRevision
+			if revision == config.Status.LatestVersion {
+				continue
+			}
+			rolledBack, ok := byVersion[revision]
+			if !ok {
+				continue
+			}
+			rolledBackConfig, err := appsserialization.DecodeDeploymentConfig(rolledBack)
+			if err != nil {
+				continue
+			}
+			if equality.Semantic.DeepEqual(rolledBackConfig.Spec.Template, config.Spec.Template) {
+				return revision, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,193 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
+)
+
+type revision struct {
+	status appsv1.DeploymentStatus
+	image  string
+	// rolledBackFrom, if set, records the revision as deployed by an automatic
+	// rollback from the given revision.
+	rolledBackFrom int64
+}
+
+func TestAutoRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		disabled  bool
+		cause     appsv1.DeploymentTriggerType
+		revisions []revision
+		cancelled bool
+		paused    bool
+		expected  int64
+	}{
+		{
+			name:      "rolled back to the last complete revision",
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusFailed, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+			expected:  1,
+		},
+		{
+			name:      "rolled back after a manual rollback",
+			cause:     appsv1.DeploymentTriggerType(appsapi.DeploymentTriggerRollback),
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusComplete, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+			expected:  2,
+		},
+		{
+			name:      "not opted in",
+			disabled:  true,
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusComplete, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+		},
+		{
+			name:      "failed automatic rollback",
+			cause:     appsv1.DeploymentTriggerType(appsapi.DeploymentTriggerAutoRollback),
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusComplete, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+		},
+		{
+			name:      "cancelled rollout",
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusComplete, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+			cancelled: true,
+		},
+		{
+			name:      "paused",
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusComplete, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+			paused:    true,
+		},
+		{
+			name:      "no complete revision",
+			revisions: []revision{{status: appsv1.DeploymentStatusFailed, image: "a"}, {status: appsv1.DeploymentStatusFailed, image: "b"}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+		},
+		{
+			name:      "template rolled back before triggered again",
+			cause:     appsv1.DeploymentTriggerOnImageChange,
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusFailed, image: "b"}, {status: appsv1.DeploymentStatusComplete, image: "a", rolledBackFrom: 2}, {status: appsv1.DeploymentStatusFailed, image: "b"}},
+		},
+		{
+			name:      "new template after an automatic rollback",
+			cause:     appsv1.DeploymentTriggerOnImageChange,
+			revisions: []revision{{status: appsv1.DeploymentStatusComplete, image: "a"}, {status: appsv1.DeploymentStatusFailed, image: "b"}, {status: appsv1.DeploymentStatusComplete, image: "a", rolledBackFrom: 2}, {status: appsv1.DeploymentStatusFailed, image: "c"}},
+			expected:  3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			for i, revision := range test.revisions {
+				config := appstest.OkDeploymentConfig(int64(i + 1))
+				config.Spec.Template.Spec.Containers[0].Image = revision.image
+				if revision.rolledBackFrom > 0 {
+					config.Status.Details = &appsv1.DeploymentDetails{Causes: []appsv1.DeploymentCause{{
+						Type:           appsv1.DeploymentTriggerType(appsapi.DeploymentTriggerAutoRollback),
+						RollbackParams: &appsv1.DeploymentCauseRollbackParams{FromRevision: revision.rolledBackFrom, ToRevision: 1},
+					}}}
+				}
+				deployment, err := appsutil.MakeDeployment(config)
+				if err != nil {
+					t.Fatal(err)
+				}
+				deployment.Annotations[appsv1.DeploymentStatusAnnotation] = string(revision.status)
+				objects = append(objects, deployment)
+			}
+			if test.cancelled {
+				appsutil.SetCancelledByUserReason(objects[len(objects)-1].(*corev1.ReplicationController))
+			}
+			failed := int64(len(test.revisions))
+			config := appstest.OkDeploymentConfig(failed)
+			config.Spec.AutoRollback = !test.disabled
+			config.Spec.Paused = test.paused
+			if len(test.cause) > 0 {
+				config.Status.Details = &appsv1.DeploymentDetails{Causes: []appsv1.DeploymentCause{{Type: test.cause}}}
+			}
+
+			instantiator := &fakeInstantiator{}
+			r := NewREST(appsfake.NewSimpleClientset(config), fake.NewSimpleClientset(objects...), &imagefake.Clientset{}, instantiator)
+			if err := NewAutoRollbackController(r).sync(context.Background(), "default/config"); err != nil {
+				t.Fatal(err)
+			}
+
+			if test.expected == 0 {
+				if instantiator.config != nil {
+					t.Fatalf("expected no rollback, got %#v", instantiator.details)
+				}
+				return
+			}
+			if instantiator.config == nil {
+				t.Fatalf("expected a rollback to revision %d", test.expected)
+			}
+			causes := instantiator.details.Causes
+			if len(causes) != 1 || causes[0].Type != appsapi.DeploymentTriggerAutoRollback {
+				t.Fatalf("expected an automatic rollback cause, got %#v", causes)
+			}
+			expected := appsapi.DeploymentCauseRollbackParams{FromRevision: failed, ToRevision: test.expected}
+			if params := causes[0].RollbackParams; params == nil || *params != expected {
+				t.Errorf("expected rollback params %#v, got %#v", expected, params)
+			}
+			if image := instantiator.config.Spec.Template.Spec.Containers[0].Image; image != test.revisions[test.expected-1].image {
+				t.Errorf("expected the image of revision %d, got %q", test.expected, image)
+			}
+			for _, trigger := range instantiator.config.Spec.Triggers {
+				if trigger.Type == appsapi.DeploymentTriggerOnImageChange && !trigger.ImageChangeParams.Automatic {
+					t.Errorf("expected the image change triggers to be kept")
+				}
+			}
+		})
+	}
+}
+
+func TestAutoRollbackEnqueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   appsv1.DeploymentStatus
+		reason   string
+		expected bool
+	}{
+		{name: "running", status: appsv1.DeploymentStatusRunning},
+		{name: "complete", status: appsv1.DeploymentStatusComplete},
+		{name: "deadline exceeded", status: appsv1.DeploymentStatusFailed, expected: true},
+		{name: "aborted by a hook", status: appsv1.DeploymentStatusFailed, reason: "pre hook failed", expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment, err := appsutil.MakeDeployment(appstest.OkDeploymentConfig(1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			deployment.Annotations[appsv1.DeploymentStatusAnnotation] = string(test.status)
+			if len(test.reason) > 0 {
+				deployment.Annotations[appsv1.DeploymentStatusReasonAnnotation] = test.reason
+			}
+
+			c := NewAutoRollbackController(nil)
+			c.queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+			defer c.queue.ShutDown()
+			c.enqueueDeployment(deployment)
+
+			if !test.expected {
+				if c.queue.Len() != 0 {
+					t.Fatalf("expected nothing queued, got %d items", c.queue.Len())
+				}
+				return
+			}
+			if c.queue.Len() != 1 {
+				t.Fatalf("expected the deployment config to be queued, got %d items", c.queue.Len())
+			}
+			if key, _ := c.queue.Get(); key != "default/config" {
+				t.Errorf("expected key default/config, got %q", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/library-go/pkg/authorization/authorizationutil"
+	"github.com/openshift/library-go/pkg/image/imageutil"
+
+	"github.com/openshift/openshift-apiserver/pkg/api/diff"
+	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
+	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
+)
+
+// preview compares the candidate of a rollback with the current deployment config
+// and checks that the images and secrets the candidate refers to still exist. Only
+// the image streams and secrets the requester may get are read; the references
+// which could not be checked are reported as warnings too.
+func (r *REST) preview(ctx context.Context, current *appsv1.DeploymentConfig, candidate *appsapi.DeploymentConfig) (*appsapi.DeploymentConfigRollbackPreview, error) {
+	to := &appsv1.DeploymentConfig{}
+	if err := v1.Convert_apps_DeploymentConfig_To_v1_DeploymentConfig(candidate, to, nil); err != nil {
+		return nil, err
+	}
+	preview := &appsapi.DeploymentConfigRollbackPreview{Config: *candidate}
+	for _, change := range append(diff.ReplicationMeta(current, to), diff.DeploymentConfigs(current, to)...) {
+		preview.Changes = append(preview.Changes, appsapi.DeploymentConfigChange{
+			Category: string(change.Category),
+			Field:    change.Field,
+			From:     change.From,
+			To:       change.To,
+		})
+	}
+	imageWarnings, err := r.imageWarnings(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	secretWarnings, err := r.secretWarnings(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	preview.Warnings = append(imageWarnings, secretWarnings...)
+	return preview, nil
+}
+
+// canGet returns whether the user of ctx may get the resource in namespace.
+func (r *REST) canGet(ctx context.Context, namespace, group, resource string) (bool, error) {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return false, nil
+	}
+	sar := authorizationutil.AddUserToSAR(user, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	})
+	resp, err := r.sar.Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return resp.Status.Allowed, nil
+}
+
+// imageWarnings warns about containers of config whose image is no longer in the
+// history of the image stream tag their image change trigger follows.
+func (r *REST) imageWarnings(ctx context.Context, config *appsv1.DeploymentConfig) ([]string, error) {
+	if config.Spec.Template == nil {
+		return nil, nil
+	}
+	images := map[string]string{}
+	for _, container := range config.Spec.Template.Spec.Containers {
+		images[container.Name] = container.Image
+	}
+
+	warnings := []string{}
+	readable := map[string]bool{}
+	for _, trigger := range config.Spec.Triggers {
+		params := trigger.ImageChangeParams
+		if trigger.Type != appsv1.DeploymentTriggerOnImageChange || params == nil || params.From.Kind != "ImageStreamTag" {
+			continue
+		}
+		namespace := params.From.Namespace
+		if len(namespace) == 0 {
+			namespace = config.Namespace
+		}
+		name, tag, err := imageutil.ParseImageStreamTagName(params.From.Name)
+		if err != nil {
+			continue
+		}
+		allowed, checked := readable[namespace]
+		if !checked {
+			if allowed, err = r.canGet(ctx, namespace, imagev1.GroupName, "imagestreams"); err != nil {
+				return nil, err
+			}
+			readable[namespace] = allowed
+		}
+		if !allowed {
+			warnings = append(warnings, fmt.Sprintf("image stream tag %s/%s:%s was not checked: you cannot get image streams in namespace %q", namespace, name, tag, namespace))
+			continue
+		}
+		stream, err := r.in.ImageStreams(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			warnings = append(warnings, fmt.Sprintf("image stream %s/%s no longer exists", namespace, name))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		history, ok := imageutil.StatusHasTag(stream, tag)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("image stream tag %s/%s:%s no longer exists", namespace, name, tag))
+			continue
+		}
+		for _, containerName := range params.ContainerNames {
+			image := images[containerName]
+			if len(image) == 0 {
+				continue
+			}
+			found := false
+			for _, item := range history.Items {
+				if image == item.DockerImageReference || strings.HasSuffix(image, "@"+item.Image) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				warnings = append(warnings, fmt.Sprintf("image %s of container %q is no longer in the history of image stream tag %s/%s:%s", image, containerName, namespace, name, tag))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// secretWarnings warns about secrets the pod template of config requires that no
+// longer exist. Optional secret references are ignored. Secrets are only read if
+// the requester may get them.
+func (r *REST) secretWarnings(ctx context.Context, config *appsv1.DeploymentConfig) ([]string, error) {
+	if config.Spec.Template == nil {
+		return nil, nil
+	}
+	referrers := map[string]string{}
+	reference := func(name, referrer string) {
+		if _, ok := referrers[name]; !ok && len(name) > 0 {
+			referrers[name] = referrer
+		}
+	}
+	spec := config.Spec.Template.Spec
+	for _, volume := range spec.Volumes {
+		switch {
+		case volume.Secret != nil && !isTrue(volume.Secret.Optional):
+			reference(volume.Secret.SecretName, fmt.Sprintf("volume %q", volume.Name))
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil && !isTrue(source.Secret.Optional) {
+					reference(source.Secret.Name, fmt.Sprintf("volume %q", volume.Name))
+				}
+			}
+		}
+	}
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	for _, container := range append(containers, spec.Containers...) {
+		for _, env := range container.Env {
+			if from := env.ValueFrom; from != nil && from.SecretKeyRef != nil && !isTrue(from.SecretKeyRef.Optional) {
+				reference(from.SecretKeyRef.Name, fmt.Sprintf("environment variable %s of container %q", env.Name, container.Name))
+			}
+		}
+		for _, from := range container.EnvFrom {
+			if from.SecretRef != nil && !isTrue(from.SecretRef.Optional) {
+				reference(from.SecretRef.Name, fmt.Sprintf("the environment of container %q", container.Name))
+			}
+		}
+	}
+	for _, secret := range spec.ImagePullSecrets {
+		reference(secret.Name, "the image pull secrets")
+	}
+
+	if len(referrers) == 0 {
+		return nil, nil
+	}
+	allowed, err := r.canGet(ctx, config.Namespace, corev1.GroupName, "secrets")
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return []string{fmt.Sprintf("the secrets referenced by the pod template were not checked: you cannot get secrets in namespace %q", config.Namespace)}, nil
+	}
+
+	names := []string{}
+	for name := range referrers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	warnings := []string{}
+	for _, name := range names {
+		_, err := r.sn.Secrets(config.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			warnings = append(warnings, fmt.Sprintf("secret %q referenced by %s no longer exists", name, referrers[name]))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
@@ -8,7 +8,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/util/dryrun"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/client-go/kubernetes"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 
 	v1 "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/v1"
@@ -16,8 +19,11 @@ import (
 	"k8s.io/apiserver/pkg/registry/rest"
 
 	"github.com/openshift/api/apps"
+	appsv1 "github.com/openshift/api/apps/v1"
 	appsclient "github.com/openshift/client-go/apps/clientset/versioned"
 	appsclienttyped "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	imageclient "github.com/openshift/client-go/image/clientset/versioned"
+	imageclienttyped "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
 
 	"github.com/openshift/library-go/pkg/apps/appsserialization"
 	"github.com/openshift/library-go/pkg/apps/appsutil"
@@ -26,11 +32,22 @@ import (
 	"github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/validation"
 )
 
-// REST provides a rollback generation endpoint. Only the Create method is implemented.
+// Instantiator rolls out new deployments of deployment configs.
+type Instantiator interface {
+	Rollout(ctx context.Context, config *appsapi.DeploymentConfig, details *appsapi.DeploymentDetails, options *metav1.CreateOptions) (*appsapi.DeploymentConfig, error)
+}
+
+// REST provides a rollback generation endpoint, which can also deploy the rollback.
+// Only the Create method is implemented. A dry-run rollback returns a preview of
+// the rollback.
 type REST struct {
-	generator RollbackGenerator
-	dn        appsclienttyped.DeploymentConfigsGetter
-	rn        corev1client.ReplicationControllersGetter
+	generator    RollbackGenerator
+	instantiator Instantiator
+	dn           appsclienttyped.DeploymentConfigsGetter
+	rn           corev1client.ReplicationControllersGetter
+	sn           corev1client.SecretsGetter
+	in           imageclienttyped.ImageStreamsGetter
+	sar          authorizationclient.SubjectAccessReviewInterface
 }
 
 var _ rest.Creater = &REST{}
@@ -38,11 +55,15 @@ var _ rest.Storage = &REST{}
 var _ rest.SingularNameProvider = &REST{}
 
 // NewREST safely creates a new REST.
-func NewREST(appsclient appsclient.Interface, kc kubernetes.Interface) *REST {
+func NewREST(appsclient appsclient.Interface, kc kubernetes.Interface, imageclient imageclient.Interface, instantiator Instantiator) *REST {
 	return &REST{
-		generator: NewRollbackGenerator(),
-		dn:        appsclient.AppsV1(),
-		rn:        kc.CoreV1(),
+		generator:    NewRollbackGenerator(),
+		instantiator: instantiator,
+		dn:           appsclient.AppsV1(),
+		rn:           kc.CoreV1(),
+		sn:           kc.CoreV1(),
+		in:           imageclient.ImageV1(),
+		sar:          kc.AuthorizationV1().SubjectAccessReviews(),
 	}
 }
 
@@ -57,7 +78,12 @@ func (r *REST) GetSingularName() string {
 	return "deploymentconfigrollback"
 }
 
-// Create generates a new DeploymentConfig representing a rollback.
+// Create generates a new DeploymentConfig representing a rollback, with a rollback
+// cause in its status details. If the rollback spec requests a rollout, the rollback
+// is deployed through instantiate and the rolled out deployment config is returned.
+// On dry-run, nothing is deployed and a DeploymentConfigRollbackPreview holding the
+// candidate deployment config is returned; the warnings of the preview are also
+// returned as warnings of the response.
 func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
 	namespace, ok := apirequest.NamespaceFrom(ctx)
 	if !ok {
@@ -118,12 +144,55 @@ func (r *REST) Create(ctx context.Context, obj runtime.Object, createValidation
 		from.Annotations[key] = value
 	}
 
+	user := ""
+	if userInfo, ok := apirequest.UserFrom(ctx); ok {
+		user = userInfo.GetName()
+	}
+	cause := appsapi.DeploymentCause{
+		Type: appsapi.DeploymentTriggerRollback,
+		RollbackParams: &appsapi.DeploymentCauseRollbackParams{
+			FromRevision: from.Status.LatestVersion,
+			ToRevision:   revision,
+			User:         user,
+		},
+	}
+	rolledOut, err := r.rollback(ctx, from, toConfig, &rollback.Spec, cause, options)
+	if err != nil || options == nil || !dryrun.IsDryRun(options.DryRun) {
+		return rolledOut, err
+	}
+
+	preview, err := r.preview(ctx, from, rolledOut)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	for _, text := range preview.Warnings {
+		warning.AddWarning(ctx, "", text)
+	}
+	return preview, nil
+}
+
+// rollback merges toConfig onto from as requested by spec, with cause as the cause
+// of the result. If spec requests a rollout, the result is deployed through the
+// instantiator, which assigns the next version.
+func (r *REST) rollback(ctx context.Context, from *appsv1.DeploymentConfig, toConfig *appsapi.DeploymentConfig, spec *appsapi.DeploymentConfigRollbackSpec, cause appsapi.DeploymentCause, options *metav1.CreateOptions) (*appsapi.DeploymentConfig, error) {
 	fromInternal := &appsapi.DeploymentConfig{}
 	if err := v1.Convert_v1_DeploymentConfig_To_apps_DeploymentConfig(from, fromInternal, nil); err != nil {
 		return nil, apierrors.NewInternalError(err)
 	}
 
-	return r.generator.GenerateRollback(fromInternal, toConfig, &rollback.Spec)
+	rolledBack, err := r.generator.GenerateRollback(fromInternal, toConfig, spec)
+	if err != nil {
+		return nil, err
+	}
+	details := &appsapi.DeploymentDetails{Causes: []appsapi.DeploymentCause{cause}}
+	if spec.Rollout {
+		return r.instantiator.Rollout(ctx, rolledBack, details, options)
+	}
+	// Without a rollout only the next version is returned, for the client to
+	// update the deployment config with.
+	rolledBack.Status.LatestVersion++
+	rolledBack.Status.Details = details
+	return rolledBack, nil
 }
 
 func newInvalidError(rollback *appsapi.DeploymentConfigRollback, reason string) error {
@@ -1,10 +1,13 @@
 package rollback
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,14 +15,19 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	clientgotesting "k8s.io/client-go/testing"
 
+	"k8s.io/apiserver/pkg/authentication/user"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	apiserverrest "k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/apiserver/pkg/warning"
 
 	"github.com/openshift/api/apps"
 	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
 	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
 	"github.com/openshift/library-go/pkg/apps/appsutil"
 
+	"github.com/openshift/openshift-apiserver/pkg/api/diff"
 	appsapi "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps"
 	_ "github.com/openshift/openshift-apiserver/pkg/apps/apis/apps/install"
 	"github.com/openshift/openshift-apiserver/pkg/apps/apiserver/registry/appstest"
@@ -33,6 +41,22 @@ func (tg *terribleGenerator) GenerateRollback(from, to *appsapi.DeploymentConfig
 
 var _ RollbackGenerator = &terribleGenerator{}
 
+type fakeInstantiator struct {
+	config  *appsapi.DeploymentConfig
+	details *appsapi.DeploymentDetails
+	options *metav1.CreateOptions
+}
+
+func (i *fakeInstantiator) Rollout(ctx context.Context, config *appsapi.DeploymentConfig, details *appsapi.DeploymentDetails, options *metav1.CreateOptions) (*appsapi.DeploymentConfig, error) {
+	i.config, i.details, i.options = config, details, options
+	rolledOut := config.DeepCopy()
+	rolledOut.Status.LatestVersion++
+	rolledOut.Status.Details = details
+	return rolledOut, nil
+}
+
+var _ Instantiator = &fakeInstantiator{}
+
 func TestCreateError(t *testing.T) {
 	rest := REST{}
 	obj, err := rest.Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfig{}, apiserverrest.ValidateAllObjectFunc, &metav1.CreateOptions{})
@@ -70,7 +94,7 @@ func TestCreateOk(t *testing.T) {
 		return true, deployment, nil
 	})
 
-	obj, err := NewREST(oc, kc).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
+	obj, err := NewREST(oc, kc, &imagefake.Clientset{}, &fakeInstantiator{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
 		Name: "config",
 		Spec: appsapi.DeploymentConfigRollbackSpec{
 			Revision: 1,
@@ -90,6 +114,186 @@ func TestCreateOk(t *testing.T) {
 	}
 }
 
+func TestCreateRollbackCause(t *testing.T) {
+	tests := []struct {
+		name              string
+		keepImageTriggers bool
+		rollout           bool
+	}{
+		{
+			name: "image triggers disabled",
+		},
+		{
+			name:              "image triggers kept",
+			keepImageTriggers: true,
+		},
+		{
+			name:    "rolled out",
+			rollout: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oc := &appsfake.Clientset{}
+			oc.AddReactor("get", "deploymentconfigs", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, appstest.OkDeploymentConfig(3), nil
+			})
+			kc := &fake.Clientset{}
+			kc.AddReactor("get", "replicationcontrollers", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				deployment, _ := appsutil.MakeDeployment(appstest.OkDeploymentConfig(1))
+				return true, deployment, nil
+			})
+			instantiator := &fakeInstantiator{}
+			ctx := apirequest.WithUser(apirequest.NewDefaultContext(), &user.DefaultInfo{Name: "alice"})
+
+			obj, err := NewREST(oc, kc, &imagefake.Clientset{}, instantiator).Create(ctx, &appsapi.DeploymentConfigRollback{
+				Name: "config",
+				Spec: appsapi.DeploymentConfigRollbackSpec{
+					Revision:          1,
+					IncludeTemplate:   true,
+					KeepImageTriggers: test.keepImageTriggers,
+					Rollout:           test.rollout,
+				},
+			}, apiserverrest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			config := obj.(*appsapi.DeploymentConfig)
+			if config.Status.LatestVersion != 4 {
+				t.Errorf("expected the rollback to be version 4, got %d", config.Status.LatestVersion)
+			}
+			if rolledOut := instantiator.config != nil; rolledOut != test.rollout {
+				t.Errorf("expected the rollback to be rolled out: %t, got %t", test.rollout, rolledOut)
+			}
+
+			details := config.Status.Details
+			if details == nil || len(details.Causes) != 1 || details.Causes[0].Type != appsapi.DeploymentTriggerRollback {
+				t.Fatalf("expected a rollback cause, got %#v", details)
+			}
+			expected := appsapi.DeploymentCauseRollbackParams{FromRevision: 3, ToRevision: 1, User: "alice"}
+			if params := details.Causes[0].RollbackParams; params == nil || *params != expected {
+				t.Errorf("expected rollback params %#v, got %#v", expected, params)
+			}
+			for _, trigger := range config.Spec.Triggers {
+				if trigger.Type == appsapi.DeploymentTriggerOnImageChange && trigger.ImageChangeParams.Automatic != test.keepImageTriggers {
+					t.Errorf("expected automatic image change trigger to be %t", test.keepImageTriggers)
+				}
+			}
+		})
+	}
+}
+
+type fakeWarningRecorder struct {
+	warnings []string
+}
+
+func (r *fakeWarningRecorder) AddWarning(agent, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
+func TestCreateDryRun(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowed          bool
+		expectedWarnings []string
+	}{
+		{
+			name:    "readable references",
+			allowed: true,
+			expectedWarnings: []string{
+				`image registry:8080/repo1@sha256:pruned of container "container1" is no longer in the history of image stream tag default/test-image-stream:latest`,
+				`secret "config-v1" referenced by volume "config" no longer exists`,
+			},
+		},
+		{
+			name: "unreadable references",
+			expectedWarnings: []string{
+				`image stream tag default/test-image-stream:latest was not checked: you cannot get image streams in namespace "default"`,
+				`the secrets referenced by the pod template were not checked: you cannot get secrets in namespace "default"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oc := &appsfake.Clientset{}
+			oc.AddReactor("get", "deploymentconfigs", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				config := appstest.OkDeploymentConfig(3)
+				config.Spec.Template.Spec.Containers[0].Image = "registry:8080/repo1@sha256:current"
+				return true, config, nil
+			})
+			kc := &fake.Clientset{}
+			kc.AddReactor("get", "replicationcontrollers", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				config := appstest.OkDeploymentConfig(1)
+				config.Spec.Template.Spec.Containers[0].Image = "registry:8080/repo1@sha256:pruned"
+				config.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "config-v1"}}}}
+				deployment, _ := appsutil.MakeDeployment(config)
+				return true, deployment, nil
+			})
+			kc.AddReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				sar := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				if sar.Spec.User != "alice" || sar.Spec.ResourceAttributes.Verb != "get" {
+					t.Errorf("unexpected access review %#v", sar.Spec)
+				}
+				sar.Status.Allowed = test.allowed
+				return true, sar, nil
+			})
+			kc.AddReactor("get", "secrets", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				if !test.allowed {
+					t.Errorf("unexpected read of secret %q", action.(clientgotesting.GetAction).GetName())
+				}
+				return true, nil, kerrors.NewNotFound(corev1.Resource("secrets"), action.(clientgotesting.GetAction).GetName())
+			})
+			ic := imagefake.NewSimpleClientset(&imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: appstest.ImageStreamName, Namespace: metav1.NamespaceDefault},
+				Status: imagev1.ImageStreamStatus{Tags: []imagev1.NamedTagEventList{{
+					Tag:   "latest",
+					Items: []imagev1.TagEvent{{DockerImageReference: "registry:8080/repo1@sha256:current", Image: "sha256:current"}},
+				}}},
+			})
+			instantiator := &fakeInstantiator{}
+			recorder := &fakeWarningRecorder{}
+			ctx := apirequest.WithUser(apirequest.NewDefaultContext(), &user.DefaultInfo{Name: "alice"})
+			ctx = warning.WithWarningRecorder(ctx, recorder)
+
+			obj, err := NewREST(oc, kc, ic, instantiator).Create(ctx, &appsapi.DeploymentConfigRollback{
+				Name: "config",
+				Spec: appsapi.DeploymentConfigRollbackSpec{Revision: 1, IncludeTemplate: true},
+			}, apiserverrest.ValidateAllObjectFunc, &metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if instantiator.config != nil {
+				t.Errorf("expected the dry-run rollback not to be rolled out")
+			}
+			if !test.allowed && len(ic.Actions()) > 0 {
+				t.Errorf("unexpected image stream reads %#v", ic.Actions())
+			}
+
+			preview, ok := obj.(*appsapi.DeploymentConfigRollbackPreview)
+			if !ok {
+				t.Fatalf("expected a rollback preview, got %#v", obj)
+			}
+			if preview.Config.Name != "config" || preview.Config.Spec.Template.Spec.Containers[0].Image != "registry:8080/repo1@sha256:pruned" {
+				t.Errorf("expected the candidate deployment config, got %#v", preview.Config)
+			}
+			expectedChanges := []appsapi.DeploymentConfigChange{
+				{Category: string(diff.CategoryImage), Field: "spec.template.spec.containers[container1]", From: "registry:8080/repo1@sha256:current", To: "registry:8080/repo1@sha256:pruned"},
+				{Category: string(diff.CategoryVolumes), Field: "spec.template.spec.volumes[config]", To: "secret config-v1"},
+				{Category: string(diff.CategoryTrigger), Field: "spec.triggers[ImageChange " + appstest.ImageStreamName + ":latest]", From: "container1 automatic=true", To: "container1 automatic=false"},
+			}
+			if !reflect.DeepEqual(preview.Changes, expectedChanges) {
+				t.Errorf("expected changes\n%#v\ngot\n%#v", expectedChanges, preview.Changes)
+			}
+			if !reflect.DeepEqual(preview.Warnings, test.expectedWarnings) {
+				t.Errorf("expected warnings\n%#v\ngot\n%#v", test.expectedWarnings, preview.Warnings)
+			}
+			if !reflect.DeepEqual(recorder.warnings, test.expectedWarnings) {
+				t.Errorf("expected response warnings\n%#v\ngot\n%#v", test.expectedWarnings, recorder.warnings)
+			}
+		})
+	}
+}
+
 func TestCreateRollbackToLatest(t *testing.T) {
 	oc := &appsfake.Clientset{}
 	oc.AddReactor("get", "deploymentconfigs", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
@@ -97,7 +301,7 @@ func TestCreateRollbackToLatest(t *testing.T) {
 		return true, config, nil
 	})
 
-	_, err := NewREST(oc, &fake.Clientset{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
+	_, err := NewREST(oc, &fake.Clientset{}, &imagefake.Clientset{}, &fakeInstantiator{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
 		Name: "config",
 		Spec: appsapi.DeploymentConfigRollbackSpec{
 			Revision: 2,
@@ -154,7 +358,7 @@ func TestCreateMissingDeployment(t *testing.T) {
 		return true, nil, kerrors.NewNotFound(corev1.Resource("replicationController"), deployment.Name)
 	})
 
-	obj, err := NewREST(oc, kc).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
+	obj, err := NewREST(oc, kc, &imagefake.Clientset{}, &fakeInstantiator{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
 		Name: "config",
 		Spec: appsapi.DeploymentConfigRollbackSpec{
 			Revision: 1,
@@ -184,7 +388,7 @@ func TestCreateInvalidDeployment(t *testing.T) {
 		return true, deployment, nil
 	})
 
-	obj, err := NewREST(oc, kc).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
+	obj, err := NewREST(oc, kc, &imagefake.Clientset{}, &fakeInstantiator{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
 		Name: "config",
 		Spec: appsapi.DeploymentConfigRollbackSpec{
 			Revision: 1,
@@ -212,7 +416,7 @@ func TestCreateMissingDeploymentConfig(t *testing.T) {
 		return true, deployment, nil
 	})
 
-	obj, err := NewREST(oc, kc).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
+	obj, err := NewREST(oc, kc, &imagefake.Clientset{}, &fakeInstantiator{}).Create(apirequest.NewDefaultContext(), &appsapi.DeploymentConfigRollback{
 		Name: "config",
 		Spec: appsapi.DeploymentConfigRollbackSpec{
 			Revision: 1,
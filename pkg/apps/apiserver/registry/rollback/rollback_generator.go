@@ -14,7 +14,8 @@ type RollbackGenerator interface {
 	// triggering.
 	//
 	// Any image change triggers on the new config are disabled to prevent
-	// triggered deployments from immediately replacing the rollback.
+	// triggered deployments from immediately replacing the rollback, unless
+	// spec.KeepImageTriggers is set.
 	GenerateRollback(from, to *appsapi.DeploymentConfig, spec *appsapi.DeploymentConfigRollbackSpec) (*appsapi.DeploymentConfig, error)
 }
 
@@ -52,16 +53,14 @@ func (g *rollbackGenerator) GenerateRollback(from, to *appsapi.DeploymentConfig,
 		rollback.Spec.Strategy = *to.Spec.Strategy.DeepCopy()
 	}
 
-	// Disable any image change triggers.
-	for _, trigger := range rollback.Spec.Triggers {
-		if trigger.Type == appsapi.DeploymentTriggerOnImageChange {
-			trigger.ImageChangeParams.Automatic = false
+	// Disable any image change triggers, unless they should be kept.
+	if !spec.KeepImageTriggers {
+		for _, trigger := range rollback.Spec.Triggers {
+			if trigger.Type == appsapi.DeploymentTriggerOnImageChange {
+				trigger.ImageChangeParams.Automatic = false
+			}
 		}
 	}
 
-	// TODO: add a new cause?
-	// TODO: Instantiate instead of incrementing latestVersion
-	rollback.Status.LatestVersion++
-
 	return rollback, nil
 }
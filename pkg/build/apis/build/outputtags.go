@@ -0,0 +1,61 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// BuildOutputTagsAppliedAnnotation is set on a Build to the comma separated
+	// list of additional tags once they were written to the output image stream.
+	BuildOutputTagsAppliedAnnotation = "build.openshift.io/output-tags-applied"
+
+	// MaxBuildOutputTags bounds the number of additional output tags.
+	MaxBuildOutputTags = 16
+)
+
+var (
+	outputTagPlaceholderRegexp = regexp.MustCompile(`\$\{([a-zA-Z]+)\}`)
+	outputTagRegexp            = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	outputTagPlaceholders      = map[string]bool{"commit": true, "shortCommit": true, "branch": true, "tag": true}
+)
+
+// ValidateBuildOutputTag returns an error if tag is not a valid additional output
+// tag once its placeholders are expanded.
+func ValidateBuildOutputTag(tag string) error {
+	for _, match := range outputTagPlaceholderRegexp.FindAllStringSubmatch(tag, -1) {
+		if !outputTagPlaceholders[match[1]] {
+			return fmt.Errorf("unknown placeholder %q", match[0])
+		}
+	}
+	// placeholders expand to valid tag characters
+	if expanded := outputTagPlaceholderRegexp.ReplaceAllString(tag, "x"); !outputTagRegexp.MatchString(expanded) {
+		return fmt.Errorf("not a valid tag")
+	}
+	return nil
+}
+
+// ExpandBuildOutputTag returns tag with its placeholders replaced by values. Values
+// are sanitized to valid tag characters. If a placeholder has no value, false is
+// returned.
+func ExpandBuildOutputTag(tag string, values map[string]string) (string, bool) {
+	ok := true
+	expanded := outputTagPlaceholderRegexp.ReplaceAllStringFunc(tag, func(placeholder string) string {
+		value := values[placeholder[2:len(placeholder)-1]]
+		if len(value) == 0 {
+			ok = false
+		}
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+				return r
+			}
+			return '-'
+		}, value)
+	})
+	if !ok || !outputTagRegexp.MatchString(expanded) {
+		return "", false
+	}
+	return expanded, true
+}
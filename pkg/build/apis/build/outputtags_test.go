@@ -0,0 +1,41 @@
+package build
+
+import "testing"
+
+func TestValidateBuildOutputTag(t *testing.T) {
+	testCases := map[string]bool{
+		"latest":           true,
+		"${commit}":        true,
+		"v-${shortCommit}": true,
+		"${branch}":        true,
+		"${tag}":           true,
+		"${version}":       false,
+		"a/b":              false,
+		"":                 false,
+	}
+	for tag, valid := range testCases {
+		if err := ValidateBuildOutputTag(tag); valid != (err == nil) {
+			t.Errorf("%q: expected valid %v, got %v", tag, valid, err)
+		}
+	}
+}
+
+func TestExpandBuildOutputTag(t *testing.T) {
+	values := map[string]string{"commit": "abc", "branch": "feature/login"}
+	testCases := []struct {
+		tag      string
+		expected string
+		ok       bool
+	}{
+		{tag: "latest", expected: "latest", ok: true},
+		{tag: "${commit}", expected: "abc", ok: true},
+		{tag: "${branch}-${commit}", expected: "feature-login-abc", ok: true},
+		{tag: "${tag}"},
+	}
+	for _, tc := range testCases {
+		expanded, ok := ExpandBuildOutputTag(tc.tag, values)
+		if expanded != tc.expected || ok != tc.ok {
+			t.Errorf("%s: expected %q %v, got %q %v", tc.tag, tc.expected, tc.ok, expanded, ok)
+		}
+	}
+}
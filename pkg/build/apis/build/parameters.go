@@ -0,0 +1,21 @@
+package build
+
+import (
+	"regexp"
+)
+
+var buildParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,62}$`)
+
+// IsBuildParameterName returns true if name is a valid parameter name.
+func IsBuildParameterName(name string) bool {
+	return buildParameterNameRegexp.MatchString(name)
+}
+
+// CompileBuildParameterPattern returns a regular expression matching the values
+// allowed by pattern, or nil if pattern is empty and any value is allowed.
+func CompileBuildParameterPattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) == 0 {
+		return nil, nil
+	}
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
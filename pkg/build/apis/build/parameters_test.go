@@ -0,0 +1,22 @@
+package build
+
+import "testing"
+
+func TestCompileBuildParameterPattern(t *testing.T) {
+	if pattern, err := CompileBuildParameterPattern(""); pattern != nil || err != nil {
+		t.Errorf("expected no pattern, got %v, %v", pattern, err)
+	}
+	if _, err := CompileBuildParameterPattern("v[0-9"); err == nil {
+		t.Errorf("expected an invalid pattern")
+	}
+
+	pattern, err := CompileBuildParameterPattern("v[0-9]+")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, expected := range map[string]bool{"v1": true, "v12": true, "xv1": false, "v1x": false, "": false} {
+		if pattern.MatchString(value) != expected {
+			t.Errorf("%q: expected match %v", value, expected)
+		}
+	}
+}
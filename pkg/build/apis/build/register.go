@@ -38,6 +38,11 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BuildConfigList{},
 		&BuildLog{},
 		&BuildRequest{},
+		&BuildCancelRequest{},
+		&BuildStrategyPolicy{},
+		&BuildStrategyPolicyList{},
+		&BuildDiff{},
+		&BuildDiffOptions{},
 		&BuildLogOptions{},
 		&BinaryBuildRequestOptions{},
 		// This is needed for webhooks
@@ -0,0 +1,69 @@
+package build
+
+import (
+	"time"
+)
+
+const (
+	// MaxBuildRetryAttempts bounds the number of attempts of a build.
+	MaxBuildRetryAttempts = 10
+
+	defaultRetryInitialBackoffSeconds = 30
+	defaultRetryMaxBackoffSeconds     = 600
+)
+
+// DefaultBuildRetryReasons are the reasons retried when a retry policy names none.
+var DefaultBuildRetryReasons = []StatusReason{
+	StatusReasonFetchSourceFailed,
+	StatusReasonPushImageToRegistryFailed,
+	StatusReasonPullBuilderImageFailed,
+	StatusReasonBuildPodEvicted,
+}
+
+// DefaultedBuildRetryPolicy returns a copy of policy with its unset fields
+// defaulted, or nil if policy is nil.
+func DefaultedBuildRetryPolicy(policy *BuildRetryPolicy) *BuildRetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	policy = policy.DeepCopy()
+	if len(policy.Reasons) == 0 {
+		policy.Reasons = DefaultBuildRetryReasons
+	}
+	if policy.InitialBackoffSeconds == nil {
+		initial := int64(defaultRetryInitialBackoffSeconds)
+		policy.InitialBackoffSeconds = &initial
+	}
+	if policy.MaxBackoffSeconds == nil {
+		max := int64(defaultRetryMaxBackoffSeconds)
+		if max < *policy.InitialBackoffSeconds {
+			max = *policy.InitialBackoffSeconds
+		}
+		policy.MaxBackoffSeconds = &max
+	}
+	return policy
+}
+
+// Retryable returns true if builds failing for reason are retried.
+func (p *BuildRetryPolicy) Retryable(reason StatusReason) bool {
+	for _, r := range p.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay between the failure of attempt and the next attempt.
+// The policy must be defaulted.
+func (p *BuildRetryPolicy) Backoff(attempt int32) time.Duration {
+	backoff := time.Duration(*p.InitialBackoffSeconds) * time.Second
+	max := time.Duration(*p.MaxBackoffSeconds) * time.Second
+	for i := int32(1); i < attempt && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
@@ -0,0 +1,38 @@
+package build
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultedBuildRetryPolicy(t *testing.T) {
+	if policy := DefaultedBuildRetryPolicy(nil); policy != nil {
+		t.Errorf("expected no policy, got %v", policy)
+	}
+
+	initial := int64(900)
+	policy := &BuildRetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: &initial}
+	defaulted := DefaultedBuildRetryPolicy(policy)
+	if policy.MaxBackoffSeconds != nil || len(policy.Reasons) != 0 {
+		t.Errorf("expected the policy not to be modified, got %v", policy)
+	}
+	if len(defaulted.Reasons) != len(DefaultBuildRetryReasons) {
+		t.Errorf("expected the default reasons, got %v", defaulted.Reasons)
+	}
+	if *defaulted.MaxBackoffSeconds != initial {
+		t.Errorf("expected the max backoff to default to the initial backoff, got %d", *defaulted.MaxBackoffSeconds)
+	}
+}
+
+func TestBuildRetryPolicy(t *testing.T) {
+	max := int64(100)
+	policy := DefaultedBuildRetryPolicy(&BuildRetryPolicy{MaxAttempts: 5, MaxBackoffSeconds: &max})
+	if !policy.Retryable(StatusReasonPushImageToRegistryFailed) || policy.Retryable(StatusReasonDockerBuildFailed) {
+		t.Errorf("unexpected retryable reasons %v", policy.Reasons)
+	}
+	for attempt, expected := range map[int32]time.Duration{1: 30 * time.Second, 2: 60 * time.Second, 3: 100 * time.Second, 4: 100 * time.Second} {
+		if backoff := policy.Backoff(attempt); backoff != expected {
+			t.Errorf("attempt %d: expected a backoff of %v, got %v", attempt, expected, backoff)
+		}
+	}
+}
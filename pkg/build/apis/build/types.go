@@ -143,6 +143,17 @@ type BuildTriggerCause struct {
 	// BitbucketWebHook represents data for a Bitbucket webhook that fired a
 	// specific build.
 	BitbucketWebHook *BitbucketWebHookCause
+
+	// GiteaWebHook represents data for a Gitea or Forgejo webhook that fired a
+	// specific build.
+	GiteaWebHook *GiteaWebHookCause
+
+	// AzureDevOpsWebHook represents data for an Azure DevOps webhook that fired a
+	// specific build.
+	AzureDevOpsWebHook *AzureDevOpsWebHookCause
+
+	// BuildRetry holds information about the failed build a build retries.
+	BuildRetry *BuildRetryCause
 }
 
 // GenericWebHookCause holds information about a generic WebHook that
@@ -189,6 +200,31 @@ type BitbucketWebHookCause struct {
 	CommonWebHookCause
 }
 
+// GiteaWebHookCause has information about a Gitea or Forgejo webhook that
+// triggered a build.
+type GiteaWebHookCause struct {
+	CommonWebHookCause
+}
+
+// AzureDevOpsWebHookCause has information about an Azure DevOps webhook that
+// triggered a build.
+type AzureDevOpsWebHookCause struct {
+	CommonWebHookCause
+}
+
+// BuildRetryCause has information about the failed build retried by a build.
+type BuildRetryCause struct {
+	// BuildName is the name of the retried build.
+	BuildName string
+
+	// Attempt is the attempt number of the build, the build that failed first
+	// being attempt 1.
+	Attempt int32
+
+	// Reason is the reason the retried build failed.
+	Reason StatusReason
+}
+
 // ImageChangeCause contains information about the image that triggered a
 // build.
 type ImageChangeCause struct {
@@ -366,6 +402,11 @@ const (
 
 type BuildConditionType string
 
+// BuildConditionCancelRequested records who requested the cancellation of a build
+// and why. Unlike the conditions named after build phases, it is left untouched
+// when the build changes phase.
+const BuildConditionCancelRequested BuildConditionType = "CancelRequested"
+
 // BuildCondition describes the state of a build at a certain point.
 type BuildCondition struct {
 	// Type of build condition.
@@ -475,9 +516,16 @@ const (
 	// StatusReasonOutOfMemoryKilled indicates that the build pod was killed for its memory consumption
 	StatusReasonOutOfMemoryKilled StatusReason = "OutOfMemoryKilled"
 
+	// StatusReasonBuildPodEvicted indicates that the build pod was evicted from its node.
+	StatusReasonBuildPodEvicted StatusReason = "BuildPodEvicted"
+
 	// StatusCannotRetrieveServiceAccount is the reason associated with a failure
 	// to look up the service account associated with the BuildConfig.
 	StatusReasonCannotRetrieveServiceAccount StatusReason = "CannotRetrieveServiceAccount"
+
+	// StatusReasonSupersededBuild indicates that the build was cancelled because
+	// a newer build of its SerialLatestOnly BuildConfig was created.
+	StatusReasonSupersededBuild StatusReason = "SupersededBuild"
 )
 
 // NOTE: These messages might change.
@@ -983,6 +1031,12 @@ type BuildOutput struct {
 	// ImageLabels define a list of labels that are applied to the resulting image. If there
 	// are multiple labels with the same name then the last one in the list is used.
 	ImageLabels []ImageLabel
+
+	// AdditionalTags are tags of the output image stream the built image is tagged
+	// with, in addition to the tag of To, when the build completes. Tags may
+	// contain the placeholders ${commit}, ${shortCommit}, ${branch} and ${tag},
+	// which are expanded from the source of the build.
+	AdditionalTags []string
 }
 
 // ImageLabel represents a label applied to the resulting image.
@@ -1032,6 +1086,62 @@ type BuildConfigSpec struct {
 	// FailedBuildsHistoryLimit is the number of old failed builds to retain.
 	// This field is a pointer to allow for differentiation between an explicit zero and not specified.
 	FailedBuildsHistoryLimit *int32
+
+	// RetryPolicy describes which failed builds of this build configuration
+	// are retried. Failed builds are not retried if it is not set.
+	RetryPolicy *BuildRetryPolicy
+
+	// Parameters declares the fields of this build configuration that build
+	// requests may override, by the name of their parameter.
+	Parameters []BuildParameter
+}
+
+// BuildRetryPolicy describes which failed builds of a BuildConfig are retried,
+// how many times and how long after they failed.
+type BuildRetryPolicy struct {
+	// Reasons are the retried build failure reasons, DefaultBuildRetryReasons if empty.
+	Reasons []StatusReason
+
+	// MaxAttempts is the maximum number of attempts, including the first build.
+	MaxAttempts int32
+
+	// InitialBackoffSeconds is the delay before the first retry. The delay doubles
+	// with every attempt.
+	InitialBackoffSeconds *int64
+
+	// MaxBackoffSeconds bounds the delay before a retry.
+	MaxBackoffSeconds *int64
+}
+
+// BuildParameterField is a BuildConfig field a BuildParameter overrides.
+type BuildParameterField string
+
+const (
+	// BuildParameterFieldOutputTag overrides the tag of the output image.
+	BuildParameterFieldOutputTag BuildParameterField = "OutputTag"
+	// BuildParameterFieldContextDir overrides the context directory of the source.
+	BuildParameterFieldContextDir BuildParameterField = "ContextDir"
+	// BuildParameterFieldDockerfilePath overrides the Dockerfile path of a Docker strategy.
+	BuildParameterFieldDockerfilePath BuildParameterField = "DockerfilePath"
+	// BuildParameterFieldGitRef overrides the git ref of the source.
+	BuildParameterFieldGitRef BuildParameterField = "GitRef"
+)
+
+// BuildParameter declares a BuildConfig field requests may override. None of the
+// fields change the build strategy, so strategy restrictions are unaffected.
+type BuildParameter struct {
+	// Name identifies the parameter in requests.
+	Name string
+
+	// Field is the overridden field.
+	Field BuildParameterField
+
+	// Pattern is a regular expression the whole value must match. If empty, any
+	// value is allowed.
+	Pattern string
+
+	// Description is a human readable description of the parameter.
+	Description string
 }
 
 // BuildRunPolicy defines the behaviour of how the new builds are executed
@@ -1149,6 +1259,14 @@ type BuildTriggerPolicy struct {
 	// BitbucketWebHook contains the parameters for a Bitbucket webhook type of
 	// trigger
 	BitbucketWebHook *WebHookTrigger
+
+	// GiteaWebHook contains the parameters for a Gitea or Forgejo webhook type of
+	// trigger
+	GiteaWebHook *WebHookTrigger
+
+	// AzureDevOpsWebHook contains the parameters for an Azure DevOps webhook type
+	// of trigger
+	AzureDevOpsWebHook *WebHookTrigger
 }
 
 // BuildTriggerType refers to a specific BuildTriggerPolicy implementation.
@@ -1162,6 +1280,8 @@ var KnownTriggerTypes = sets.NewString(
 	string(ConfigChangeBuildTriggerType),
 	string(GitLabWebHookBuildTriggerType),
 	string(BitbucketWebHookBuildTriggerType),
+	string(GiteaWebHookBuildTriggerType),
+	string(AzureDevOpsWebHookBuildTriggerType),
 )
 
 const (
@@ -1183,6 +1303,14 @@ const (
 	// Bitbucket webhook invocations
 	BitbucketWebHookBuildTriggerType BuildTriggerType = "Bitbucket"
 
+	// GiteaWebHookBuildTriggerType represents a trigger that launches builds on
+	// Gitea or Forgejo webhook invocations
+	GiteaWebHookBuildTriggerType BuildTriggerType = "Gitea"
+
+	// AzureDevOpsWebHookBuildTriggerType represents a trigger that launches builds on
+	// Azure DevOps service hook invocations
+	AzureDevOpsWebHookBuildTriggerType BuildTriggerType = "AzureDevOps"
+
 	// ImageChangeBuildTriggerType represents a trigger that launches builds on
 	// availability of a new version of an image
 	ImageChangeBuildTriggerType           BuildTriggerType = "ImageChange"
@@ -1306,6 +1434,135 @@ type BuildRequest struct {
 
 	// SourceStrategyOptions contains additional source-strategy specific options for the build
 	SourceStrategyOptions *SourceStrategyOptions
+
+	// Parameters maps the names of parameters declared by the build configuration
+	// to the values requested for them.
+	Parameters map[string]string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildCancelRequest is the resource used to cancel a build, or the builds of a
+// build configuration.
+type BuildCancelRequest struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Reason is a brief CamelCase reason for the cancellation. It defaults to
+	// CancelledBuild.
+	Reason string
+
+	// Message is a human readable description of the cancellation.
+	Message string
+
+	// LabelSelector restricts the cancellation of the builds of a build
+	// configuration to the builds matching it.
+	LabelSelector string
+
+	// OlderThanSeconds restricts the cancellation of the builds of a build
+	// configuration to the builds created at least that many seconds ago.
+	OlderThanSeconds *int64
+
+	// GracePeriodSeconds, if set, is the number of seconds the build pod is given
+	// to terminate once the build is cancelled.
+	GracePeriodSeconds *int64
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildStrategyPolicy restricts the build strategies and strategy options of the
+// builds and build configurations in the namespaces it selects.
+type BuildStrategyPolicy struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec BuildStrategyPolicySpec
+}
+
+// BuildStrategyPolicySpec holds the restrictions of a BuildStrategyPolicy.
+type BuildStrategyPolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to. A nil selector
+	// selects every namespace.
+	NamespaceSelector *metav1.LabelSelector
+
+	// AllowedStrategies is the list of strategy types builds may use. An empty list
+	// allows every strategy.
+	AllowedStrategies []BuildStrategyType
+
+	// DisallowNoCache forbids Docker builds without the build cache.
+	DisallowNoCache bool
+
+	// DisallowForcePull forbids builds forcing the pull of their builder or base images.
+	DisallowForcePull bool
+
+	// RequiredImageOptimizationPolicies, if set, requires Docker builds to set an
+	// image optimization policy from the list.
+	RequiredImageOptimizationPolicies []ImageOptimizationPolicy
+
+	// AllowedCustomBuilderImages, if set, is the list of DockerImage references
+	// custom builds may use as builder image. A trailing * matches any reference with
+	// the preceding prefix.
+	AllowedCustomBuilderImages []string
+
+	// ForbiddenBuildVolumeSourceTypes is the list of volume source types build
+	// volumes may not use.
+	ForbiddenBuildVolumeSourceTypes []BuildVolumeSourceType
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildStrategyPolicyList is a collection of BuildStrategyPolicies.
+type BuildStrategyPolicyList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []BuildStrategyPolicy
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildDiff is the difference between a build and another build of its namespace,
+// or the build its build configuration would produce now.
+type BuildDiff struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// From is the build the changes are from.
+	From kapi.ObjectReference
+
+	// To is the build, or the build configuration, the changes are to.
+	To kapi.ObjectReference
+
+	// Changes is the list of fields which differ between the builds.
+	Changes []BuildDiffChange
+}
+
+// BuildDiffChange is a field which differs between two builds.
+type BuildDiffChange struct {
+	// Category groups the change by what it affects.
+	Category string
+
+	// Field is the path of the field which differs.
+	Field string
+
+	// From is the value of the field in the first build, empty if it is not set.
+	From string
+
+	// To is the value of the field in the second build, empty if it is not set.
+	To string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BuildDiffOptions is the REST options for a build diff.
+type BuildDiffOptions struct {
+	metav1.TypeMeta
+
+	// Build is the name of the build to compare with. Without it the build is
+	// compared with the build its build configuration would produce now.
+	Build string
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1343,7 +1600,8 @@ type BinaryBuildRequestOptions struct {
 type BuildLogOptions struct {
 	metav1.TypeMeta
 
-	// Container for which to return logs
+	// Container for which to return logs. It may also be a comma separated list
+	// of build pod containers, build stages and build steps.
 	Container string
 	// Follow if true indicates that the build log should be streamed until
 	// the build terminates.
@@ -1385,8 +1643,22 @@ type BuildLogOptions struct {
 	// connection to the real kubelet is vulnerable to a man in the middle attack (e.g. an attacker could not intercept
 	// the actual log data coming from the real kubelet).
 	InsecureSkipTLSVerifyBackend bool
+
+	// Format selects how the log is returned. Defaults to Text.
+	Format BuildLogFormat
 }
 
+// BuildLogFormat is the format a build log is returned in.
+type BuildLogFormat string
+
+const (
+	// BuildLogFormatText returns the raw log lines.
+	BuildLogFormatText BuildLogFormat = "Text"
+	// BuildLogFormatJSONLines returns every log line as a JSON encoded object
+	// carrying the container, build stage and step it was written by.
+	BuildLogFormatJSONLines BuildLogFormat = "JSONLines"
+)
+
 // SecretSpec specifies a secret to be included in a build pod and its corresponding mount point
 type SecretSpec struct {
 	// SecretSource is a reference to the secret
@@ -243,6 +243,22 @@ func Convert_url_Values_To_v1_BuildLogOptions(in *url.Values, out *v1.BuildLogOp
 			return err
 		}
 	}
+	if values, ok := map[string][]string(*in)["format"]; ok && len(values) > 0 {
+		var format string
+		if err := runtime.Convert_Slice_string_To_string(&values, &format, s); err != nil {
+			return err
+		}
+		out.Format = v1.BuildLogFormat(format)
+	}
+	return nil
+}
+
+func Convert_url_Values_To_v1_BuildDiffOptions(in *url.Values, out *v1.BuildDiffOptions, s conversion.Scope) error {
+	if in == nil || out == nil {
+		return nil
+	}
+	*out = v1.BuildDiffOptions{}
+	out.Build = in.Get("build")
 	return nil
 }
 
@@ -259,6 +275,11 @@ func AddCustomConversionFuncs(scheme *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := scheme.AddConversionFunc((*url.Values)(nil), (*v1.BuildDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_url_Values_To_v1_BuildDiffOptions(a.(*url.Values), b.(*v1.BuildDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
 	return scheme.AddConversionFunc((*url.Values)(nil), (*v1.BuildLogOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_url_Values_To_v1_BuildLogOptions(a.(*url.Values), b.(*v1.BuildLogOptions), scope)
 	})
@@ -406,6 +406,7 @@ func TestBuildLogOptionsOptions(t *testing.T) {
 		Timestamps:   true,
 		TailLines:    &tailLines,
 		LimitBytes:   &limitBytes,
+		Format:       internal.BuildLogFormatJSONLines,
 	}
 	versionedBuildLogOptions, err := scheme.ConvertToVersion(unversionedBuildLogOptions, v1.GroupVersion)
 	if err != nil {
@@ -27,6 +27,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1.AzureDevOpsWebHookCause)(nil), (*build.AzureDevOpsWebHookCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause(a.(*v1.AzureDevOpsWebHookCause), b.(*build.AzureDevOpsWebHookCause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.AzureDevOpsWebHookCause)(nil), (*v1.AzureDevOpsWebHookCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause(a.(*build.AzureDevOpsWebHookCause), b.(*v1.AzureDevOpsWebHookCause), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BinaryBuildRequestOptions)(nil), (*build.BinaryBuildRequestOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BinaryBuildRequestOptions_To_build_BinaryBuildRequestOptions(a.(*v1.BinaryBuildRequestOptions), b.(*build.BinaryBuildRequestOptions), scope)
 	}); err != nil {
@@ -67,6 +77,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildCancelRequest)(nil), (*build.BuildCancelRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildCancelRequest_To_build_BuildCancelRequest(a.(*v1.BuildCancelRequest), b.(*build.BuildCancelRequest), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildCancelRequest)(nil), (*v1.BuildCancelRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildCancelRequest_To_v1_BuildCancelRequest(a.(*build.BuildCancelRequest), b.(*v1.BuildCancelRequest), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BuildCondition)(nil), (*build.BuildCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BuildCondition_To_build_BuildCondition(a.(*v1.BuildCondition), b.(*build.BuildCondition), scope)
 	}); err != nil {
@@ -112,6 +132,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildDiff)(nil), (*build.BuildDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildDiff_To_build_BuildDiff(a.(*v1.BuildDiff), b.(*build.BuildDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildDiff)(nil), (*v1.BuildDiff)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildDiff_To_v1_BuildDiff(a.(*build.BuildDiff), b.(*v1.BuildDiff), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildDiffChange)(nil), (*build.BuildDiffChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildDiffChange_To_build_BuildDiffChange(a.(*v1.BuildDiffChange), b.(*build.BuildDiffChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildDiffChange)(nil), (*v1.BuildDiffChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildDiffChange_To_v1_BuildDiffChange(a.(*build.BuildDiffChange), b.(*v1.BuildDiffChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildDiffOptions)(nil), (*build.BuildDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildDiffOptions_To_build_BuildDiffOptions(a.(*v1.BuildDiffOptions), b.(*build.BuildDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildDiffOptions)(nil), (*v1.BuildDiffOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildDiffOptions_To_v1_BuildDiffOptions(a.(*build.BuildDiffOptions), b.(*v1.BuildDiffOptions), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BuildList)(nil), (*build.BuildList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BuildList_To_build_BuildList(a.(*v1.BuildList), b.(*build.BuildList), scope)
 	}); err != nil {
@@ -147,6 +197,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildParameter)(nil), (*build.BuildParameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildParameter_To_build_BuildParameter(a.(*v1.BuildParameter), b.(*build.BuildParameter), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildParameter)(nil), (*v1.BuildParameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildParameter_To_v1_BuildParameter(a.(*build.BuildParameter), b.(*v1.BuildParameter), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BuildPostCommitSpec)(nil), (*build.BuildPostCommitSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BuildPostCommitSpec_To_build_BuildPostCommitSpec(a.(*v1.BuildPostCommitSpec), b.(*build.BuildPostCommitSpec), scope)
 	}); err != nil {
@@ -167,6 +227,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildRetryCause)(nil), (*build.BuildRetryCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildRetryCause_To_build_BuildRetryCause(a.(*v1.BuildRetryCause), b.(*build.BuildRetryCause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildRetryCause)(nil), (*v1.BuildRetryCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildRetryCause_To_v1_BuildRetryCause(a.(*build.BuildRetryCause), b.(*v1.BuildRetryCause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildRetryPolicy)(nil), (*build.BuildRetryPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy(a.(*v1.BuildRetryPolicy), b.(*build.BuildRetryPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildRetryPolicy)(nil), (*v1.BuildRetryPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildRetryPolicy_To_v1_BuildRetryPolicy(a.(*build.BuildRetryPolicy), b.(*v1.BuildRetryPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BuildSource)(nil), (*build.BuildSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BuildSource_To_build_BuildSource(a.(*v1.BuildSource), b.(*build.BuildSource), scope)
 	}); err != nil {
@@ -217,6 +297,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildStrategyPolicy)(nil), (*build.BuildStrategyPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildStrategyPolicy_To_build_BuildStrategyPolicy(a.(*v1.BuildStrategyPolicy), b.(*build.BuildStrategyPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildStrategyPolicy)(nil), (*v1.BuildStrategyPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildStrategyPolicy_To_v1_BuildStrategyPolicy(a.(*build.BuildStrategyPolicy), b.(*v1.BuildStrategyPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildStrategyPolicyList)(nil), (*build.BuildStrategyPolicyList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildStrategyPolicyList_To_build_BuildStrategyPolicyList(a.(*v1.BuildStrategyPolicyList), b.(*build.BuildStrategyPolicyList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildStrategyPolicyList)(nil), (*v1.BuildStrategyPolicyList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildStrategyPolicyList_To_v1_BuildStrategyPolicyList(a.(*build.BuildStrategyPolicyList), b.(*v1.BuildStrategyPolicyList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.BuildStrategyPolicySpec)(nil), (*build.BuildStrategyPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec(a.(*v1.BuildStrategyPolicySpec), b.(*build.BuildStrategyPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.BuildStrategyPolicySpec)(nil), (*v1.BuildStrategyPolicySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec(a.(*build.BuildStrategyPolicySpec), b.(*v1.BuildStrategyPolicySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.BuildTriggerCause)(nil), (*build.BuildTriggerCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_BuildTriggerCause_To_build_BuildTriggerCause(a.(*v1.BuildTriggerCause), b.(*build.BuildTriggerCause), scope)
 	}); err != nil {
@@ -392,6 +502,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.GiteaWebHookCause)(nil), (*build.GiteaWebHookCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause(a.(*v1.GiteaWebHookCause), b.(*build.GiteaWebHookCause), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*build.GiteaWebHookCause)(nil), (*v1.GiteaWebHookCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause(a.(*build.GiteaWebHookCause), b.(*v1.GiteaWebHookCause), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.ImageChangeCause)(nil), (*build.ImageChangeCause)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ImageChangeCause_To_build_ImageChangeCause(a.(*v1.ImageChangeCause), b.(*build.ImageChangeCause), scope)
 	}); err != nil {
@@ -635,6 +755,30 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause(in *v1.AzureDevOpsWebHookCause, out *build.AzureDevOpsWebHookCause, s conversion.Scope) error {
+	if err := Convert_v1_CommonWebHookCause_To_build_CommonWebHookCause(&in.CommonWebHookCause, &out.CommonWebHookCause, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause is an autogenerated conversion function.
+func Convert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause(in *v1.AzureDevOpsWebHookCause, out *build.AzureDevOpsWebHookCause, s conversion.Scope) error {
+	return autoConvert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause(in, out, s)
+}
+
+func autoConvert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause(in *build.AzureDevOpsWebHookCause, out *v1.AzureDevOpsWebHookCause, s conversion.Scope) error {
+	if err := Convert_build_CommonWebHookCause_To_v1_CommonWebHookCause(&in.CommonWebHookCause, &out.CommonWebHookCause, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause is an autogenerated conversion function.
+func Convert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause(in *build.AzureDevOpsWebHookCause, out *v1.AzureDevOpsWebHookCause, s conversion.Scope) error {
+	return autoConvert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause(in, out, s)
+}
+
 func autoConvert_v1_BinaryBuildRequestOptions_To_build_BinaryBuildRequestOptions(in *v1.BinaryBuildRequestOptions, out *build.BinaryBuildRequestOptions, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	out.AsFile = in.AsFile
@@ -745,6 +889,36 @@ func Convert_build_Build_To_v1_Build(in *build.Build, out *v1.Build, s conversio
 	return autoConvert_build_Build_To_v1_Build(in, out, s)
 }
 
+func autoConvert_v1_BuildCancelRequest_To_build_BuildCancelRequest(in *v1.BuildCancelRequest, out *build.BuildCancelRequest, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.LabelSelector = in.LabelSelector
+	out.OlderThanSeconds = (*int64)(unsafe.Pointer(in.OlderThanSeconds))
+	out.GracePeriodSeconds = (*int64)(unsafe.Pointer(in.GracePeriodSeconds))
+	return nil
+}
+
+// Convert_v1_BuildCancelRequest_To_build_BuildCancelRequest is an autogenerated conversion function.
+func Convert_v1_BuildCancelRequest_To_build_BuildCancelRequest(in *v1.BuildCancelRequest, out *build.BuildCancelRequest, s conversion.Scope) error {
+	return autoConvert_v1_BuildCancelRequest_To_build_BuildCancelRequest(in, out, s)
+}
+
+func autoConvert_build_BuildCancelRequest_To_v1_BuildCancelRequest(in *build.BuildCancelRequest, out *v1.BuildCancelRequest, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.LabelSelector = in.LabelSelector
+	out.OlderThanSeconds = (*int64)(unsafe.Pointer(in.OlderThanSeconds))
+	out.GracePeriodSeconds = (*int64)(unsafe.Pointer(in.GracePeriodSeconds))
+	return nil
+}
+
+// Convert_build_BuildCancelRequest_To_v1_BuildCancelRequest is an autogenerated conversion function.
+func Convert_build_BuildCancelRequest_To_v1_BuildCancelRequest(in *build.BuildCancelRequest, out *v1.BuildCancelRequest, s conversion.Scope) error {
+	return autoConvert_build_BuildCancelRequest_To_v1_BuildCancelRequest(in, out, s)
+}
+
 func autoConvert_v1_BuildCondition_To_build_BuildCondition(in *v1.BuildCondition, out *build.BuildCondition, s conversion.Scope) error {
 	out.Type = build.BuildConditionType(in.Type)
 	out.Status = core.ConditionStatus(in.Status)
@@ -862,6 +1036,8 @@ func autoConvert_v1_BuildConfigSpec_To_build_BuildConfigSpec(in *v1.BuildConfigS
 	}
 	out.SuccessfulBuildsHistoryLimit = (*int32)(unsafe.Pointer(in.SuccessfulBuildsHistoryLimit))
 	out.FailedBuildsHistoryLimit = (*int32)(unsafe.Pointer(in.FailedBuildsHistoryLimit))
+	out.RetryPolicy = (*build.BuildRetryPolicy)(unsafe.Pointer(in.RetryPolicy))
+	out.Parameters = *(*[]build.BuildParameter)(unsafe.Pointer(&in.Parameters))
 	return nil
 }
 
@@ -888,6 +1064,8 @@ func autoConvert_build_BuildConfigSpec_To_v1_BuildConfigSpec(in *build.BuildConf
 	}
 	out.SuccessfulBuildsHistoryLimit = (*int32)(unsafe.Pointer(in.SuccessfulBuildsHistoryLimit))
 	out.FailedBuildsHistoryLimit = (*int32)(unsafe.Pointer(in.FailedBuildsHistoryLimit))
+	out.RetryPolicy = (*v1.BuildRetryPolicy)(unsafe.Pointer(in.RetryPolicy))
+	out.Parameters = *(*[]v1.BuildParameter)(unsafe.Pointer(&in.Parameters))
 	return nil
 }
 
@@ -918,6 +1096,86 @@ func Convert_build_BuildConfigStatus_To_v1_BuildConfigStatus(in *build.BuildConf
 	return autoConvert_build_BuildConfigStatus_To_v1_BuildConfigStatus(in, out, s)
 }
 
+func autoConvert_v1_BuildDiff_To_build_BuildDiff(in *v1.BuildDiff, out *build.BuildDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := apiscorev1.Convert_v1_ObjectReference_To_core_ObjectReference(&in.From, &out.From, s); err != nil {
+		return err
+	}
+	if err := apiscorev1.Convert_v1_ObjectReference_To_core_ObjectReference(&in.To, &out.To, s); err != nil {
+		return err
+	}
+	out.Changes = *(*[]build.BuildDiffChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_v1_BuildDiff_To_build_BuildDiff is an autogenerated conversion function.
+func Convert_v1_BuildDiff_To_build_BuildDiff(in *v1.BuildDiff, out *build.BuildDiff, s conversion.Scope) error {
+	return autoConvert_v1_BuildDiff_To_build_BuildDiff(in, out, s)
+}
+
+func autoConvert_build_BuildDiff_To_v1_BuildDiff(in *build.BuildDiff, out *v1.BuildDiff, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := apiscorev1.Convert_core_ObjectReference_To_v1_ObjectReference(&in.From, &out.From, s); err != nil {
+		return err
+	}
+	if err := apiscorev1.Convert_core_ObjectReference_To_v1_ObjectReference(&in.To, &out.To, s); err != nil {
+		return err
+	}
+	out.Changes = *(*[]v1.BuildDiffChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_build_BuildDiff_To_v1_BuildDiff is an autogenerated conversion function.
+func Convert_build_BuildDiff_To_v1_BuildDiff(in *build.BuildDiff, out *v1.BuildDiff, s conversion.Scope) error {
+	return autoConvert_build_BuildDiff_To_v1_BuildDiff(in, out, s)
+}
+
+func autoConvert_v1_BuildDiffChange_To_build_BuildDiffChange(in *v1.BuildDiffChange, out *build.BuildDiffChange, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Field = in.Field
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_v1_BuildDiffChange_To_build_BuildDiffChange is an autogenerated conversion function.
+func Convert_v1_BuildDiffChange_To_build_BuildDiffChange(in *v1.BuildDiffChange, out *build.BuildDiffChange, s conversion.Scope) error {
+	return autoConvert_v1_BuildDiffChange_To_build_BuildDiffChange(in, out, s)
+}
+
+func autoConvert_build_BuildDiffChange_To_v1_BuildDiffChange(in *build.BuildDiffChange, out *v1.BuildDiffChange, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Field = in.Field
+	out.From = in.From
+	out.To = in.To
+	return nil
+}
+
+// Convert_build_BuildDiffChange_To_v1_BuildDiffChange is an autogenerated conversion function.
+func Convert_build_BuildDiffChange_To_v1_BuildDiffChange(in *build.BuildDiffChange, out *v1.BuildDiffChange, s conversion.Scope) error {
+	return autoConvert_build_BuildDiffChange_To_v1_BuildDiffChange(in, out, s)
+}
+
+func autoConvert_v1_BuildDiffOptions_To_build_BuildDiffOptions(in *v1.BuildDiffOptions, out *build.BuildDiffOptions, s conversion.Scope) error {
+	out.Build = in.Build
+	return nil
+}
+
+// Convert_v1_BuildDiffOptions_To_build_BuildDiffOptions is an autogenerated conversion function.
+func Convert_v1_BuildDiffOptions_To_build_BuildDiffOptions(in *v1.BuildDiffOptions, out *build.BuildDiffOptions, s conversion.Scope) error {
+	return autoConvert_v1_BuildDiffOptions_To_build_BuildDiffOptions(in, out, s)
+}
+
+func autoConvert_build_BuildDiffOptions_To_v1_BuildDiffOptions(in *build.BuildDiffOptions, out *v1.BuildDiffOptions, s conversion.Scope) error {
+	out.Build = in.Build
+	return nil
+}
+
+// Convert_build_BuildDiffOptions_To_v1_BuildDiffOptions is an autogenerated conversion function.
+func Convert_build_BuildDiffOptions_To_v1_BuildDiffOptions(in *build.BuildDiffOptions, out *v1.BuildDiffOptions, s conversion.Scope) error {
+	return autoConvert_build_BuildDiffOptions_To_v1_BuildDiffOptions(in, out, s)
+}
+
 func autoConvert_v1_BuildList_To_build_BuildList(in *v1.BuildList, out *build.BuildList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -990,6 +1248,7 @@ func autoConvert_v1_BuildLogOptions_To_build_BuildLogOptions(in *v1.BuildLogOpti
 	out.NoWait = in.NoWait
 	out.Version = (*int64)(unsafe.Pointer(in.Version))
 	out.InsecureSkipTLSVerifyBackend = in.InsecureSkipTLSVerifyBackend
+	out.Format = build.BuildLogFormat(in.Format)
 	return nil
 }
 
@@ -1010,6 +1269,7 @@ func autoConvert_build_BuildLogOptions_To_v1_BuildLogOptions(in *build.BuildLogO
 	out.NoWait = in.NoWait
 	out.Version = (*int64)(unsafe.Pointer(in.Version))
 	out.InsecureSkipTLSVerifyBackend = in.InsecureSkipTLSVerifyBackend
+	out.Format = v1.BuildLogFormat(in.Format)
 	return nil
 }
 
@@ -1038,6 +1298,7 @@ func autoConvert_v1_BuildOutput_To_build_BuildOutput(in *v1.BuildOutput, out *bu
 		out.PushSecret = nil
 	}
 	out.ImageLabels = *(*[]build.ImageLabel)(unsafe.Pointer(&in.ImageLabels))
+	out.AdditionalTags = *(*[]string)(unsafe.Pointer(&in.AdditionalTags))
 	return nil
 }
 
@@ -1061,6 +1322,7 @@ func autoConvert_build_BuildOutput_To_v1_BuildOutput(in *build.BuildOutput, out
 		out.PushSecret = nil
 	}
 	out.ImageLabels = *(*[]v1.ImageLabel)(unsafe.Pointer(&in.ImageLabels))
+	out.AdditionalTags = *(*[]string)(unsafe.Pointer(&in.AdditionalTags))
 	return nil
 }
 
@@ -1069,6 +1331,32 @@ func Convert_build_BuildOutput_To_v1_BuildOutput(in *build.BuildOutput, out *v1.
 	return autoConvert_build_BuildOutput_To_v1_BuildOutput(in, out, s)
 }
 
+func autoConvert_v1_BuildParameter_To_build_BuildParameter(in *v1.BuildParameter, out *build.BuildParameter, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Field = build.BuildParameterField(in.Field)
+	out.Pattern = in.Pattern
+	out.Description = in.Description
+	return nil
+}
+
+// Convert_v1_BuildParameter_To_build_BuildParameter is an autogenerated conversion function.
+func Convert_v1_BuildParameter_To_build_BuildParameter(in *v1.BuildParameter, out *build.BuildParameter, s conversion.Scope) error {
+	return autoConvert_v1_BuildParameter_To_build_BuildParameter(in, out, s)
+}
+
+func autoConvert_build_BuildParameter_To_v1_BuildParameter(in *build.BuildParameter, out *v1.BuildParameter, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Field = v1.BuildParameterField(in.Field)
+	out.Pattern = in.Pattern
+	out.Description = in.Description
+	return nil
+}
+
+// Convert_build_BuildParameter_To_v1_BuildParameter is an autogenerated conversion function.
+func Convert_build_BuildParameter_To_v1_BuildParameter(in *build.BuildParameter, out *v1.BuildParameter, s conversion.Scope) error {
+	return autoConvert_build_BuildParameter_To_v1_BuildParameter(in, out, s)
+}
+
 func autoConvert_v1_BuildPostCommitSpec_To_build_BuildPostCommitSpec(in *v1.BuildPostCommitSpec, out *build.BuildPostCommitSpec, s conversion.Scope) error {
 	out.Command = *(*[]string)(unsafe.Pointer(&in.Command))
 	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
@@ -1156,6 +1444,7 @@ func autoConvert_v1_BuildRequest_To_build_BuildRequest(in *v1.BuildRequest, out
 		out.DockerStrategyOptions = nil
 	}
 	out.SourceStrategyOptions = (*build.SourceStrategyOptions)(unsafe.Pointer(in.SourceStrategyOptions))
+	out.Parameters = *(*map[string]string)(unsafe.Pointer(&in.Parameters))
 	return nil
 }
 
@@ -1227,6 +1516,7 @@ func autoConvert_build_BuildRequest_To_v1_BuildRequest(in *build.BuildRequest, o
 		out.DockerStrategyOptions = nil
 	}
 	out.SourceStrategyOptions = (*v1.SourceStrategyOptions)(unsafe.Pointer(in.SourceStrategyOptions))
+	out.Parameters = *(*map[string]string)(unsafe.Pointer(&in.Parameters))
 	return nil
 }
 
@@ -1235,6 +1525,56 @@ func Convert_build_BuildRequest_To_v1_BuildRequest(in *build.BuildRequest, out *
 	return autoConvert_build_BuildRequest_To_v1_BuildRequest(in, out, s)
 }
 
+func autoConvert_v1_BuildRetryCause_To_build_BuildRetryCause(in *v1.BuildRetryCause, out *build.BuildRetryCause, s conversion.Scope) error {
+	out.BuildName = in.BuildName
+	out.Attempt = in.Attempt
+	out.Reason = build.StatusReason(in.Reason)
+	return nil
+}
+
+// Convert_v1_BuildRetryCause_To_build_BuildRetryCause is an autogenerated conversion function.
+func Convert_v1_BuildRetryCause_To_build_BuildRetryCause(in *v1.BuildRetryCause, out *build.BuildRetryCause, s conversion.Scope) error {
+	return autoConvert_v1_BuildRetryCause_To_build_BuildRetryCause(in, out, s)
+}
+
+func autoConvert_build_BuildRetryCause_To_v1_BuildRetryCause(in *build.BuildRetryCause, out *v1.BuildRetryCause, s conversion.Scope) error {
+	out.BuildName = in.BuildName
+	out.Attempt = in.Attempt
+	out.Reason = v1.StatusReason(in.Reason)
+	return nil
+}
+
+// Convert_build_BuildRetryCause_To_v1_BuildRetryCause is an autogenerated conversion function.
+func Convert_build_BuildRetryCause_To_v1_BuildRetryCause(in *build.BuildRetryCause, out *v1.BuildRetryCause, s conversion.Scope) error {
+	return autoConvert_build_BuildRetryCause_To_v1_BuildRetryCause(in, out, s)
+}
+
+func autoConvert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy(in *v1.BuildRetryPolicy, out *build.BuildRetryPolicy, s conversion.Scope) error {
+	out.Reasons = *(*[]build.StatusReason)(unsafe.Pointer(&in.Reasons))
+	out.MaxAttempts = in.MaxAttempts
+	out.InitialBackoffSeconds = (*int64)(unsafe.Pointer(in.InitialBackoffSeconds))
+	out.MaxBackoffSeconds = (*int64)(unsafe.Pointer(in.MaxBackoffSeconds))
+	return nil
+}
+
+// Convert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy is an autogenerated conversion function.
+func Convert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy(in *v1.BuildRetryPolicy, out *build.BuildRetryPolicy, s conversion.Scope) error {
+	return autoConvert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy(in, out, s)
+}
+
+func autoConvert_build_BuildRetryPolicy_To_v1_BuildRetryPolicy(in *build.BuildRetryPolicy, out *v1.BuildRetryPolicy, s conversion.Scope) error {
+	out.Reasons = *(*[]v1.StatusReason)(unsafe.Pointer(&in.Reasons))
+	out.MaxAttempts = in.MaxAttempts
+	out.InitialBackoffSeconds = (*int64)(unsafe.Pointer(in.InitialBackoffSeconds))
+	out.MaxBackoffSeconds = (*int64)(unsafe.Pointer(in.MaxBackoffSeconds))
+	return nil
+}
+
+// Convert_build_BuildRetryPolicy_To_v1_BuildRetryPolicy is an autogenerated conversion function.
+func Convert_build_BuildRetryPolicy_To_v1_BuildRetryPolicy(in *build.BuildRetryPolicy, out *v1.BuildRetryPolicy, s conversion.Scope) error {
+	return autoConvert_build_BuildRetryPolicy_To_v1_BuildRetryPolicy(in, out, s)
+}
+
 func autoConvert_v1_BuildSource_To_build_BuildSource(in *v1.BuildSource, out *build.BuildSource, s conversion.Scope) error {
 	// INFO: in.Type opted out of conversion generation
 	out.Binary = (*build.BinaryBuildSource)(unsafe.Pointer(in.Binary))
@@ -1577,6 +1917,86 @@ func autoConvert_build_BuildStrategy_To_v1_BuildStrategy(in *build.BuildStrategy
 	return nil
 }
 
+func autoConvert_v1_BuildStrategyPolicy_To_build_BuildStrategyPolicy(in *v1.BuildStrategyPolicy, out *build.BuildStrategyPolicy, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_BuildStrategyPolicy_To_build_BuildStrategyPolicy is an autogenerated conversion function.
+func Convert_v1_BuildStrategyPolicy_To_build_BuildStrategyPolicy(in *v1.BuildStrategyPolicy, out *build.BuildStrategyPolicy, s conversion.Scope) error {
+	return autoConvert_v1_BuildStrategyPolicy_To_build_BuildStrategyPolicy(in, out, s)
+}
+
+func autoConvert_build_BuildStrategyPolicy_To_v1_BuildStrategyPolicy(in *build.BuildStrategyPolicy, out *v1.BuildStrategyPolicy, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_build_BuildStrategyPolicy_To_v1_BuildStrategyPolicy is an autogenerated conversion function.
+func Convert_build_BuildStrategyPolicy_To_v1_BuildStrategyPolicy(in *build.BuildStrategyPolicy, out *v1.BuildStrategyPolicy, s conversion.Scope) error {
+	return autoConvert_build_BuildStrategyPolicy_To_v1_BuildStrategyPolicy(in, out, s)
+}
+
+func autoConvert_v1_BuildStrategyPolicyList_To_build_BuildStrategyPolicyList(in *v1.BuildStrategyPolicyList, out *build.BuildStrategyPolicyList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]build.BuildStrategyPolicy)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1_BuildStrategyPolicyList_To_build_BuildStrategyPolicyList is an autogenerated conversion function.
+func Convert_v1_BuildStrategyPolicyList_To_build_BuildStrategyPolicyList(in *v1.BuildStrategyPolicyList, out *build.BuildStrategyPolicyList, s conversion.Scope) error {
+	return autoConvert_v1_BuildStrategyPolicyList_To_build_BuildStrategyPolicyList(in, out, s)
+}
+
+func autoConvert_build_BuildStrategyPolicyList_To_v1_BuildStrategyPolicyList(in *build.BuildStrategyPolicyList, out *v1.BuildStrategyPolicyList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1.BuildStrategyPolicy)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_build_BuildStrategyPolicyList_To_v1_BuildStrategyPolicyList is an autogenerated conversion function.
+func Convert_build_BuildStrategyPolicyList_To_v1_BuildStrategyPolicyList(in *build.BuildStrategyPolicyList, out *v1.BuildStrategyPolicyList, s conversion.Scope) error {
+	return autoConvert_build_BuildStrategyPolicyList_To_v1_BuildStrategyPolicyList(in, out, s)
+}
+
+func autoConvert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec(in *v1.BuildStrategyPolicySpec, out *build.BuildStrategyPolicySpec, s conversion.Scope) error {
+	out.NamespaceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NamespaceSelector))
+	out.AllowedStrategies = *(*[]build.BuildStrategyType)(unsafe.Pointer(&in.AllowedStrategies))
+	out.DisallowNoCache = in.DisallowNoCache
+	out.DisallowForcePull = in.DisallowForcePull
+	out.RequiredImageOptimizationPolicies = *(*[]build.ImageOptimizationPolicy)(unsafe.Pointer(&in.RequiredImageOptimizationPolicies))
+	out.AllowedCustomBuilderImages = *(*[]string)(unsafe.Pointer(&in.AllowedCustomBuilderImages))
+	out.ForbiddenBuildVolumeSourceTypes = *(*[]build.BuildVolumeSourceType)(unsafe.Pointer(&in.ForbiddenBuildVolumeSourceTypes))
+	return nil
+}
+
+// Convert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec is an autogenerated conversion function.
+func Convert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec(in *v1.BuildStrategyPolicySpec, out *build.BuildStrategyPolicySpec, s conversion.Scope) error {
+	return autoConvert_v1_BuildStrategyPolicySpec_To_build_BuildStrategyPolicySpec(in, out, s)
+}
+
+func autoConvert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec(in *build.BuildStrategyPolicySpec, out *v1.BuildStrategyPolicySpec, s conversion.Scope) error {
+	out.NamespaceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.NamespaceSelector))
+	out.AllowedStrategies = *(*[]v1.BuildStrategyType)(unsafe.Pointer(&in.AllowedStrategies))
+	out.DisallowNoCache = in.DisallowNoCache
+	out.DisallowForcePull = in.DisallowForcePull
+	out.RequiredImageOptimizationPolicies = *(*[]v1.ImageOptimizationPolicy)(unsafe.Pointer(&in.RequiredImageOptimizationPolicies))
+	out.AllowedCustomBuilderImages = *(*[]string)(unsafe.Pointer(&in.AllowedCustomBuilderImages))
+	out.ForbiddenBuildVolumeSourceTypes = *(*[]v1.BuildVolumeSourceType)(unsafe.Pointer(&in.ForbiddenBuildVolumeSourceTypes))
+	return nil
+}
+
+// Convert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec is an autogenerated conversion function.
+func Convert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec(in *build.BuildStrategyPolicySpec, out *v1.BuildStrategyPolicySpec, s conversion.Scope) error {
+	return autoConvert_build_BuildStrategyPolicySpec_To_v1_BuildStrategyPolicySpec(in, out, s)
+}
+
 func autoConvert_v1_BuildTriggerCause_To_build_BuildTriggerCause(in *v1.BuildTriggerCause, out *build.BuildTriggerCause, s conversion.Scope) error {
 	out.Message = in.Message
 	if in.GenericWebHook != nil {
@@ -1624,6 +2044,25 @@ func autoConvert_v1_BuildTriggerCause_To_build_BuildTriggerCause(in *v1.BuildTri
 	} else {
 		out.BitbucketWebHook = nil
 	}
+	if in.GiteaWebHook != nil {
+		in, out := &in.GiteaWebHook, &out.GiteaWebHook
+		*out = new(build.GiteaWebHookCause)
+		if err := Convert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GiteaWebHook = nil
+	}
+	if in.AzureDevOpsWebHook != nil {
+		in, out := &in.AzureDevOpsWebHook, &out.AzureDevOpsWebHook
+		*out = new(build.AzureDevOpsWebHookCause)
+		if err := Convert_v1_AzureDevOpsWebHookCause_To_build_AzureDevOpsWebHookCause(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AzureDevOpsWebHook = nil
+	}
+	out.BuildRetry = (*build.BuildRetryCause)(unsafe.Pointer(in.BuildRetry))
 	return nil
 }
 
@@ -1679,6 +2118,25 @@ func autoConvert_build_BuildTriggerCause_To_v1_BuildTriggerCause(in *build.Build
 	} else {
 		out.BitbucketWebHook = nil
 	}
+	if in.GiteaWebHook != nil {
+		in, out := &in.GiteaWebHook, &out.GiteaWebHook
+		*out = new(v1.GiteaWebHookCause)
+		if err := Convert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.GiteaWebHook = nil
+	}
+	if in.AzureDevOpsWebHook != nil {
+		in, out := &in.AzureDevOpsWebHook, &out.AzureDevOpsWebHook
+		*out = new(v1.AzureDevOpsWebHookCause)
+		if err := Convert_build_AzureDevOpsWebHookCause_To_v1_AzureDevOpsWebHookCause(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.AzureDevOpsWebHook = nil
+	}
+	out.BuildRetry = (*v1.BuildRetryCause)(unsafe.Pointer(in.BuildRetry))
 	return nil
 }
 
@@ -1702,6 +2160,8 @@ func autoConvert_v1_BuildTriggerPolicy_To_build_BuildTriggerPolicy(in *v1.BuildT
 	}
 	out.GitLabWebHook = (*build.WebHookTrigger)(unsafe.Pointer(in.GitLabWebHook))
 	out.BitbucketWebHook = (*build.WebHookTrigger)(unsafe.Pointer(in.BitbucketWebHook))
+	out.GiteaWebHook = (*build.WebHookTrigger)(unsafe.Pointer(in.GiteaWebHook))
+	out.AzureDevOpsWebHook = (*build.WebHookTrigger)(unsafe.Pointer(in.AzureDevOpsWebHook))
 	return nil
 }
 
@@ -1720,6 +2180,8 @@ func autoConvert_build_BuildTriggerPolicy_To_v1_BuildTriggerPolicy(in *build.Bui
 	}
 	out.GitLabWebHook = (*v1.WebHookTrigger)(unsafe.Pointer(in.GitLabWebHook))
 	out.BitbucketWebHook = (*v1.WebHookTrigger)(unsafe.Pointer(in.BitbucketWebHook))
+	out.GiteaWebHook = (*v1.WebHookTrigger)(unsafe.Pointer(in.GiteaWebHook))
+	out.AzureDevOpsWebHook = (*v1.WebHookTrigger)(unsafe.Pointer(in.AzureDevOpsWebHook))
 	return nil
 }
 
@@ -2525,6 +2987,30 @@ func Convert_build_GitSourceRevision_To_v1_GitSourceRevision(in *build.GitSource
 	return autoConvert_build_GitSourceRevision_To_v1_GitSourceRevision(in, out, s)
 }
 
+func autoConvert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause(in *v1.GiteaWebHookCause, out *build.GiteaWebHookCause, s conversion.Scope) error {
+	if err := Convert_v1_CommonWebHookCause_To_build_CommonWebHookCause(&in.CommonWebHookCause, &out.CommonWebHookCause, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause is an autogenerated conversion function.
+func Convert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause(in *v1.GiteaWebHookCause, out *build.GiteaWebHookCause, s conversion.Scope) error {
+	return autoConvert_v1_GiteaWebHookCause_To_build_GiteaWebHookCause(in, out, s)
+}
+
+func autoConvert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause(in *build.GiteaWebHookCause, out *v1.GiteaWebHookCause, s conversion.Scope) error {
+	if err := Convert_build_CommonWebHookCause_To_v1_CommonWebHookCause(&in.CommonWebHookCause, &out.CommonWebHookCause, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause is an autogenerated conversion function.
+func Convert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause(in *build.GiteaWebHookCause, out *v1.GiteaWebHookCause, s conversion.Scope) error {
+	return autoConvert_build_GiteaWebHookCause_To_v1_GiteaWebHookCause(in, out, s)
+}
+
 func autoConvert_v1_ImageChangeCause_To_build_ImageChangeCause(in *v1.ImageChangeCause, out *build.ImageChangeCause, s conversion.Scope) error {
 	out.ImageID = in.ImageID
 	if in.FromRef != nil {
@@ -5,12 +5,15 @@ import (
 	"net/url"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"k8s.io/klog/v2"
 
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	kpath "k8s.io/apimachinery/pkg/api/validation/path"
+	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
@@ -118,11 +121,70 @@ func ValidateBuildConfig(config *buildapi.BuildConfig) field.ErrorList {
 		allErrs = append(allErrs, validation.ValidateNonnegativeField(int64(*config.Spec.FailedBuildsHistoryLimit), specPath.Child("failedBuildsHistoryLimit"))...)
 	}
 
+	allErrs = append(allErrs, validateBuildParameters(config.Spec.Parameters, specPath.Child("parameters"))...)
+	if config.Spec.RetryPolicy != nil {
+		allErrs = append(allErrs, validateBuildRetryPolicy(config.Spec.RetryPolicy, specPath.Child("retryPolicy"))...)
+	}
+
 	allErrs = append(allErrs, validateCommonSpec(&config.Spec.CommonSpec, specPath)...)
 
 	return allErrs
 }
 
+var validBuildParameterFields = sets.NewString(
+	string(buildapi.BuildParameterFieldOutputTag),
+	string(buildapi.BuildParameterFieldContextDir),
+	string(buildapi.BuildParameterFieldDockerfilePath),
+	string(buildapi.BuildParameterFieldGitRef),
+)
+
+func validateBuildParameters(parameters []buildapi.BuildParameter, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, parameter := range parameters {
+		idxPath := fldPath.Index(i)
+		switch {
+		case !buildapi.IsBuildParameterName(parameter.Name):
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), parameter.Name,
+				"must start with a letter and consist of at most 63 letters, digits, '_' or '-'"))
+		case names.Has(parameter.Name):
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), parameter.Name))
+		}
+		names.Insert(parameter.Name)
+		if !validBuildParameterFields.Has(string(parameter.Field)) {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("field"), parameter.Field, validBuildParameterFields.List()))
+		}
+		if _, err := buildapi.CompileBuildParameterPattern(parameter.Pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("pattern"), parameter.Pattern, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateBuildRetryPolicy(policy *buildapi.BuildRetryPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, reason := range policy.Reasons {
+		if len(reason) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("reasons").Index(i), ""))
+		}
+	}
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > buildapi.MaxBuildRetryAttempts {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxAttempts"), policy.MaxAttempts,
+			fmt.Sprintf("must be between 1 and %d", buildapi.MaxBuildRetryAttempts)))
+	}
+	if policy.InitialBackoffSeconds != nil {
+		allErrs = append(allErrs, validation.ValidateNonnegativeField(*policy.InitialBackoffSeconds, fldPath.Child("initialBackoffSeconds"))...)
+	}
+	if policy.MaxBackoffSeconds != nil {
+		allErrs = append(allErrs, validation.ValidateNonnegativeField(*policy.MaxBackoffSeconds, fldPath.Child("maxBackoffSeconds"))...)
+		if policy.InitialBackoffSeconds != nil && *policy.MaxBackoffSeconds < *policy.InitialBackoffSeconds {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxBackoffSeconds"), *policy.MaxBackoffSeconds,
+				"must not be less than initialBackoffSeconds"))
+		}
+	}
+	return allErrs
+}
+
 func ValidateBuildConfigUpdate(config *buildapi.BuildConfig, older *buildapi.BuildConfig) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validation.ValidateObjectMetaUpdate(&config.ObjectMeta, &older.ObjectMeta, field.NewPath("metadata"))...)
@@ -132,7 +194,78 @@ func ValidateBuildConfigUpdate(config *buildapi.BuildConfig, older *buildapi.Bui
 
 // ValidateBuildRequest validates a BuildRequest object
 func ValidateBuildRequest(request *buildapi.BuildRequest) field.ErrorList {
-	return validation.ValidateObjectMeta(&request.ObjectMeta, true, kpath.ValidatePathSegmentName, field.NewPath("metadata"))
+	allErrs := validation.ValidateObjectMeta(&request.ObjectMeta, true, kpath.ValidatePathSegmentName, field.NewPath("metadata"))
+	for name := range request.Parameters {
+		if !buildapi.IsBuildParameterName(name) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("parameters").Key(name), name,
+				"must start with a letter and consist of at most 63 letters, digits, '_' or '-'"))
+		}
+	}
+	return allErrs
+}
+
+var cancelReasonRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9]{0,127}$`)
+
+// ValidateBuildCancelRequest validates the options of a build cancellation.
+func ValidateBuildCancelRequest(request *buildapi.BuildCancelRequest) field.ErrorList {
+	allErrs := validation.ValidateObjectMeta(&request.ObjectMeta, true, kpath.ValidatePathSegmentName, field.NewPath("metadata"))
+	if len(request.Reason) > 0 && !cancelReasonRegexp.MatchString(request.Reason) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("reason"), request.Reason, "must be a CamelCase word of at most 128 characters"))
+	}
+	if _, err := labels.Parse(request.LabelSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("labelSelector"), request.LabelSelector, err.Error()))
+	}
+	if request.OlderThanSeconds != nil && *request.OlderThanSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("olderThanSeconds"), *request.OlderThanSeconds, "must not be negative"))
+	}
+	if request.GracePeriodSeconds != nil && *request.GracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("gracePeriodSeconds"), *request.GracePeriodSeconds, "must not be negative"))
+	}
+	return allErrs
+}
+
+var (
+	validPolicyStrategies                = sets.NewString(string(buildv1.DockerBuildStrategyType), string(buildv1.SourceBuildStrategyType), string(buildv1.CustomBuildStrategyType), string(buildv1.JenkinsPipelineBuildStrategyType))
+	validPolicyImageOptimizationPolicies = sets.NewString(string(buildapi.ImageOptimizationNone), string(buildapi.ImageOptimizationSkipLayers), string(buildapi.ImageOptimizationSkipLayersAndWarn))
+	validPolicyVolumeSourceTypes         = sets.NewString(string(buildapi.BuildVolumeSourceTypeSecret), string(buildapi.BuildVolumeSourceTypeConfigMap), string(buildapi.BuildVolumeSourceTypeCSI))
+)
+
+// ValidateBuildStrategyPolicy tests required fields for a BuildStrategyPolicy.
+func ValidateBuildStrategyPolicy(policy *buildapi.BuildStrategyPolicy) field.ErrorList {
+	allErrs := validation.ValidateObjectMeta(&policy.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
+	specPath := field.NewPath("spec")
+	spec := &policy.Spec
+	if spec.NamespaceSelector != nil {
+		allErrs = append(allErrs, unversionedvalidation.ValidateLabelSelector(spec.NamespaceSelector, unversionedvalidation.LabelSelectorValidationOptions{}, specPath.Child("namespaceSelector"))...)
+	}
+	for i, strategy := range spec.AllowedStrategies {
+		if !validPolicyStrategies.Has(string(strategy)) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("allowedStrategies").Index(i), strategy, validPolicyStrategies.List()))
+		}
+	}
+	for i, imageOptimizationPolicy := range spec.RequiredImageOptimizationPolicies {
+		if !validPolicyImageOptimizationPolicies.Has(string(imageOptimizationPolicy)) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("requiredImageOptimizationPolicies").Index(i), imageOptimizationPolicy, validPolicyImageOptimizationPolicies.List()))
+		}
+	}
+	for i, sourceType := range spec.ForbiddenBuildVolumeSourceTypes {
+		if !validPolicyVolumeSourceTypes.Has(string(sourceType)) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("forbiddenBuildVolumeSourceTypes").Index(i), sourceType, validPolicyVolumeSourceTypes.List()))
+		}
+	}
+	for i, image := range spec.AllowedCustomBuilderImages {
+		if len(strings.TrimSuffix(image, "*")) == 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("allowedCustomBuilderImages").Index(i), image, "must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateBuildStrategyPolicyUpdate tests an update of a BuildStrategyPolicy.
+func ValidateBuildStrategyPolicyUpdate(policy, older *buildapi.BuildStrategyPolicy) field.ErrorList {
+	allErrs := validation.ValidateObjectMetaUpdate(&policy.ObjectMeta, &older.ObjectMeta, field.NewPath("metadata"))
+	allErrs = append(allErrs, ValidateBuildStrategyPolicy(policy)...)
+	return allErrs
 }
 
 func validateCommonSpec(spec *buildapi.CommonSpec, fldPath *field.Path) field.ErrorList {
@@ -476,10 +609,32 @@ func validateOutput(output *buildapi.BuildOutput, fldPath *field.Path) field.Err
 
 	allErrs = append(allErrs, validateSecretRef(output.PushSecret, fldPath.Child("pushSecret"))...)
 	allErrs = append(allErrs, ValidateImageLabels(output.ImageLabels, fldPath.Child("imageLabels"))...)
+	allErrs = append(allErrs, validateOutputTags(output, fldPath.Child("additionalTags"))...)
 
 	return allErrs
 }
 
+// validateOutputTags validates the additional output tags of a build or BuildConfig,
+// which require an ImageStreamTag output.
+func validateOutputTags(output *buildapi.BuildOutput, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(output.AdditionalTags) == 0 {
+		return allErrs
+	}
+	if len(output.AdditionalTags) > buildapi.MaxBuildOutputTags {
+		allErrs = append(allErrs, field.TooMany(fldPath, len(output.AdditionalTags), buildapi.MaxBuildOutputTags))
+	}
+	for i, tag := range output.AdditionalTags {
+		if err := buildapi.ValidateBuildOutputTag(tag); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), tag, err.Error()))
+		}
+	}
+	if output.To == nil || output.To.Kind != "ImageStreamTag" {
+		allErrs = append(allErrs, field.Invalid(fldPath, output.AdditionalTags, "additional tags require an ImageStreamTag output"))
+	}
+	return allErrs
+}
+
 func validateStrategy(strategy *buildapi.BuildStrategy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -639,6 +794,18 @@ func validateTrigger(trigger *buildapi.BuildTriggerPolicy, buildFrom *kapi.Objec
 		} else {
 			allErrs = append(allErrs, validateWebHook(trigger.GenericWebHook, fldPath.Child("generic"), true)...)
 		}
+	case buildapi.GiteaWebHookBuildTriggerType:
+		if trigger.GiteaWebHook == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("gitea"), ""))
+		} else {
+			allErrs = append(allErrs, validateWebHook(trigger.GiteaWebHook, fldPath.Child("gitea"), false)...)
+		}
+	case buildapi.AzureDevOpsWebHookBuildTriggerType:
+		if trigger.AzureDevOpsWebHook == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("azureDevOps"), ""))
+		} else {
+			allErrs = append(allErrs, validateWebHook(trigger.AzureDevOpsWebHook, fldPath.Child("azureDevOps"), false)...)
+		}
 	case buildapi.ImageChangeBuildTriggerType:
 		if trigger.ImageChange == nil {
 			allErrs = append(allErrs, field.Required(fldPath.Child("imageChange"), ""))
@@ -687,6 +854,17 @@ func validateWebHook(webHook *buildapi.WebHookTrigger, fldPath *field.Path, isGe
 	return allErrs
 }
 
+// ValidateBuildDiffOptions validates the options of a build diff.
+func ValidateBuildDiffOptions(opts *buildapi.BuildDiffOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(opts.Build) > 0 {
+		for _, msg := range kpath.ValidatePathSegmentName(opts.Build, false) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("build"), opts.Build, msg))
+		}
+	}
+	return allErrs
+}
+
 func ValidateBuildLogOptions(opts *buildapi.BuildLogOptions) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -702,6 +880,11 @@ func ValidateBuildLogOptions(opts *buildapi.BuildLogOptions) field.ErrorList {
 	if opts.Version != nil && opts.Previous {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("previous"), opts.Previous, "cannot use previous when a version is specified"))
 	}
+	switch opts.Format {
+	case "", buildapi.BuildLogFormatText, buildapi.BuildLogFormatJSONLines:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("format"), opts.Format, []string{string(buildapi.BuildLogFormatText), string(buildapi.BuildLogFormatJSONLines)}))
+	}
 	return allErrs
 }
 
@@ -900,6 +900,14 @@ func TestValidateBuildRequest(t *testing.T) {
 	testCases := map[string]*buildapi.BuildRequest{
 		string(field.ErrorTypeRequired) + "metadata.namespace": {ObjectMeta: metav1.ObjectMeta{Name: "requestName"}},
 		string(field.ErrorTypeRequired) + "metadata.name":      {ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault}},
+		string(field.ErrorTypeInvalid) + "parameters[1tag]": {
+			ObjectMeta: metav1.ObjectMeta{Name: "requestName", Namespace: metav1.NamespaceDefault},
+			Parameters: map[string]string{"1tag": "v1"},
+		},
+		"": {
+			ObjectMeta: metav1.ObjectMeta{Name: "requestName", Namespace: metav1.NamespaceDefault},
+			Parameters: map[string]string{"tag": "v1"},
+		},
 	}
 
 	for desc, tc := range testCases {
@@ -2803,6 +2811,36 @@ func TestValidateTrigger(t *testing.T) {
 			},
 			expected: []*field.Error{field.Invalid(field.NewPath("bitbucket", "allowEnv"), "", "")},
 		},
+		"Gitea trigger with no gitea webhook": {
+			trigger:  buildapi.BuildTriggerPolicy{Type: buildapi.GiteaWebHookBuildTriggerType},
+			expected: []*field.Error{field.Required(field.NewPath("gitea"), "")},
+		},
+		"Gitea trigger with a generic webhook": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.GiteaWebHookBuildTriggerType,
+				GenericWebHook: &buildapi.WebHookTrigger{
+					Secret: "secret101",
+				},
+			},
+			expected: []*field.Error{field.Required(field.NewPath("gitea"), "")},
+		},
+		"Gitea trigger with allow env": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.GiteaWebHookBuildTriggerType,
+				GiteaWebHook: &buildapi.WebHookTrigger{
+					Secret:   "secret101",
+					AllowEnv: true,
+				},
+			},
+			expected: []*field.Error{field.Invalid(field.NewPath("gitea", "allowEnv"), "", "")},
+		},
+		"AzureDevOps trigger with no secret": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type:               buildapi.AzureDevOpsWebHookBuildTriggerType,
+				AzureDevOpsWebHook: &buildapi.WebHookTrigger{},
+			},
+			expected: []*field.Error{field.Invalid(field.NewPath("azureDevOps"), buildapi.WebHookTrigger{}, "must provide a value for at least one of secret or secretReference")},
+		},
 		"Generic trigger with no generic webhook": {
 			trigger:  buildapi.BuildTriggerPolicy{Type: buildapi.GenericWebHookBuildTriggerType},
 			expected: []*field.Error{field.Required(field.NewPath("generic"), "")},
@@ -2878,6 +2916,24 @@ func TestValidateTrigger(t *testing.T) {
 				},
 			},
 		},
+		"valid Gitea trigger": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.GiteaWebHookBuildTriggerType,
+				GiteaWebHook: &buildapi.WebHookTrigger{
+					Secret: "secret101",
+				},
+			},
+		},
+		"valid AzureDevOps trigger with secretref": {
+			trigger: buildapi.BuildTriggerPolicy{
+				Type: buildapi.AzureDevOpsWebHookBuildTriggerType,
+				AzureDevOpsWebHook: &buildapi.WebHookTrigger{
+					SecretReference: &buildapi.SecretLocalReference{
+						Name: "mysecret",
+					},
+				},
+			},
+		},
 		"valid Generic trigger with secretref": {
 			trigger: buildapi.BuildTriggerPolicy{
 				Type: buildapi.GenericWebHookBuildTriggerType,
@@ -4013,3 +4069,273 @@ func TestValidateImageLabels(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateOutputTags(t *testing.T) {
+	imageStreamTag := &kapi.ObjectReference{Kind: "ImageStreamTag", Name: "app:latest"}
+	tooMany := make([]string, buildapi.MaxBuildOutputTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("v%d", i)
+	}
+	tests := []struct {
+		name     string
+		output   buildapi.BuildOutput
+		errField string
+		errType  field.ErrorType
+	}{
+		{
+			name:   "no tags",
+			output: buildapi.BuildOutput{To: &kapi.ObjectReference{Kind: "DockerImage", Name: "quay.io/team/app"}},
+		},
+		{
+			name:   "valid tags",
+			output: buildapi.BuildOutput{To: imageStreamTag, AdditionalTags: []string{"latest", "${shortCommit}", "${branch}-${tag}"}},
+		},
+		{
+			name:     "unknown placeholder",
+			output:   buildapi.BuildOutput{To: imageStreamTag, AdditionalTags: []string{"latest", "${version}"}},
+			errField: "additionalTags[1]",
+			errType:  field.ErrorTypeInvalid,
+		},
+		{
+			name:     "invalid tag",
+			output:   buildapi.BuildOutput{To: imageStreamTag, AdditionalTags: []string{"a/b"}},
+			errField: "additionalTags[0]",
+			errType:  field.ErrorTypeInvalid,
+		},
+		{
+			name:     "too many tags",
+			output:   buildapi.BuildOutput{To: imageStreamTag, AdditionalTags: tooMany},
+			errField: "additionalTags",
+			errType:  field.ErrorTypeTooMany,
+		},
+		{
+			name:     "docker image output",
+			output:   buildapi.BuildOutput{To: &kapi.ObjectReference{Kind: "DockerImage", Name: "quay.io/team/app"}, AdditionalTags: []string{"latest"}},
+			errField: "additionalTags",
+			errType:  field.ErrorTypeInvalid,
+		},
+	}
+
+	for _, tc := range tests {
+		errs := validateOutputTags(&tc.output, field.NewPath("additionalTags"))
+		if len(tc.errField) == 0 {
+			if len(errs) > 0 {
+				t.Errorf("%s: unexpected error: %v", tc.name, errs.ToAggregate())
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Errorf("%s: expected a single error, got %v", tc.name, errs)
+			continue
+		}
+		if errs[0].Field != tc.errField || errs[0].Type != tc.errType {
+			t.Errorf("%s: unexpected error: %v", tc.name, errs[0])
+		}
+	}
+}
+
+func TestBuildConfigValidationRetryPolicy(t *testing.T) {
+	buildConfig := &buildapi.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-id",
+			Namespace: "namespace",
+		},
+		Spec: buildapi.BuildConfigSpec{
+			RunPolicy: buildapi.BuildRunPolicySerial,
+			CommonSpec: buildapi.CommonSpec{
+				Source: buildapi.BuildSource{
+					Git: &buildapi.GitBuildSource{
+						URI: "http://github.com/my/repository",
+					},
+				},
+				Strategy: buildapi.BuildStrategy{
+					DockerStrategy: &buildapi.DockerBuildStrategy{},
+				},
+			},
+		},
+	}
+	negative, initial := int64(-1), int64(60)
+	testCases := map[string]struct {
+		policy      buildapi.BuildRetryPolicy
+		expectField string
+	}{
+		"defaults":             {policy: buildapi.BuildRetryPolicy{MaxAttempts: 3}},
+		"missing max attempts": {policy: buildapi.BuildRetryPolicy{}, expectField: "spec.retryPolicy.maxAttempts"},
+		"too many attempts":    {policy: buildapi.BuildRetryPolicy{MaxAttempts: 11}, expectField: "spec.retryPolicy.maxAttempts"},
+		"empty reason": {
+			policy:      buildapi.BuildRetryPolicy{MaxAttempts: 2, Reasons: []buildapi.StatusReason{""}},
+			expectField: "spec.retryPolicy.reasons[0]",
+		},
+		"negative backoff": {
+			policy:      buildapi.BuildRetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: &negative},
+			expectField: "spec.retryPolicy.initialBackoffSeconds",
+		},
+		"max backoff below initial": {
+			policy:      buildapi.BuildRetryPolicy{MaxAttempts: 2, InitialBackoffSeconds: &initial, MaxBackoffSeconds: new(int64)},
+			expectField: "spec.retryPolicy.maxBackoffSeconds",
+		},
+	}
+	for name, tc := range testCases {
+		buildConfig.Spec.RetryPolicy = &tc.policy
+		errs := ValidateBuildConfig(buildConfig)
+		switch {
+		case len(tc.expectField) == 0 && len(errs) != 0:
+			t.Errorf("%s: unexpected errors %v", name, errs)
+		case len(tc.expectField) != 0 && (len(errs) != 1 || errs[0].Field != tc.expectField):
+			t.Errorf("%s: expected an error on %s, got %v", name, tc.expectField, errs)
+		}
+	}
+}
+
+func TestBuildConfigValidationParameters(t *testing.T) {
+	buildConfig := &buildapi.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config-id",
+			Namespace: "namespace",
+		},
+		Spec: buildapi.BuildConfigSpec{
+			RunPolicy: buildapi.BuildRunPolicySerial,
+			CommonSpec: buildapi.CommonSpec{
+				Source: buildapi.BuildSource{
+					Git: &buildapi.GitBuildSource{
+						URI: "http://github.com/my/repository",
+					},
+				},
+				Strategy: buildapi.BuildStrategy{
+					DockerStrategy: &buildapi.DockerBuildStrategy{},
+				},
+			},
+		},
+	}
+	testCases := map[string]struct {
+		parameters  []buildapi.BuildParameter
+		expectField string
+	}{
+		"valid": {
+			parameters: []buildapi.BuildParameter{
+				{Name: "tag", Field: buildapi.BuildParameterFieldOutputTag, Pattern: "v[0-9]+"},
+				{Name: "dir", Field: buildapi.BuildParameterFieldContextDir},
+			},
+		},
+		"invalid name": {
+			parameters:  []buildapi.BuildParameter{{Name: "1tag", Field: buildapi.BuildParameterFieldOutputTag}},
+			expectField: "spec.parameters[0].name",
+		},
+		"duplicate name": {
+			parameters: []buildapi.BuildParameter{
+				{Name: "tag", Field: buildapi.BuildParameterFieldOutputTag},
+				{Name: "tag", Field: buildapi.BuildParameterFieldGitRef},
+			},
+			expectField: "spec.parameters[1].name",
+		},
+		"unsupported field": {
+			parameters:  []buildapi.BuildParameter{{Name: "strategy", Field: "Strategy"}},
+			expectField: "spec.parameters[0].field",
+		},
+		"invalid pattern": {
+			parameters:  []buildapi.BuildParameter{{Name: "tag", Field: buildapi.BuildParameterFieldOutputTag, Pattern: "v[0-9"}},
+			expectField: "spec.parameters[0].pattern",
+		},
+	}
+	for name, tc := range testCases {
+		buildConfig.Spec.Parameters = tc.parameters
+		errs := ValidateBuildConfig(buildConfig)
+		switch {
+		case len(tc.expectField) == 0 && len(errs) != 0:
+			t.Errorf("%s: unexpected errors %v", name, errs)
+		case len(tc.expectField) != 0 && (len(errs) != 1 || errs[0].Field != tc.expectField):
+			t.Errorf("%s: expected an error on %s, got %v", name, tc.expectField, errs)
+		}
+	}
+}
+
+func TestValidateBuildStrategyPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        buildapi.BuildStrategyPolicySpec
+		errExpected bool
+		errType     field.ErrorType
+		errField    string
+	}{
+		{
+			name: "empty policy",
+		},
+		{
+			name: "valid policy",
+			spec: buildapi.BuildStrategyPolicySpec{
+				NamespaceSelector:                 &metav1.LabelSelector{MatchLabels: map[string]string{"builds": "hardened"}},
+				AllowedStrategies:                 []buildapi.BuildStrategyType{"Docker", "Source"},
+				DisallowNoCache:                   true,
+				DisallowForcePull:                 true,
+				RequiredImageOptimizationPolicies: []buildapi.ImageOptimizationPolicy{buildapi.ImageOptimizationSkipLayers},
+				AllowedCustomBuilderImages:        []string{"registry.example.com/builders/*"},
+				ForbiddenBuildVolumeSourceTypes:   []buildapi.BuildVolumeSourceType{buildapi.BuildVolumeSourceTypeCSI},
+			},
+		},
+		{
+			name:        "invalid selector",
+			spec:        buildapi.BuildStrategyPolicySpec{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"-": "a"}}},
+			errExpected: true,
+			errType:     field.ErrorTypeInvalid,
+			errField:    "spec.namespaceSelector.matchLabels",
+		},
+		{
+			name:        "unknown strategy",
+			spec:        buildapi.BuildStrategyPolicySpec{AllowedStrategies: []buildapi.BuildStrategyType{"docker"}},
+			errExpected: true,
+			errType:     field.ErrorTypeNotSupported,
+			errField:    "spec.allowedStrategies[0]",
+		},
+		{
+			name:        "unknown image optimization policy",
+			spec:        buildapi.BuildStrategyPolicySpec{RequiredImageOptimizationPolicies: []buildapi.ImageOptimizationPolicy{"All"}},
+			errExpected: true,
+			errType:     field.ErrorTypeNotSupported,
+			errField:    "spec.requiredImageOptimizationPolicies[0]",
+		},
+		{
+			name:        "unknown volume source type",
+			spec:        buildapi.BuildStrategyPolicySpec{ForbiddenBuildVolumeSourceTypes: []buildapi.BuildVolumeSourceType{"HostPath"}},
+			errExpected: true,
+			errType:     field.ErrorTypeNotSupported,
+			errField:    "spec.forbiddenBuildVolumeSourceTypes[0]",
+		},
+		{
+			name:        "empty custom builder image",
+			spec:        buildapi.BuildStrategyPolicySpec{AllowedCustomBuilderImages: []string{"*"}},
+			errExpected: true,
+			errType:     field.ErrorTypeInvalid,
+			errField:    "spec.allowedCustomBuilderImages[0]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := &buildapi.BuildStrategyPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "hardened"},
+				Spec:       tc.spec,
+			}
+			errs := ValidateBuildStrategyPolicy(policy)
+			if len(errs) > 0 && !tc.errExpected {
+				t.Fatalf("unexpected error: %v", errs)
+			}
+			if len(errs) == 0 {
+				if tc.errExpected {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if errs[0].Type != tc.errType {
+				t.Errorf("expected error type %s, got %s", tc.errType, errs[0].Type)
+			}
+			if errs[0].Field != tc.errField {
+				t.Errorf("expected error field %s, got %s", tc.errField, errs[0].Field)
+			}
+		})
+	}
+
+	namespaced := &buildapi.BuildStrategyPolicy{ObjectMeta: metav1.ObjectMeta{Name: "hardened", Namespace: "foo"}}
+	if errs := ValidateBuildStrategyPolicy(namespaced); len(errs) == 0 {
+		t.Errorf("expected a namespaced policy to be rejected")
+	}
+}
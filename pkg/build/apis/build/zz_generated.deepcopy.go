@@ -6,10 +6,28 @@
 package build
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureDevOpsWebHookCause) DeepCopyInto(out *AzureDevOpsWebHookCause) {
+	*out = *in
+	in.CommonWebHookCause.DeepCopyInto(&out.CommonWebHookCause)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureDevOpsWebHookCause.
+func (in *AzureDevOpsWebHookCause) DeepCopy() *AzureDevOpsWebHookCause {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureDevOpsWebHookCause)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BinaryBuildRequestOptions) DeepCopyInto(out *BinaryBuildRequestOptions) {
 	*out = *in
@@ -97,6 +115,42 @@ func (in *Build) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildCancelRequest) DeepCopyInto(out *BuildCancelRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.OlderThanSeconds != nil {
+		in, out := &in.OlderThanSeconds, &out.OlderThanSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GracePeriodSeconds != nil {
+		in, out := &in.GracePeriodSeconds, &out.GracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildCancelRequest.
+func (in *BuildCancelRequest) DeepCopy() *BuildCancelRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildCancelRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildCancelRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildCondition) DeepCopyInto(out *BuildCondition) {
 	*out = *in
@@ -197,6 +251,16 @@ func (in *BuildConfigSpec) DeepCopyInto(out *BuildConfigSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(BuildRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]BuildParameter, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -233,6 +297,80 @@ func (in *BuildConfigStatus) DeepCopy() *BuildConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDiff) DeepCopyInto(out *BuildDiff) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.From = in.From
+	out.To = in.To
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]BuildDiffChange, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDiff.
+func (in *BuildDiff) DeepCopy() *BuildDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildDiff) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDiffChange) DeepCopyInto(out *BuildDiffChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDiffChange.
+func (in *BuildDiffChange) DeepCopy() *BuildDiffChange {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDiffChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDiffOptions) DeepCopyInto(out *BuildDiffOptions) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDiffOptions.
+func (in *BuildDiffOptions) DeepCopy() *BuildDiffOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDiffOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildDiffOptions) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildList) DeepCopyInto(out *BuildList) {
 	*out = *in
@@ -358,6 +496,11 @@ func (in *BuildOutput) DeepCopyInto(out *BuildOutput) {
 		*out = make([]ImageLabel, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdditionalTags != nil {
+		in, out := &in.AdditionalTags, &out.AdditionalTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -371,6 +514,22 @@ func (in *BuildOutput) DeepCopy() *BuildOutput {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildParameter) DeepCopyInto(out *BuildParameter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildParameter.
+func (in *BuildParameter) DeepCopy() *BuildParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildPostCommitSpec) DeepCopyInto(out *BuildPostCommitSpec) {
 	*out = *in
@@ -451,6 +610,13 @@ func (in *BuildRequest) DeepCopyInto(out *BuildRequest) {
 		*out = new(SourceStrategyOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -472,6 +638,53 @@ func (in *BuildRequest) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildRetryCause) DeepCopyInto(out *BuildRetryCause) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildRetryCause.
+func (in *BuildRetryCause) DeepCopy() *BuildRetryCause {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildRetryCause)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildRetryPolicy) DeepCopyInto(out *BuildRetryPolicy) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]StatusReason, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitialBackoffSeconds != nil {
+		in, out := &in.InitialBackoffSeconds, &out.InitialBackoffSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxBackoffSeconds != nil {
+		in, out := &in.MaxBackoffSeconds, &out.MaxBackoffSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildRetryPolicy.
+func (in *BuildRetryPolicy) DeepCopy() *BuildRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildSource) DeepCopyInto(out *BuildSource) {
 	*out = *in
@@ -666,6 +879,107 @@ func (in *BuildStrategy) DeepCopy() *BuildStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStrategyPolicy) DeepCopyInto(out *BuildStrategyPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStrategyPolicy.
+func (in *BuildStrategyPolicy) DeepCopy() *BuildStrategyPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStrategyPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildStrategyPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStrategyPolicyList) DeepCopyInto(out *BuildStrategyPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BuildStrategyPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStrategyPolicyList.
+func (in *BuildStrategyPolicyList) DeepCopy() *BuildStrategyPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStrategyPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildStrategyPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildStrategyPolicySpec) DeepCopyInto(out *BuildStrategyPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedStrategies != nil {
+		in, out := &in.AllowedStrategies, &out.AllowedStrategies
+		*out = make([]BuildStrategyType, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredImageOptimizationPolicies != nil {
+		in, out := &in.RequiredImageOptimizationPolicies, &out.RequiredImageOptimizationPolicies
+		*out = make([]ImageOptimizationPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCustomBuilderImages != nil {
+		in, out := &in.AllowedCustomBuilderImages, &out.AllowedCustomBuilderImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenBuildVolumeSourceTypes != nil {
+		in, out := &in.ForbiddenBuildVolumeSourceTypes, &out.ForbiddenBuildVolumeSourceTypes
+		*out = make([]BuildVolumeSourceType, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildStrategyPolicySpec.
+func (in *BuildStrategyPolicySpec) DeepCopy() *BuildStrategyPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildStrategyPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildTriggerCause) DeepCopyInto(out *BuildTriggerCause) {
 	*out = *in
@@ -694,6 +1008,21 @@ func (in *BuildTriggerCause) DeepCopyInto(out *BuildTriggerCause) {
 		*out = new(BitbucketWebHookCause)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GiteaWebHook != nil {
+		in, out := &in.GiteaWebHook, &out.GiteaWebHook
+		*out = new(GiteaWebHookCause)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureDevOpsWebHook != nil {
+		in, out := &in.AzureDevOpsWebHook, &out.AzureDevOpsWebHook
+		*out = new(AzureDevOpsWebHookCause)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildRetry != nil {
+		in, out := &in.BuildRetry, &out.BuildRetry
+		*out = new(BuildRetryCause)
+		**out = **in
+	}
 	return
 }
 
@@ -735,6 +1064,16 @@ func (in *BuildTriggerPolicy) DeepCopyInto(out *BuildTriggerPolicy) {
 		*out = new(WebHookTrigger)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GiteaWebHook != nil {
+		in, out := &in.GiteaWebHook, &out.GiteaWebHook
+		*out = new(WebHookTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureDevOpsWebHook != nil {
+		in, out := &in.AzureDevOpsWebHook, &out.AzureDevOpsWebHook
+		*out = new(WebHookTrigger)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1182,6 +1521,23 @@ func (in *GitSourceRevision) DeepCopy() *GitSourceRevision {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GiteaWebHookCause) DeepCopyInto(out *GiteaWebHookCause) {
+	*out = *in
+	in.CommonWebHookCause.DeepCopyInto(&out.CommonWebHookCause)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GiteaWebHookCause.
+func (in *GiteaWebHookCause) DeepCopy() *GiteaWebHookCause {
+	if in == nil {
+		return nil
+	}
+	out := new(GiteaWebHookCause)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageChangeCause) DeepCopyInto(out *ImageChangeCause) {
 	*out = *in
@@ -0,0 +1,77 @@
+package secretinjector
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/authentication/user"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/openshift/library-go/pkg/authorization/authorizationutil"
+)
+
+const (
+	accessCacheSize       = 4096
+	allowedAccessCacheTTL = time.Minute
+	deniedAccessCacheTTL  = 10 * time.Second
+)
+
+// accessCache answers whether a user can read a secret with SubjectAccessReviews,
+// caching the answers for a short time.
+type accessCache struct {
+	client authorizationclient.SubjectAccessReviewInterface
+	cache  *utilcache.LRUExpireCache
+}
+
+func newAccessCache(client authorizationclient.SubjectAccessReviewInterface) *accessCache {
+	return &accessCache{
+		client: client,
+		cache:  utilcache.NewLRUExpireCache(accessCacheSize),
+	}
+}
+
+// canGetSecret returns true if u is allowed to get the secret name in namespace.
+func (c *accessCache) canGetSecret(ctx context.Context, u user.Info, namespace, name string) (bool, error) {
+	key := accessKey(u, namespace, name)
+	if allowed, ok := c.cache.Get(key); ok {
+		return allowed.(bool), nil
+	}
+
+	sar := authorizationutil.AddUserToSAR(u, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      name,
+			},
+		},
+	})
+	resp, err := c.client.Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	ttl := deniedAccessCacheTTL
+	if resp.Status.Allowed {
+		ttl = allowedAccessCacheTTL
+	}
+	c.cache.Add(key, resp.Status.Allowed, ttl)
+	return resp.Status.Allowed, nil
+}
+
+// accessKey identifies the user and secret of an access review.
+func accessKey(u user.Info, namespace, name string) string {
+	groups := append([]string{}, u.GetGroups()...)
+	sort.Strings(groups)
+	extra := []string{}
+	for k, v := range u.GetExtra() {
+		extra = append(extra, k+"="+strings.Join(v, ","))
+	}
+	sort.Strings(extra)
+	return strings.Join([]string{u.GetName(), u.GetUID(), strings.Join(groups, ","), strings.Join(extra, ";"), namespace, name}, "\x00")
+}
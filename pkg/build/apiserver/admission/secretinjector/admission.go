@@ -5,19 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/admission"
-	restclient "k8s.io/client-go/rest"
+	"k8s.io/apiserver/pkg/admission/initializer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	api "k8s.io/kubernetes/pkg/apis/core"
 
-	buildv1 "github.com/openshift/api/build/v1"
-	"github.com/openshift/library-go/pkg/apiserver/admission/admissionrestconfig"
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
-	authclient "github.com/openshift/openshift-apiserver/pkg/client/impersonatingclient"
 )
 
 func Register(plugins *admission.Plugins) {
@@ -31,10 +28,12 @@ func Register(plugins *admission.Plugins) {
 
 type secretInjector struct {
 	*admission.Handler
-	restClientConfig restclient.Config
+	index  *patternIndex
+	access *accessCache
 }
 
-var _ = admissionrestconfig.WantsRESTClientConfig(&secretInjector{})
+var _ = initializer.WantsExternalKubeInformerFactory(&secretInjector{})
+var _ = initializer.WantsExternalKubeClientSet(&secretInjector{})
 var _ = admission.MutationInterface(&secretInjector{})
 var _ = admission.ValidationInterface(&secretInjector{})
 
@@ -52,73 +51,147 @@ func (si *secretInjector) admit(ctx context.Context, attr admission.Attributes,
 		return nil
 	}
 
-	if bc.Spec.Source.SourceSecret != nil || bc.Spec.Source.Git == nil {
+	injectSourceSecret := bc.Spec.Source.SourceSecret == nil && bc.Spec.Source.Git != nil
+	images := imageSecrets(bc)
+	if !injectSourceSecret && len(images) == 0 {
 		return nil
 	}
 
-	client, err := authclient.NewImpersonatingKubernetesClientset(attr.GetUserInfo(), si.restClientConfig)
-	if err != nil {
-		klog.V(2).Infof("secretinjector: could not create client: %v", err)
+	if !si.WaitForReady() {
+		klog.V(2).Infof("secretinjector: secret cache is not synced")
 		return nil
 	}
 
 	namespace := attr.GetNamespace()
 
-	url, err := url.Parse(bc.Spec.Source.Git.URI)
+	patterns, err := si.index.patterns(namespace)
 	if err != nil {
-		klog.V(2).Infof(`secretinjector: buildconfig "%s/%s": URI %q parse failed: %v`, namespace, bc.GetName(), bc.Spec.Source.Git.URI, err)
+		klog.V(2).Infof("secretinjector: failed to list Secrets: %v", err)
 		return nil
 	}
 
-	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		klog.V(2).Infof("secretinjector: failed to list Secrets: %v", err)
-		return nil
+	if injectSourceSecret {
+		url, err := url.Parse(bc.Spec.Source.Git.URI)
+		if err != nil {
+			klog.V(2).Infof(`secretinjector: buildconfig "%s/%s": URI %q parse failed: %v`, namespace, bc.GetName(), bc.Spec.Source.Git.URI, err)
+		} else {
+			secretName := si.matchSecret(ctx, attr, patterns.source, url, func(secret secretRef) bool {
+				return !(secret.secretType == corev1.SecretTypeBasicAuth && url.Scheme == "ssh" ||
+					secret.secretType == corev1.SecretTypeSSHAuth && url.Scheme != "ssh")
+			})
+			if secretName != "" {
+				klog.V(4).Infof(`secretinjector: matched secret "%s/%s" to buildconfig "%s"`, namespace, secretName, bc.GetName())
+				if err := setSecret(attr, "spec.source.sourceSecret", &bc.Spec.Source.SourceSecret, secretName, mutationAllowed); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
-	patterns := []*URLPattern{}
-	for _, secret := range secrets.Items {
-		if secret.Type == corev1.SecretTypeBasicAuth && url.Scheme == "ssh" ||
-			secret.Type == corev1.SecretTypeSSHAuth && url.Scheme != "ssh" {
+	for _, image := range images {
+		url, err := imageURL(image.from.Name)
+		if err != nil {
+			klog.V(2).Infof(`secretinjector: buildconfig "%s/%s": image %q parse failed: %v`, namespace, bc.GetName(), image.from.Name, err)
 			continue
 		}
+		secretName := si.matchSecret(ctx, attr, patterns.registry, url, func(secretRef) bool { return true })
+		if secretName == "" {
+			continue
+		}
+		klog.V(4).Infof(`secretinjector: matched secret "%s/%s" to %s of buildconfig "%s"`, namespace, secretName, image.field, bc.GetName())
+		if err := setSecret(attr, image.field, image.secret, secretName, mutationAllowed); err != nil {
+			return err
+		}
+	}
 
-		for k, v := range secret.GetAnnotations() {
-			if strings.HasPrefix(k, buildv1.BuildSourceSecretMatchURIAnnotationPrefix) {
-				v = strings.TrimSpace(v)
-				if v == "" {
-					continue
-				}
+	return nil
+}
 
-				pattern, err := NewURLPattern(v)
-				if err != nil {
-					klog.V(2).Infof(`secretinjector: buildconfig "%s/%s": unparseable annotation %q: %v`, namespace, bc.GetName(), k, err)
-					continue
-				}
+// matchSecret returns the name of the first secret of patterns matching url
+// that is accepted and that the user can read, or "" if there is none.
+func (si *secretInjector) matchSecret(ctx context.Context, attr admission.Attributes, patterns []*URLPattern, url *url.URL, accept func(secretRef) bool) string {
+	// patterns are ordered longest first, the first matching secret the user
+	// can read wins
+	for _, pattern := range patterns {
+		secret := pattern.Cookie.(secretRef)
+		if !accept(secret) || !pattern.match(url) {
+			continue
+		}
 
-				pattern.Cookie = secret.GetName()
-				patterns = append(patterns, pattern)
-			}
+		allowed, err := si.access.canGetSecret(ctx, attr.GetUserInfo(), attr.GetNamespace(), secret.name)
+		if err != nil {
+			klog.V(2).Infof(`secretinjector: failed to review access to secret "%s/%s": %v`, attr.GetNamespace(), secret.name, err)
+			return ""
+		}
+		if allowed {
+			return secret.name
 		}
 	}
+	return ""
+}
 
-	if match := Match(patterns, url); match != nil {
-		secretName := match.Cookie.(string)
-		klog.V(4).Infof(`secretinjector: matched secret "%s/%s" to buildconfig "%s"`, namespace, secretName, bc.GetName())
-		if mutationAllowed {
-			bc.Spec.Source.SourceSecret = &api.LocalObjectReference{Name: secretName}
-		} else {
-			return admission.NewForbidden(attr, fmt.Errorf("mutated spec.source.sourceSecret, expected: %v, got %v", api.LocalObjectReference{Name: secretName}, bc.Spec.Source.SourceSecret))
+// setSecret sets ref to the secret name, or rejects the request if the secret
+// was expected to be injected already.
+func setSecret(attr admission.Attributes, field string, ref **api.LocalObjectReference, name string, mutationAllowed bool) error {
+	if !mutationAllowed {
+		return admission.NewForbidden(attr, fmt.Errorf("mutated %s, expected: %v, got %v", field, api.LocalObjectReference{Name: name}, *ref))
+	}
+	*ref = &api.LocalObjectReference{Name: name}
+	return nil
+}
+
+// imageSecret is an image pull or push secret of a BuildConfig.
+type imageSecret struct {
+	field  string
+	from   *api.ObjectReference
+	secret **api.LocalObjectReference
+}
+
+// imageSecrets returns the unset pull and push secrets of bc whose image
+// references point directly at a registry.
+func imageSecrets(bc *buildapi.BuildConfig) []imageSecret {
+	all := []imageSecret{}
+	strategy := &bc.Spec.Strategy
+	switch {
+	case strategy.SourceStrategy != nil:
+		all = append(all, imageSecret{"spec.strategy.sourceStrategy.pullSecret", &strategy.SourceStrategy.From, &strategy.SourceStrategy.PullSecret})
+	case strategy.DockerStrategy != nil:
+		all = append(all, imageSecret{"spec.strategy.dockerStrategy.pullSecret", strategy.DockerStrategy.From, &strategy.DockerStrategy.PullSecret})
+	case strategy.CustomStrategy != nil:
+		all = append(all, imageSecret{"spec.strategy.customStrategy.pullSecret", &strategy.CustomStrategy.From, &strategy.CustomStrategy.PullSecret})
+	}
+	for i := range bc.Spec.Source.Images {
+		image := &bc.Spec.Source.Images[i]
+		all = append(all, imageSecret{fmt.Sprintf("spec.source.images[%d].pullSecret", i), &image.From, &image.PullSecret})
+	}
+	all = append(all, imageSecret{"spec.output.pushSecret", bc.Spec.Output.To, &bc.Spec.Output.PushSecret})
+
+	secrets := []imageSecret{}
+	for _, s := range all {
+		if s.from != nil && s.from.Kind == "DockerImage" && len(s.from.Name) > 0 && *s.secret == nil {
+			secrets = append(secrets, s)
 		}
 	}
+	return secrets
+}
 
-	return nil
+func (si *secretInjector) SetExternalKubeInformerFactory(kubeInformers informers.SharedInformerFactory) {
+	secretInformer := kubeInformers.Core().V1().Secrets()
+	si.index = newPatternIndex(secretInformer.Lister())
+	secretInformer.Informer().AddEventHandler(si.index.eventHandler())
+	si.SetReadyFunc(secretInformer.Informer().HasSynced)
 }
 
-func (si *secretInjector) SetRESTClientConfig(restClientConfig restclient.Config) {
-	si.restClientConfig = restClientConfig
+func (si *secretInjector) SetExternalKubeClientSet(c kubernetes.Interface) {
+	si.access = newAccessCache(c.AuthorizationV1().SubjectAccessReviews())
 }
 
 func (si *secretInjector) ValidateInitialization() error {
+	if si.index == nil {
+		return fmt.Errorf("build.openshift.io/BuildConfigSecretInjector needs a secret informer")
+	}
+	if si.access == nil {
+		return fmt.Errorf("build.openshift.io/BuildConfigSecretInjector needs a SubjectAccessReview client")
+	}
 	return nil
 }
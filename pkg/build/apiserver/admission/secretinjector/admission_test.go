@@ -0,0 +1,263 @@
+package secretinjector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/informers"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	api "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/openshift/api/build"
+	buildv1 "github.com/openshift/api/build/v1"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+func testSecret(name string, secretType corev1.SecretType, patterns ...string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Annotations: map[string]string{},
+		},
+		Type: secretType,
+	}
+	for i, pattern := range patterns {
+		secret.Annotations[buildv1.BuildSourceSecretMatchURIAnnotationPrefix+string(rune('a'+i))] = pattern
+	}
+	return secret
+}
+
+func testRegistrySecret(name string, patterns ...string) *corev1.Secret {
+	secret := testSecret(name, corev1.SecretTypeDockerConfigJson)
+	for i, pattern := range patterns {
+		secret.Annotations[RegistrySecretMatchAnnotationPrefix+string(rune('a'+i))] = pattern
+	}
+	return secret
+}
+
+func testBuildConfig(uri string) *buildapi.BuildConfig {
+	return &buildapi.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bc"},
+		Spec: buildapi.BuildConfigSpec{
+			CommonSpec: buildapi.CommonSpec{
+				Source: buildapi.BuildSource{
+					Git: &buildapi.GitBuildSource{URI: uri},
+				},
+			},
+		},
+	}
+}
+
+// newTestInjector returns an injector backed by a synced secret informer. SAR
+// requests are allowed for the secrets in readable and counted in reviews.
+func newTestInjector(t *testing.T, stopCh <-chan struct{}, readable map[string]bool, reviews *int, secrets ...runtime.Object) (*secretInjector, *fakekubeclient.Clientset) {
+	client := fakekubeclient.NewSimpleClientset(secrets...)
+	client.PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		*reviews++
+		sar := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		if sar.Spec.User != "developer" || sar.Spec.ResourceAttributes.Verb != "get" || sar.Spec.ResourceAttributes.Resource != "secrets" {
+			t.Errorf("unexpected access review %#v", sar.Spec)
+		}
+		sar.Status.Allowed = readable[sar.Spec.ResourceAttributes.Name]
+		return true, sar, nil
+	})
+	kubeInformers := informers.NewSharedInformerFactory(client, 0)
+
+	injector := &secretInjector{Handler: admission.NewHandler(admission.Create)}
+	injector.SetExternalKubeInformerFactory(kubeInformers)
+	injector.SetExternalKubeClientSet(client)
+	if err := injector.ValidateInitialization(); err != nil {
+		t.Fatal(err)
+	}
+	kubeInformers.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, kubeInformers.Core().V1().Secrets().Informer().HasSynced) {
+		t.Fatal("Informer's cache is not updated!")
+	}
+	return injector, client
+}
+
+func admit(t *testing.T, injector *secretInjector, bc *buildapi.BuildConfig) string {
+	attr := admission.NewAttributesRecord(bc, nil, build.Kind("BuildConfig").WithVersion("version"), "default", "bc", build.Resource("buildconfigs").WithVersion("version"), "", admission.Create, nil, false, &user.DefaultInfo{Name: "developer"})
+	if err := injector.Admit(context.Background(), attr, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bc.Spec.Source.SourceSecret == nil {
+		return ""
+	}
+	return bc.Spec.Source.SourceSecret.Name
+}
+
+func TestSecretInjection(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	reviews := 0
+	injector, _ := newTestInjector(t, stopCh, map[string]bool{"org": true, "repo-ssh": true}, &reviews,
+		testSecret("org", corev1.SecretTypeBasicAuth, "https://github.com/openshift/*"),
+		testSecret("repo", corev1.SecretTypeBasicAuth, "https://github.com/openshift/origin"),
+		testSecret("repo-ssh", corev1.SecretTypeSSHAuth, "ssh://github.com/openshift/origin"),
+		testSecret("invalid", corev1.SecretTypeBasicAuth, "not a pattern"),
+	)
+
+	testCases := map[string]struct {
+		uri      string
+		expected string
+	}{
+		// the longer pattern of "repo" matches, but the user cannot read it
+		"unreadable secret is skipped": {uri: "https://github.com/openshift/origin", expected: "org"},
+		"ssh secret for ssh url":       {uri: "ssh://github.com/openshift/origin", expected: "repo-ssh"},
+		"no match":                     {uri: "https://gitlab.com/openshift/origin"},
+	}
+	for name, tc := range testCases {
+		if actual := admit(t, injector, testBuildConfig(tc.uri)); actual != tc.expected {
+			t.Errorf("%s: expected secret %q, got %q", name, tc.expected, actual)
+		}
+	}
+
+	// access reviews are cached
+	reviews = 0
+	admit(t, injector, testBuildConfig("https://github.com/openshift/origin"))
+	if reviews != 0 {
+		t.Errorf("expected cached access reviews, got %d reviews", reviews)
+	}
+}
+
+func TestSecretInjectionFollowsSecretChanges(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	reviews := 0
+	injector, client := newTestInjector(t, stopCh, map[string]bool{"org": true, "repo": true}, &reviews,
+		testSecret("org", corev1.SecretTypeBasicAuth, "https://github.com/openshift/*"),
+	)
+	if actual := admit(t, injector, testBuildConfig("https://github.com/openshift/origin")); actual != "org" {
+		t.Fatalf("expected secret %q, got %q", "org", actual)
+	}
+
+	if _, err := client.CoreV1().Secrets("default").Create(context.Background(), testSecret("repo", corev1.SecretTypeBasicAuth, "https://github.com/openshift/origin"), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitForSecret(injector, "repo"); err != nil {
+		t.Fatal(err)
+	}
+	if actual := admit(t, injector, testBuildConfig("https://github.com/openshift/origin")); actual != "repo" {
+		t.Errorf("expected secret %q, got %q", "repo", actual)
+	}
+}
+
+func waitForSecret(injector *secretInjector, name string) error {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		patterns, err := injector.index.patterns("default")
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns.source {
+			if pattern.Cookie.(secretRef).name == name {
+				return nil
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return context.DeadlineExceeded
+}
+
+func TestCompilePatternsOrder(t *testing.T) {
+	patterns := compilePatterns([]*corev1.Secret{
+		testSecret("b", corev1.SecretTypeBasicAuth, "https://github.com/*"),
+		testSecret("a", corev1.SecretTypeBasicAuth, "https://github.com/*", "https://github.com/openshift/*"),
+	})
+	names := []string{}
+	for _, pattern := range patterns.source {
+		names = append(names, pattern.Cookie.(secretRef).name+" "+pattern.pattern)
+	}
+	expected := "a https://github.com/openshift/*,a https://github.com/*,b https://github.com/*"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("expected %s, got %s", expected, strings.Join(names, ","))
+	}
+}
+
+func TestImageSecretInjection(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	reviews := 0
+	injector, _ := newTestInjector(t, stopCh, map[string]bool{"quay": true, "quay-org": true, "example": true, "unreadable": false}, &reviews,
+		testRegistrySecret("quay", "quay.io"),
+		testRegistrySecret("quay-org", "quay.io/openshift/*"),
+		testRegistrySecret("example", "*.example.com:5000"),
+		testRegistrySecret("unreadable", "registry.example.com:5000/private/*"),
+		// only docker config secrets are used for registries
+		func() *corev1.Secret {
+			secret := testSecret("basic", corev1.SecretTypeBasicAuth)
+			secret.Annotations[RegistrySecretMatchAnnotationPrefix+"a"] = "docker.io"
+			return secret
+		}(),
+	)
+
+	bc := testBuildConfig("")
+	bc.Spec.Source.Git = nil
+	bc.Spec.Strategy.SourceStrategy = &buildapi.SourceBuildStrategy{
+		From: api.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/builder:latest"},
+	}
+	bc.Spec.Source.Images = []buildapi.ImageSource{
+		{From: api.ObjectReference{Kind: "DockerImage", Name: "registry.example.com:5000/private/app"}},
+		{From: api.ObjectReference{Kind: "DockerImage", Name: "docker.io/library/busybox"}},
+		{From: api.ObjectReference{Kind: "ImageStreamTag", Name: "app:latest"}},
+		{From: api.ObjectReference{Kind: "DockerImage", Name: "quay.io/other/app"}, PullSecret: &api.LocalObjectReference{Name: "explicit"}},
+	}
+	bc.Spec.Output.To = &api.ObjectReference{Kind: "DockerImage", Name: "quay.io/someone/app:latest"}
+	admit(t, injector, bc)
+
+	name := func(ref *api.LocalObjectReference) string {
+		if ref == nil {
+			return ""
+		}
+		return ref.Name
+	}
+	testCases := map[string]struct {
+		actual   *api.LocalObjectReference
+		expected string
+	}{
+		"strategy pull secret":                      {bc.Spec.Strategy.SourceStrategy.PullSecret, "quay-org"},
+		"unreadable secret falls back to the host":  {bc.Spec.Source.Images[0].PullSecret, "example"},
+		"no docker config secret for the registry":  {bc.Spec.Source.Images[1].PullSecret, ""},
+		"image stream references are left to build": {bc.Spec.Source.Images[2].PullSecret, ""},
+		"explicit pull secret is kept":              {bc.Spec.Source.Images[3].PullSecret, "explicit"},
+		"push secret":                               {bc.Spec.Output.PushSecret, "quay"},
+	}
+	for tcName, tc := range testCases {
+		if actual := name(tc.actual); actual != tc.expected {
+			t.Errorf("%s: expected secret %q, got %q", tcName, tc.expected, actual)
+		}
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	testCases := map[string]string{
+		"quay.io/openshift/origin:latest":                     "https://quay.io/openshift/origin",
+		"registry:5000/app@sha256:" + strings.Repeat("0", 64): "https://registry:5000/app",
+		"centos/ruby-27-centos7":                              "https://docker.io/centos/ruby-27-centos7",
+	}
+	for spec, expected := range testCases {
+		url, err := imageURL(spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", spec, err)
+			continue
+		}
+		if url.String() != expected {
+			t.Errorf("%s: expected %s, got %s", spec, expected, url)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package secretinjector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+// secretRef is the Cookie of the URL patterns in a patternIndex.
+type secretRef struct {
+	name       string
+	secretType corev1.SecretType
+}
+
+// namespacePatterns are the compiled patterns of the secrets of a namespace,
+// longest pattern first.
+type namespacePatterns struct {
+	// source are the source URI patterns of source secrets.
+	source []*URLPattern
+	// registry are the registry patterns of docker config secrets.
+	registry []*URLPattern
+}
+
+// patternIndex holds the secret patterns of each namespace, compiled from the
+// annotations of the secrets in the informer cache. The patterns of a
+// namespace are compiled when they are first needed and dropped whenever one of
+// the secrets of the namespace changes.
+type patternIndex struct {
+	lister corev1listers.SecretLister
+
+	lock        sync.Mutex
+	namespaces  map[string]*namespacePatterns
+	generations map[string]uint64
+}
+
+func newPatternIndex(lister corev1listers.SecretLister) *patternIndex {
+	return &patternIndex{
+		lister:      lister,
+		namespaces:  map[string]*namespacePatterns{},
+		generations: map[string]uint64{},
+	}
+}
+
+// eventHandler returns the handler invalidating the index on secret changes.
+func (i *patternIndex) eventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    i.invalidate,
+		UpdateFunc: func(_, obj interface{}) { i.invalidate(obj) },
+		DeleteFunc: i.invalidate,
+	}
+}
+
+func (i *patternIndex) invalidate(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.generations[secret.Namespace]++
+	delete(i.namespaces, secret.Namespace)
+}
+
+// patterns returns the patterns of namespace. The returned patterns are shared
+// and must not be modified.
+func (i *patternIndex) patterns(namespace string) (*namespacePatterns, error) {
+	i.lock.Lock()
+	patterns, ok := i.namespaces[namespace]
+	generation := i.generations[namespace]
+	i.lock.Unlock()
+	if ok {
+		return patterns, nil
+	}
+
+	secrets, err := i.lister.Secrets(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	patterns = compilePatterns(secrets)
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	// a secret changed while the patterns were compiled, they may be stale
+	if i.generations[namespace] == generation {
+		i.namespaces[namespace] = patterns
+	}
+	return patterns, nil
+}
+
+func compilePatterns(secrets []*corev1.Secret) *namespacePatterns {
+	// sort secrets and annotations so that patterns of equal length keep a stable order
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	patterns := &namespacePatterns{source: []*URLPattern{}, registry: []*URLPattern{}}
+	for _, secret := range secrets {
+		patterns.source = append(patterns.source, compileAnnotations(secret, buildv1.BuildSourceSecretMatchURIAnnotationPrefix, NewURLPattern)...)
+		if secret.Type == corev1.SecretTypeDockercfg || secret.Type == corev1.SecretTypeDockerConfigJson {
+			patterns.registry = append(patterns.registry, compileAnnotations(secret, RegistrySecretMatchAnnotationPrefix, NewRegistryPattern)...)
+		}
+	}
+	sort.Stable(sort.Reverse(byLength(patterns.source)))
+	sort.Stable(sort.Reverse(byLength(patterns.registry)))
+	return patterns
+}
+
+// compileAnnotations parses the annotations of secret starting with prefix.
+func compileAnnotations(secret *corev1.Secret, prefix string, parse func(string) (*URLPattern, error)) []*URLPattern {
+	keys := make([]string, 0, len(secret.Annotations))
+	for k := range secret.Annotations {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	patterns := []*URLPattern{}
+	for _, k := range keys {
+		v := strings.TrimSpace(secret.Annotations[k])
+		if v == "" {
+			continue
+		}
+
+		pattern, err := parse(v)
+		if err != nil {
+			klog.V(2).Infof(`secretinjector: secret "%s/%s": unparseable annotation %q: %v`, secret.Namespace, secret.Name, k, err)
+			continue
+		}
+
+		pattern.Cookie = secretRef{name: secret.Name, secretType: secret.Type}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
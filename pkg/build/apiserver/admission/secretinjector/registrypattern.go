@@ -0,0 +1,49 @@
+package secretinjector
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/openshift/library-go/pkg/image/imageutil"
+)
+
+// RegistrySecretMatchAnnotationPrefix is a prefix for annotations on a docker config Secret
+// which indicate a registry, optionally followed by a repository path, against which the
+// Secret can be used to pull or push images, e.g. "quay.io/myorg/*" or "*.example.com".
+const RegistrySecretMatchAnnotationPrefix = "build.openshift.io/registry-secret-match-"
+
+const defaultRegistry = "docker.io"
+
+// NewRegistryPattern parses a registry pattern. A pattern without a repository
+// path matches every repository of the registry.
+func NewRegistryPattern(pattern string) (*URLPattern, error) {
+	if strings.Contains(pattern, "://") {
+		return nil, InvalidPatternError
+	}
+	if !strings.Contains(pattern, "/") {
+		pattern += "/*"
+	}
+	urlPattern, err := NewURLPattern("https://" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	urlPattern.pattern = pattern
+	return urlPattern, nil
+}
+
+// imageURL returns the URL matched against registry patterns for a docker pull spec.
+func imageURL(spec string) (*url.URL, error) {
+	ref, err := imageutil.ParseDockerImageReference(spec)
+	if err != nil {
+		return nil, err
+	}
+	registry := ref.Registry
+	if registry == "" {
+		registry = defaultRegistry
+	}
+	path := "/" + ref.Name
+	if ref.Namespace != "" {
+		path = "/" + ref.Namespace + path
+	}
+	return &url.URL{Scheme: "https", Host: registry, Path: path}, nil
+}
@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	kapihelper "k8s.io/kubernetes/pkg/apis/core/helper"
 
 	"k8s.io/apiserver/pkg/admission"
@@ -23,12 +27,15 @@ import (
 	"github.com/openshift/library-go/pkg/apiserver/admission/admissionrestconfig"
 	"github.com/openshift/library-go/pkg/authorization/authorizationutil"
 
+	openshiftapiserveradmission "github.com/openshift/openshift-apiserver/pkg/admission"
 	"github.com/openshift/openshift-apiserver/pkg/api/legacy"
 	"github.com/openshift/openshift-apiserver/pkg/bootstrappolicy"
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
 	buildv1helpers "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
 )
 
+const timeToWaitForCacheSync = 10 * time.Second
+
 func Register(plugins *admission.Plugins) {
 	plugins.Register("build.openshift.io/BuildByStrategy",
 		func(config io.Reader) (admission.Interface, error) {
@@ -40,10 +47,19 @@ type buildByStrategy struct {
 	*admission.Handler
 	sarClient   authorizationclient.SubjectAccessReviewInterface
 	buildClient buildclient.Interface
+
+	// policies holds the BuildStrategyPolicy objects evaluated next to the RBAC
+	// checks.
+	policies       cache.Store
+	policiesSynced func() bool
+	nsLister       corev1listers.NamespaceLister
+	nsListerSynced func() bool
 }
 
 var _ = initializer.WantsExternalKubeClientSet(&buildByStrategy{})
+var _ = initializer.WantsExternalKubeInformerFactory(&buildByStrategy{})
 var _ = admissionrestconfig.WantsRESTClientConfig(&buildByStrategy{})
+var _ = openshiftapiserveradmission.WantsBuildStrategyPolicyInformer(&buildByStrategy{})
 var _ = admission.ValidationInterface(&buildByStrategy{})
 
 // NewBuildByStrategy returns an admission control for builds that checks
@@ -93,6 +109,16 @@ func (a *buildByStrategy) SetExternalKubeClientSet(c kubernetes.Interface) {
 	a.sarClient = c.AuthorizationV1().SubjectAccessReviews()
 }
 
+func (a *buildByStrategy) SetExternalKubeInformerFactory(kubeInformers informers.SharedInformerFactory) {
+	a.nsLister = kubeInformers.Core().V1().Namespaces().Lister()
+	a.nsListerSynced = kubeInformers.Core().V1().Namespaces().Informer().HasSynced
+}
+
+func (a *buildByStrategy) SetBuildStrategyPolicyInformer(informer cache.SharedIndexInformer) {
+	a.policies = informer.GetStore()
+	a.policiesSynced = informer.HasSynced
+}
+
 func (a *buildByStrategy) SetRESTClientConfig(restClientConfig rest.Config) {
 	var err error
 	a.buildClient, err = buildclient.NewForConfig(&restClientConfig)
@@ -109,6 +135,12 @@ func (a *buildByStrategy) ValidateInitialization() error {
 	if a.sarClient == nil {
 		return fmt.Errorf("build.openshift.io/BuildByStrategy needs an Openshift sarClient")
 	}
+	if a.policies == nil {
+		return fmt.Errorf("build.openshift.io/BuildByStrategy needs a build strategy policy informer")
+	}
+	if a.nsLister == nil {
+		return fmt.Errorf("build.openshift.io/BuildByStrategy needs a namespace lister")
+	}
 	return nil
 }
 
@@ -205,6 +237,7 @@ func (a *buildByStrategy) checkBuildRequestAuthorization(ctx context.Context, re
 		if err := buildv1helpers.Convert_v1_Build_To_build_Build(build, internalBuild, nil); err != nil {
 			return admission.NewForbidden(attr, err)
 		}
+		applyStrategyOptions(&internalBuild.Spec.Strategy, req)
 		return a.checkBuildAuthorization(ctx, internalBuild, attr)
 
 	case build.Resource("buildconfigs"),
@@ -217,6 +250,7 @@ func (a *buildByStrategy) checkBuildRequestAuthorization(ctx context.Context, re
 		if err := buildv1helpers.Convert_v1_BuildConfig_To_build_BuildConfig(buildConfig, internalBuildConfig, nil); err != nil {
 			return admission.NewForbidden(attr, err)
 		}
+		applyStrategyOptions(&internalBuildConfig.Spec.Strategy, req)
 		return a.checkBuildConfigAuthorization(ctx, internalBuildConfig, attr)
 	default:
 		return admission.NewForbidden(attr, fmt.Errorf("Unknown resource type %s for BuildRequest", attr.GetResource()))
@@ -231,7 +265,28 @@ func (a *buildByStrategy) checkAccess(ctx context.Context, strategy buildapi.Bui
 	if !resp.Status.Allowed {
 		return notAllowed(strategy, attr)
 	}
-	return nil
+	if strategyUnchanged(attr) {
+		return nil
+	}
+	return a.checkPolicy(strategy, attr)
+}
+
+// strategyUnchanged returns true if attr updates a build or build config without
+// changing its strategy. The build strategy policies were enforced when the
+// strategy was set, updates of other fields must not be rejected by them.
+func strategyUnchanged(attr admission.Attributes) bool {
+	if attr.GetOperation() != admission.Update {
+		return false
+	}
+	switch obj := attr.GetObject().(type) {
+	case *buildapi.Build:
+		old, ok := attr.GetOldObject().(*buildapi.Build)
+		return ok && kapihelper.Semantic.DeepEqual(obj.Spec.Strategy, old.Spec.Strategy)
+	case *buildapi.BuildConfig:
+		old, ok := attr.GetOldObject().(*buildapi.BuildConfig)
+		return ok && kapihelper.Semantic.DeepEqual(obj.Spec.Strategy, old.Spec.Strategy)
+	}
+	return false
 }
 
 func notAllowed(strategy buildapi.BuildStrategy, attr admission.Attributes) error {
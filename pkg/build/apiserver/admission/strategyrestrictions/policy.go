@@ -0,0 +1,174 @@
+package strategyrestrictions
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/admission"
+
+	buildv1 "github.com/openshift/api/build/v1"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+// checkPolicy rejects strategy if it violates a BuildStrategyPolicy selecting the
+// namespace of the request.
+func (a *buildByStrategy) checkPolicy(strategy buildapi.BuildStrategy, attr admission.Attributes) error {
+	if a.policies == nil {
+		return nil
+	}
+	if !a.waitForSyncedStore(time.After(timeToWaitForCacheSync)) {
+		return admission.NewForbidden(attr, errors.New("build.openshift.io/BuildByStrategy: caches not synchronized"))
+	}
+	policies := []*buildv1.BuildStrategyPolicy{}
+	for _, obj := range a.policies.List() {
+		if policy, ok := obj.(*buildv1.BuildStrategyPolicy); ok {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	// evaluate the policies in a stable order, so that the same violation is reported on every request
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Name < policies[j].Name })
+
+	namespace, err := a.nsLister.Get(attr.GetNamespace())
+	if err != nil {
+		return admission.NewForbidden(attr, err)
+	}
+	for _, policy := range policies {
+		selector := labels.Everything()
+		if policy.Spec.NamespaceSelector != nil {
+			selector, err = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+			if err != nil {
+				return admission.NewForbidden(attr, fmt.Errorf("build strategy policy %q: %v", policy.Name, err))
+			}
+		}
+		if !selector.Matches(labels.Set(namespace.Labels)) {
+			continue
+		}
+		if violations := policyViolations(&policy.Spec, strategy); len(violations) > 0 {
+			return admission.NewForbidden(attr, fmt.Errorf("build strategy policy %q: %s", policy.Name, strings.Join(violations, ", ")))
+		}
+	}
+	return nil
+}
+
+func (a *buildByStrategy) waitForSyncedStore(timeout <-chan time.Time) bool {
+	for !a.policiesSynced() || !a.nsListerSynced() {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			return a.policiesSynced() && a.nsListerSynced()
+		}
+	}
+	return true
+}
+
+// policyViolations returns the descriptions of the restrictions of policy strategy
+// violates.
+func policyViolations(policy *buildv1.BuildStrategyPolicySpec, strategy buildapi.BuildStrategy) []string {
+	violations := []string{}
+	strategyType := strategyTypeString(strategy)
+	if len(policy.AllowedStrategies) > 0 && !containsStrategyType(policy.AllowedStrategies, strategyType) {
+		violations = append(violations, fmt.Sprintf("build strategy %s is not allowed", strategyType))
+	}
+
+	var forcePull bool
+	var volumes []buildapi.BuildVolume
+	switch {
+	case strategy.DockerStrategy != nil:
+		docker := strategy.DockerStrategy
+		forcePull, volumes = docker.ForcePull, docker.Volumes
+		if policy.DisallowNoCache && docker.NoCache {
+			violations = append(violations, "noCache is not allowed")
+		}
+		if len(policy.RequiredImageOptimizationPolicies) > 0 {
+			imageOptimizationPolicy := ""
+			if docker.ImageOptimizationPolicy != nil {
+				imageOptimizationPolicy = string(*docker.ImageOptimizationPolicy)
+			}
+			required := []string{}
+			for _, p := range policy.RequiredImageOptimizationPolicies {
+				required = append(required, string(p))
+			}
+			if !contains(required, imageOptimizationPolicy) {
+				violations = append(violations, fmt.Sprintf("imageOptimizationPolicy must be one of %s", strings.Join(required, ", ")))
+			}
+		}
+	case strategy.SourceStrategy != nil:
+		forcePull, volumes = strategy.SourceStrategy.ForcePull, strategy.SourceStrategy.Volumes
+	case strategy.CustomStrategy != nil:
+		custom := strategy.CustomStrategy
+		forcePull = custom.ForcePull
+		if len(policy.AllowedCustomBuilderImages) > 0 && (custom.From.Kind != "DockerImage" || !imageAllowed(policy.AllowedCustomBuilderImages, custom.From.Name)) {
+			violations = append(violations, fmt.Sprintf("custom builder image %s %q is not allowed", custom.From.Kind, custom.From.Name))
+		}
+	}
+	if policy.DisallowForcePull && forcePull {
+		violations = append(violations, "forcePull is not allowed")
+	}
+	for _, volume := range volumes {
+		if containsVolumeSourceType(policy.ForbiddenBuildVolumeSourceTypes, string(volume.Source.Type)) {
+			violations = append(violations, fmt.Sprintf("build volume %q of source type %s is not allowed", volume.Name, volume.Source.Type))
+		}
+	}
+	return violations
+}
+
+// applyStrategyOptions applies the strategy options of req, which override those of
+// the build or build config it is created from, to strategy.
+func applyStrategyOptions(strategy *buildapi.BuildStrategy, req *buildapi.BuildRequest) {
+	if req.DockerStrategyOptions != nil && req.DockerStrategyOptions.NoCache != nil && strategy.DockerStrategy != nil {
+		strategy.DockerStrategy.NoCache = *req.DockerStrategyOptions.NoCache
+	}
+}
+
+// imageAllowed returns true if image matches one of allowed. A trailing * in allowed
+// matches any image with the preceding prefix.
+func imageAllowed(allowed []string, image string) bool {
+	for _, pattern := range allowed {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(image, prefix) {
+				return true
+			}
+			continue
+		}
+		if image == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStrategyType(values []buildv1.BuildStrategyType, value string) bool {
+	for _, v := range values {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsVolumeSourceType(values []buildv1.BuildVolumeSourceType, value string) bool {
+	for _, v := range values {
+		if string(v) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package strategyrestrictions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	fakekubeclient "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+
+	"github.com/openshift/api/build"
+	buildapiv1 "github.com/openshift/api/build/v1"
+	fakebuildclient "github.com/openshift/client-go/build/clientset/versioned/fake"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	skipLayers := buildapi.ImageOptimizationSkipLayers
+	tests := []struct {
+		name       string
+		policy     buildapiv1.BuildStrategyPolicySpec
+		strategy   buildapi.BuildStrategy
+		violations []string
+	}{
+		{
+			name:     "empty policy",
+			strategy: buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{NoCache: true, ForcePull: true}},
+		},
+		{
+			name:       "strategy not allowed",
+			policy:     buildapiv1.BuildStrategyPolicySpec{AllowedStrategies: []buildapiv1.BuildStrategyType{"Source"}},
+			strategy:   buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{}},
+			violations: []string{"build strategy Docker is not allowed"},
+		},
+		{
+			name:       "no cache and force pull",
+			policy:     buildapiv1.BuildStrategyPolicySpec{DisallowNoCache: true, DisallowForcePull: true},
+			strategy:   buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{NoCache: true, ForcePull: true}},
+			violations: []string{"noCache is not allowed", "forcePull is not allowed"},
+		},
+		{
+			name:       "source force pull",
+			policy:     buildapiv1.BuildStrategyPolicySpec{DisallowForcePull: true},
+			strategy:   buildapi.BuildStrategy{SourceStrategy: &buildapi.SourceBuildStrategy{ForcePull: true}},
+			violations: []string{"forcePull is not allowed"},
+		},
+		{
+			name:       "missing image optimization policy",
+			policy:     buildapiv1.BuildStrategyPolicySpec{RequiredImageOptimizationPolicies: []buildapiv1.ImageOptimizationPolicy{"SkipLayers"}},
+			strategy:   buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{}},
+			violations: []string{"imageOptimizationPolicy must be one of SkipLayers"},
+		},
+		{
+			name:     "required image optimization policy",
+			policy:   buildapiv1.BuildStrategyPolicySpec{RequiredImageOptimizationPolicies: []buildapiv1.ImageOptimizationPolicy{"SkipLayers"}},
+			strategy: buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{ImageOptimizationPolicy: &skipLayers}},
+		},
+		{
+			name:     "allowed custom builder image",
+			policy:   buildapiv1.BuildStrategyPolicySpec{AllowedCustomBuilderImages: []string{"registry.example.com/builders/*"}},
+			strategy: buildapi.BuildStrategy{CustomStrategy: &buildapi.CustomBuildStrategy{From: kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/builders/go:1.22"}}},
+		},
+		{
+			name:       "custom builder image not allowed",
+			policy:     buildapiv1.BuildStrategyPolicySpec{AllowedCustomBuilderImages: []string{"registry.example.com/builders/go:1.22"}},
+			strategy:   buildapi.BuildStrategy{CustomStrategy: &buildapi.CustomBuildStrategy{From: kapi.ObjectReference{Kind: "DockerImage", Name: "docker.io/evil/builder"}}},
+			violations: []string{`custom builder image DockerImage "docker.io/evil/builder" is not allowed`},
+		},
+		{
+			name:       "custom builder image stream tag",
+			policy:     buildapiv1.BuildStrategyPolicySpec{AllowedCustomBuilderImages: []string{"registry.example.com/builders/*"}},
+			strategy:   buildapi.BuildStrategy{CustomStrategy: &buildapi.CustomBuildStrategy{From: kapi.ObjectReference{Kind: "ImageStreamTag", Name: "builder:latest"}}},
+			violations: []string{`custom builder image ImageStreamTag "builder:latest" is not allowed`},
+		},
+		{
+			name:   "forbidden volume source type",
+			policy: buildapiv1.BuildStrategyPolicySpec{ForbiddenBuildVolumeSourceTypes: []buildapiv1.BuildVolumeSourceType{"CSI"}},
+			strategy: buildapi.BuildStrategy{SourceStrategy: &buildapi.SourceBuildStrategy{Volumes: []buildapi.BuildVolume{
+				{Name: "certs", Source: buildapi.BuildVolumeSource{Type: buildapi.BuildVolumeSourceTypeSecret}},
+				{Name: "cache", Source: buildapi.BuildVolumeSource{Type: buildapi.BuildVolumeSourceTypeCSI}},
+			}}},
+			violations: []string{`build volume "cache" of source type CSI is not allowed`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations := policyViolations(&test.policy, test.strategy)
+			if strings.Join(violations, "; ") != strings.Join(test.violations, "; ") {
+				t.Errorf("expected violations %q, got %q", test.violations, violations)
+			}
+		})
+	}
+}
+
+func TestBuildAdmissionPolicy(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "hardened", Labels: map[string]string{"builds": "hardened"}}})
+	indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}})
+	policies := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	policies.Add(&buildapiv1.BuildStrategyPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardened"},
+		Spec: buildapiv1.BuildStrategyPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"builds": "hardened"}},
+			DisallowNoCache:   true,
+		},
+	})
+	noCache := true
+
+	tests := []struct {
+		name           string
+		namespace      string
+		resource       string
+		subresource    string
+		object         runtime.Object
+		oldObject      runtime.Object
+		responseObject runtime.Object
+		expectAccept   bool
+	}{
+		{
+			name:         "allowed build outside selected namespace",
+			namespace:    "foo",
+			resource:     "builds",
+			object:       internalPolicyTestBuild("foo", true),
+			expectAccept: true,
+		},
+		{
+			name:         "allowed build in selected namespace",
+			namespace:    "hardened",
+			resource:     "builds",
+			object:       internalPolicyTestBuild("hardened", false),
+			expectAccept: true,
+		},
+		{
+			name:      "denied build in selected namespace",
+			namespace: "hardened",
+			resource:  "builds",
+			object:    internalPolicyTestBuild("hardened", true),
+		},
+		{
+			name:         "allowed update keeping the strategy",
+			namespace:    "hardened",
+			resource:     "builds",
+			object:       internalPolicyTestBuild("hardened", true),
+			oldObject:    internalPolicyTestBuild("hardened", true),
+			expectAccept: true,
+		},
+		{
+			name:      "denied update changing the strategy",
+			namespace: "hardened",
+			resource:  "builds",
+			object:    internalPolicyTestBuild("hardened", true),
+			oldObject: internalPolicyTestBuild("hardened", false),
+		},
+		{
+			name:        "denied instantiate overriding no cache",
+			namespace:   "hardened",
+			resource:    "buildconfigs",
+			subresource: "instantiate",
+			object: &buildapi.BuildRequest{
+				ObjectMeta:            metav1.ObjectMeta{Namespace: "hardened", Name: "test-buildconfig"},
+				DockerStrategyOptions: &buildapi.DockerStrategyOptions{NoCache: &noCache},
+			},
+			responseObject: &buildapiv1.BuildConfig{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "hardened", Name: "test-buildconfig"},
+				Spec: buildapiv1.BuildConfigSpec{CommonSpec: buildapiv1.CommonSpec{
+					Strategy: buildapiv1.BuildStrategy{DockerStrategy: &buildapiv1.DockerBuildStrategy{}},
+				}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeBuildClient := fakebuildclient.NewSimpleClientset()
+			if test.responseObject != nil {
+				fakeBuildClient = fakebuildclient.NewSimpleClientset(test.responseObject)
+			}
+			fakeKubeClient := fakekubeclient.NewSimpleClientset()
+			fakeKubeClient.PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, reviewResponse(true, ""), nil
+			})
+
+			c := NewBuildByStrategy().(*buildByStrategy)
+			c.sarClient = fakeKubeClient.AuthorizationV1().SubjectAccessReviews()
+			c.buildClient = fakeBuildClient
+			c.policies = policies
+			c.policiesSynced = func() bool { return true }
+			c.nsLister = corev1listers.NewNamespaceLister(indexer)
+			c.nsListerSynced = func() bool { return true }
+
+			operation := admission.Create
+			if test.oldObject != nil {
+				operation = admission.Update
+			}
+			attrs := admission.NewAttributesRecord(test.object, test.oldObject, build.Kind("Build").WithVersion("version"), test.namespace, "test-build", build.Resource(test.resource).WithVersion("version"), test.subresource, operation, nil, false, fakeUser())
+			err := c.Validate(context.TODO(), attrs, nil)
+			if test.expectAccept && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.expectAccept && !apierrors.IsForbidden(err) {
+				t.Errorf("expected a forbidden error, got %v", err)
+			}
+		})
+	}
+}
+
+func internalPolicyTestBuild(namespace string, noCache bool) *buildapi.Build {
+	build := internalTestBuild(buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{NoCache: noCache}})
+	build.Namespace = namespace
+	return build
+}
@@ -3,33 +3,58 @@ package apiserver
 import (
 	"fmt"
 	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/kubernetes"
+	kubetypedclient "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	buildv1 "github.com/openshift/api/build/v1"
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
 	buildv1client "github.com/openshift/client-go/build/clientset/versioned"
 	imagev1client "github.com/openshift/client-go/image/clientset/versioned"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/buildgenerator"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/logarchive"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
 	buildetcd "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/build/etcd"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildcancel"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildclone"
 	buildconfigregistry "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildconfig"
 	buildconfigetcd "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildconfig/etcd"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildconfiginstantiate"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/builddiff"
 	buildlogregistry "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildlog"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildprovenance"
+	buildstrategypolicyetcd "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildstrategypolicy/etcd"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/azuredevops"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/bitbucket"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/generic"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/gitea"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/github"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook/gitlab"
 )
 
 type ExtraConfig struct {
 	KubeAPIServerClientConfig *restclient.Config
+	// BuildLogArchive, if set, receives the logs of completed builds so that
+	// builds/log can serve them after the build pod is gone.
+	BuildLogArchive logarchive.Archive
+	// BuildLogArchiveRetention is how long archived build logs are kept. Zero
+	// keeps them forever.
+	BuildLogArchiveRetention time.Duration
+	// BuildProvenanceSigner, if set, signs the provenance statements recorded for
+	// completed builds.
+	BuildProvenanceSigner *provenance.Signer
+	// BinaryBuildUploadConfig configures the staging of resumable binary build
+	// uploads.
+	BinaryBuildUploadConfig openshiftcontrolplanev1.BinaryBuildUploadConfig
 
 	// TODO these should all become local eventually
 	Scheme *runtime.Scheme
@@ -38,6 +63,7 @@ type ExtraConfig struct {
 	makeV1Storage sync.Once
 	v1Storage     map[string]rest.Storage
 	v1StorageErr  error
+	startFns      []func(<-chan struct{})
 }
 
 type BuildServerConfig struct {
@@ -93,6 +119,15 @@ func (c completedConfig) New(delegationTarget genericapiserver.DelegationTarget)
 		return nil, err
 	}
 
+	if err := s.GenericAPIServer.AddPostStartHook("build.openshift.io-apiserver-controllers", func(context genericapiserver.PostStartHookContext) error {
+		for _, fn := range c.ExtraConfig.startFns {
+			go fn(context.StopCh)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -117,7 +152,7 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 	if err != nil {
 		return nil, err
 	}
-	buildStorage, buildDetailsStorage, err := buildetcd.NewREST(c.GenericConfig.RESTOptionsGetter)
+	buildStorage, buildDetailsStorage, err := buildetcd.NewREST(c.GenericConfig.RESTOptionsGetter, c.ExtraConfig.BuildProvenanceSigner)
 	if err != nil {
 		return nil, fmt.Errorf("error building REST storage: %v", err)
 	}
@@ -125,6 +160,10 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error building REST storage: %v", err)
 	}
+	buildStrategyPolicyStorage, err := buildstrategypolicyetcd.NewREST(c.GenericConfig.RESTOptionsGetter)
+	if err != nil {
+		return nil, fmt.Errorf("error building REST storage: %v", err)
+	}
 	// TODO: Move this to external versions at some point. The generator is only consumed by API server.
 	buildGenerator := &buildgenerator.BuildGenerator{
 		Client: buildgenerator.Client{
@@ -136,35 +175,68 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 		},
 		ServiceAccounts: kubeClient.CoreV1(),
 		Secrets:         kubeClient.CoreV1(),
+		Pods:            kubeClient.CoreV1(),
+	}
+	eventScheme := runtime.NewScheme()
+	if err := buildv1.Install(eventScheme); err != nil {
+		return nil, err
 	}
+	eventBroadcaster := record.NewBroadcaster()
+	c.ExtraConfig.startFns = append(c.ExtraConfig.startFns, func(stopCh <-chan struct{}) {
+		eventBroadcaster.StartRecordingToSink(&kubetypedclient.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+		<-stopCh
+		eventBroadcaster.Shutdown()
+	})
 	buildConfigWebHooks := buildconfigregistry.NewWebHookREST(
 		buildClient.BuildV1(),
 		kubeClient.CoreV1(),
+		eventBroadcaster.NewRecorder(eventScheme, corev1.EventSource{Component: "openshift-apiserver"}),
 		// We use the buildv1 schemegroup to encode the Build that gets
 		// returned. As such, we need to make sure that the GroupVersion we use
 		// is the same API version that the storage is going to be used for.
 		buildv1.GroupVersion,
 		map[string]webhook.Plugin{
-			"generic":   generic.New(),
-			"github":    github.New(),
-			"gitlab":    gitlab.New(),
-			"bitbucket": bitbucket.New(),
+			"generic":     generic.New(),
+			"github":      github.New(),
+			"gitlab":      gitlab.New(),
+			"bitbucket":   bitbucket.New(),
+			"gitea":       gitea.New(),
+			"azuredevops": azuredevops.New(),
 		},
 	)
 
 	v1Storage := map[string]rest.Storage{}
 	v1Storage["builds"] = buildStorage
 	v1Storage["builds/clone"] = buildclone.NewStorage(buildGenerator)
+	v1Storage["builds/cancel"] = buildcancel.NewStorage(buildGenerator)
 	v1Storage["builds/log"] = buildlogregistry.NewREST(
 		buildClient.BuildV1(),
 		kubeClient.CoreV1(),
 		c.GenericConfig.SharedInformerFactory.Core().V1().Pods().Lister(),
+		c.ExtraConfig.BuildLogArchive,
 	)
+	buildControllers := []buildController{
+		buildgenerator.NewBuildRetrier(buildGenerator),
+		buildgenerator.NewOutputTagger(buildClient.BuildV1(), imageClient.ImageV1()),
+	}
+	if c.ExtraConfig.BuildLogArchive != nil {
+		buildControllers = append(buildControllers, buildlogregistry.NewArchiver(buildClient.BuildV1(), kubeClient.CoreV1(), c.ExtraConfig.BuildLogArchive, c.ExtraConfig.BuildLogArchiveRetention))
+	}
+	c.ExtraConfig.startFns = append(c.ExtraConfig.startFns, runBuildControllers(kubeClient, buildClient.BuildV1(), buildControllers...))
 	v1Storage["builds/details"] = buildDetailsStorage
+	v1Storage["builds/provenance"] = buildprovenance.NewREST(buildClient.BuildV1())
+	v1Storage["builds/diff"] = builddiff.NewREST(buildClient.BuildV1(), buildGenerator, kubeClient.AuthorizationV1().SubjectAccessReviews())
 
 	v1Storage["buildconfigs"] = buildConfigStorage
 	v1Storage["buildconfigs/webhooks"] = buildConfigWebHooks
+	v1Storage["buildconfigs/cancel"] = buildcancel.NewBuildConfigStorage(buildGenerator)
 	v1Storage["buildconfigs/instantiate"] = buildconfiginstantiate.NewStorage(buildGenerator)
-	v1Storage["buildconfigs/instantiatebinary"] = buildconfiginstantiate.NewBinaryStorage(buildGenerator, buildClient.BuildV1(), c.ExtraConfig.KubeAPIServerClientConfig)
+	binaryInstantiateStorage, err := buildconfiginstantiate.NewBinaryStorage(buildGenerator, buildClient.BuildV1(), c.ExtraConfig.KubeAPIServerClientConfig, c.ExtraConfig.BinaryBuildUploadConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error building REST storage: %v", err)
+	}
+	v1Storage["buildconfigs/instantiatebinary"] = binaryInstantiateStorage
+
+	v1Storage["buildstrategypolicies"] = buildStrategyPolicyStorage
 	return v1Storage, nil
 }
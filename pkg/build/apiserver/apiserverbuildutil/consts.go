@@ -1,16 +1,24 @@
 package apiserverbuildutil
 
 const (
-	BuildTriggerCauseGithubMsg    = "GitHub WebHook"
-	BuildTriggerCauseGenericMsg   = "Generic WebHook"
-	BuildTriggerCauseGitLabMsg    = "GitLab WebHook"
-	BuildTriggerCauseBitbucketMsg = "Bitbucket WebHook"
+	BuildTriggerCauseGithubMsg      = "GitHub WebHook"
+	BuildTriggerCauseGenericMsg     = "Generic WebHook"
+	BuildTriggerCauseGitLabMsg      = "GitLab WebHook"
+	BuildTriggerCauseBitbucketMsg   = "Bitbucket WebHook"
+	BuildTriggerCauseGiteaMsg       = "Gitea WebHook"
+	BuildTriggerCauseAzureDevOpsMsg = "Azure DevOps WebHook"
 )
 
 const (
 	// GitCloneContainer is the name of the container that will clone the
 	// build source repository and also handle binary input content.
 	GitCloneContainer = "git-clone"
+	// ExtractImageContentContainer is the name of the container that extracts
+	// the content of source images.
+	ExtractImageContentContainer = "extract-image-content"
+	// ManageDockerfileContainer is the name of the container that writes or
+	// updates the Dockerfile of docker strategy builds.
+	ManageDockerfileContainer = "manage-dockerfile"
 )
 
 const (
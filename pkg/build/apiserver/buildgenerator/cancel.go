@@ -0,0 +1,203 @@
+package buildgenerator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/build/naming"
+
+	internal "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+// CancelOptions describe why a build is cancelled.
+type CancelOptions struct {
+	// Reason is a brief CamelCase reason, it defaults to CancelledBuild.
+	Reason string
+	// Message is a human readable description of the cancellation.
+	Message string
+	// GracePeriodSeconds, if set, is the grace period the build pod is deleted
+	// with. Otherwise the build controller deletes it with its own.
+	GracePeriodSeconds *int64
+}
+
+// Cancel marks the named build for cancellation and records who cancelled it and
+// why in its CancelRequested condition. Builds which are completed are returned
+// unchanged, and builds which are already cancelled only have their pod deleted
+// with the requested grace period.
+func (g *BuildGenerator) Cancel(ctx context.Context, name string, options CancelOptions) (*buildv1.Build, error) {
+	var err error
+	for i := 0; i < conflictRetries; i++ {
+		var build *buildv1.Build
+		build, err = g.Client.GetBuild(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if isCompleted(build) {
+			return build, nil
+		}
+		if build.Status.Cancelled {
+			return build, g.deleteBuildPod(ctx, build, options)
+		}
+		setCancelled(ctx, build, options)
+		err = g.Client.UpdateBuild(ctx, build, metav1.UpdateOptions{})
+		if errors.IsConflict(err) {
+			klog.V(4).Infof("cancel returned conflict, try %d/%d", i+1, conflictRetries)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		klog.V(4).Infof("Build %s/%s has been marked for cancellation: %s", build.Namespace, build.Name, options.Reason)
+		if err := g.deleteBuildPod(ctx, build, options); err != nil {
+			return nil, err
+		}
+		return g.Client.GetBuild(ctx, name, metav1.GetOptions{})
+	}
+	return nil, err
+}
+
+// CancelBuildConfigBuilds cancels the builds of the named BuildConfig which are
+// not completed, match selector and, if createdBefore is set, were created before it.
+func (g *BuildGenerator) CancelBuildConfigBuilds(ctx context.Context, name string, selector labels.Selector, createdBefore *metav1.Time, options CancelOptions) ([]buildv1.Build, error) {
+	if _, err := g.Client.GetBuildConfig(ctx, name, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+	requirement, err := labels.NewRequirement(buildv1.BuildConfigLabel, selection.Equals, []string{labelValue(name)})
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+	list, err := g.Client.ListBuilds(ctx, metav1.ListOptions{LabelSelector: selector.Add(*requirement).String()})
+	if err != nil {
+		return nil, err
+	}
+	cancelled := []buildv1.Build{}
+	for _, build := range list.Items {
+		// the label value may be truncated, the annotation holds the full name
+		if build.Annotations[buildv1.BuildConfigAnnotation] != name {
+			continue
+		}
+		if build.Status.Cancelled || isCompleted(&build) {
+			continue
+		}
+		if createdBefore != nil && !build.CreationTimestamp.Before(createdBefore) {
+			continue
+		}
+		updated, err := g.Cancel(ctx, build.Name, options)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return cancelled, err
+		}
+		cancelled = append(cancelled, *updated)
+	}
+	return cancelled, nil
+}
+
+// supersedeBuilds cancels the New builds of a SerialLatestOnly BuildConfig which
+// were created before build, as only the latest of them would ever run.
+func (g *BuildGenerator) supersedeBuilds(ctx context.Context, bc *buildv1.BuildConfig, build *buildv1.Build) {
+	if bc == nil || bc.Spec.RunPolicy != buildv1.BuildRunPolicySerialLatestOnly {
+		return
+	}
+	list, err := g.Client.ListBuilds(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{buildv1.BuildConfigLabel: labelValue(bc.Name)}).String(),
+	})
+	if err != nil {
+		klog.V(2).Infof("Unable to list the builds superseded by build %s/%s: %v", build.Namespace, build.Name, err)
+		return
+	}
+	number := buildNumber(build)
+	for _, older := range list.Items {
+		if older.Name == build.Name || older.Status.Phase != buildv1.BuildPhaseNew || older.Status.Cancelled ||
+			older.Annotations[buildv1.BuildConfigAnnotation] != bc.Name || buildNumber(&older) >= number {
+			continue
+		}
+		_, err := g.Cancel(ctx, older.Name, CancelOptions{
+			Reason:  string(internal.StatusReasonSupersededBuild),
+			Message: fmt.Sprintf("Superseded by build %s.", build.Name),
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			klog.V(2).Infof("Unable to cancel build %s/%s superseded by build %s: %v", older.Namespace, older.Name, build.Name, err)
+		}
+	}
+}
+
+// deleteBuildPod deletes the pod of a cancelled build with the grace period of
+// options, so that it is not killed with the grace period of the build controller.
+// A pod which does not exist yet is never created for a cancelled build.
+func (g *BuildGenerator) deleteBuildPod(ctx context.Context, build *buildv1.Build, options CancelOptions) error {
+	if options.GracePeriodSeconds == nil || g.Pods == nil {
+		return nil
+	}
+	podName := build.Annotations[buildv1.BuildPodNameAnnotation]
+	if len(podName) == 0 {
+		podName = naming.GetPodName(build.Name, "build")
+	}
+	err := g.Pods.Pods(build.Namespace).Delete(ctx, podName, metav1.DeleteOptions{GracePeriodSeconds: options.GracePeriodSeconds})
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.NewInternalError(fmt.Errorf("build %s was cancelled, but its pod could not be deleted with a grace period of %ds: %v", build.Name, *options.GracePeriodSeconds, err))
+	}
+	klog.V(4).Infof("Deleted the pod %s/%s of cancelled build %s with a grace period of %ds", build.Namespace, podName, build.Name, *options.GracePeriodSeconds)
+	return nil
+}
+
+func setCancelled(ctx context.Context, build *buildv1.Build, options CancelOptions) {
+	reason := options.Reason
+	if len(reason) == 0 {
+		reason = string(internal.StatusReasonCancelledBuild)
+	}
+	message := options.Message
+	if len(message) == 0 {
+		message = internal.StatusMessageCancelledBuild
+	}
+	if user, ok := apirequest.UserFrom(ctx); ok {
+		message = fmt.Sprintf("Cancelled by %s: %s", user.GetName(), message)
+	}
+
+	build.Status.Cancelled = true
+
+	now := metav1.Now()
+	condition := buildv1.BuildCondition{
+		Type:               buildv1.BuildConditionType(internal.BuildConditionCancelRequested),
+		Status:             corev1.ConditionTrue,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	}
+	for i := range build.Status.Conditions {
+		if build.Status.Conditions[i].Type == condition.Type {
+			build.Status.Conditions[i] = condition
+			return
+		}
+	}
+	build.Status.Conditions = append(build.Status.Conditions, condition)
+}
+
+// buildNumber returns the number of build within its BuildConfig, or 0 if it is unknown.
+func buildNumber(build *buildv1.Build) int64 {
+	number, err := strconv.ParseInt(build.Annotations[buildv1.BuildNumberAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+func isCompleted(build *buildv1.Build) bool {
+	switch build.Status.Phase {
+	case buildv1.BuildPhaseComplete, buildv1.BuildPhaseFailed, buildv1.BuildPhaseCancelled, buildv1.BuildPhaseError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package buildgenerator
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/authentication/user"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+// cancelTestGenerator returns a generator backed by builds, which are updated in place.
+func cancelTestGenerator(bc *buildv1.BuildConfig, builds map[string]*buildv1.Build) *BuildGenerator {
+	return &BuildGenerator{Client: TestingClient{
+		GetBuildConfigFunc: func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.BuildConfig, error) {
+			if bc == nil || bc.Name != name {
+				return nil, errors.NewNotFound(buildv1.Resource("buildconfigs"), name)
+			}
+			return bc, nil
+		},
+		GetBuildFunc: func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.Build, error) {
+			build, ok := builds[name]
+			if !ok {
+				return nil, errors.NewNotFound(buildv1.Resource("builds"), name)
+			}
+			return build.DeepCopy(), nil
+		},
+		ListBuildsFunc: func(ctx context.Context, options metav1.ListOptions) (*buildv1.BuildList, error) {
+			selector, err := labels.Parse(options.LabelSelector)
+			if err != nil {
+				return nil, err
+			}
+			list := &buildv1.BuildList{}
+			for _, build := range builds {
+				if selector.Matches(labels.Set(build.Labels)) {
+					list.Items = append(list.Items, *build.DeepCopy())
+				}
+			}
+			return list, nil
+		},
+		UpdateBuildFunc: func(ctx context.Context, build *buildv1.Build, options metav1.UpdateOptions) error {
+			builds[build.Name] = build.DeepCopy()
+			return nil
+		},
+	}}
+}
+
+func cancelTestBuild(name string, number int, phase buildv1.BuildPhase, created time.Time) *buildv1.Build {
+	return &buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{buildv1.BuildConfigLabel: "bc"},
+			Annotations: map[string]string{
+				buildv1.BuildConfigAnnotation: "bc",
+				buildv1.BuildNumberAnnotation: strconv.Itoa(number),
+			},
+		},
+		Status: buildv1.BuildStatus{Phase: phase},
+	}
+}
+
+func cancelCondition(build *buildv1.Build) *buildv1.BuildCondition {
+	for i := range build.Status.Conditions {
+		if build.Status.Conditions[i].Type == "CancelRequested" {
+			return &build.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestCancel(t *testing.T) {
+	builds := map[string]*buildv1.Build{
+		"running":  cancelTestBuild("running", 1, buildv1.BuildPhaseRunning, time.Now()),
+		"complete": cancelTestBuild("complete", 2, buildv1.BuildPhaseComplete, time.Now()),
+	}
+	generator := cancelTestGenerator(nil, builds)
+	ctx := apirequest.WithUser(apirequest.WithNamespace(context.Background(), "default"), &user.DefaultInfo{Name: "developer"})
+
+	build, err := generator.Cancel(ctx, "running", CancelOptions{Reason: "Obsolete", Message: "Replaced by a hotfix."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !build.Status.Cancelled {
+		t.Errorf("expected the build to be cancelled")
+	}
+	condition := cancelCondition(build)
+	if condition == nil || condition.Reason != "Obsolete" || condition.Message != "Cancelled by developer: Replaced by a hotfix." {
+		t.Errorf("unexpected CancelRequested condition %#v", condition)
+	}
+
+	build, err = generator.Cancel(ctx, "complete", CancelOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Status.Cancelled || cancelCondition(build) != nil {
+		t.Errorf("expected the completed build to be left alone")
+	}
+}
+
+func TestCancelWithGracePeriod(t *testing.T) {
+	builds := map[string]*buildv1.Build{
+		"running": cancelTestBuild("running", 1, buildv1.BuildPhaseRunning, time.Now()),
+		"new":     cancelTestBuild("new", 2, buildv1.BuildPhaseNew, time.Now()),
+	}
+	generator := cancelTestGenerator(nil, builds)
+	kubeClient := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "running-build"}})
+	generator.Pods = kubeClient.CoreV1()
+	ctx := apirequest.WithNamespace(context.Background(), "default")
+
+	gracePeriod := int64(30)
+	if _, err := generator.Cancel(ctx, "running", CancelOptions{GracePeriodSeconds: &gracePeriod}); err != nil {
+		t.Fatal(err)
+	}
+	deletes := 0
+	for _, action := range kubeClient.Actions() {
+		deleteAction, ok := action.(clientgotesting.DeleteActionImpl)
+		if !ok {
+			continue
+		}
+		deletes++
+		if deleteAction.Name != "running-build" || deleteAction.DeleteOptions.GracePeriodSeconds == nil || *deleteAction.DeleteOptions.GracePeriodSeconds != gracePeriod {
+			t.Errorf("unexpected delete of the build pod %#v", deleteAction)
+		}
+	}
+	if deletes != 1 {
+		t.Errorf("expected the build pod to be deleted once, got %d deletes", deletes)
+	}
+
+	// a build whose pod was not created yet is only marked for cancellation
+	build, err := generator.Cancel(ctx, "new", CancelOptions{GracePeriodSeconds: &gracePeriod})
+	if err != nil || !build.Status.Cancelled {
+		t.Errorf("expected the new build to be cancelled, got %v", err)
+	}
+}
+
+func TestCancelBuildConfigBuilds(t *testing.T) {
+	now := time.Now()
+	bc := &buildv1.BuildConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bc"}}
+	other := cancelTestBuild("other", 1, buildv1.BuildPhaseRunning, now.Add(-time.Hour))
+	other.Annotations[buildv1.BuildConfigAnnotation] = "bc-with-a-truncated-label"
+	builds := map[string]*buildv1.Build{
+		"old":      cancelTestBuild("old", 1, buildv1.BuildPhaseRunning, now.Add(-time.Hour)),
+		"new":      cancelTestBuild("new", 2, buildv1.BuildPhaseNew, now),
+		"complete": cancelTestBuild("complete", 3, buildv1.BuildPhaseComplete, now.Add(-time.Hour)),
+		"other":    other,
+	}
+	generator := cancelTestGenerator(bc, builds)
+	ctx := apirequest.WithNamespace(context.Background(), "default")
+
+	createdBefore := metav1.NewTime(now.Add(-time.Minute))
+	cancelled, err := generator.CancelBuildConfigBuilds(ctx, "bc", labels.Everything(), &createdBefore, CancelOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cancelled) != 1 || cancelled[0].Name != "old" {
+		t.Errorf("expected only build old to be cancelled, got %v", cancelled)
+	}
+
+	if _, err := generator.CancelBuildConfigBuilds(ctx, "missing", labels.Everything(), nil, CancelOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestSupersedeBuilds(t *testing.T) {
+	now := time.Now()
+	bc := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bc"},
+		Spec:       buildv1.BuildConfigSpec{RunPolicy: buildv1.BuildRunPolicySerialLatestOnly},
+	}
+	builds := map[string]*buildv1.Build{
+		"bc-1": cancelTestBuild("bc-1", 1, buildv1.BuildPhaseRunning, now),
+		"bc-2": cancelTestBuild("bc-2", 2, buildv1.BuildPhaseNew, now),
+		"bc-3": cancelTestBuild("bc-3", 3, buildv1.BuildPhaseNew, now),
+	}
+	generator := cancelTestGenerator(bc, builds)
+	generator.supersedeBuilds(apirequest.WithNamespace(context.Background(), "default"), bc, builds["bc-3"])
+
+	for name, expected := range map[string]bool{"bc-1": false, "bc-2": true, "bc-3": false} {
+		if builds[name].Status.Cancelled != expected {
+			t.Errorf("%s: expected cancelled %v, got %v", name, expected, builds[name].Status.Cancelled)
+		}
+	}
+	if condition := cancelCondition(builds["bc-2"]); condition == nil || condition.Reason != "SupersededBuild" {
+		t.Errorf("unexpected CancelRequested condition %#v", condition)
+	}
+}
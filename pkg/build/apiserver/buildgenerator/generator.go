@@ -43,6 +43,8 @@ type BuildGenerator struct {
 	Client          GeneratorClient
 	ServiceAccounts corev1client.ServiceAccountsGetter
 	Secrets         corev1client.SecretsGetter
+	// Pods, if set, deletes the pods of builds cancelled with a grace period.
+	Pods corev1client.PodsGetter
 }
 
 // GeneratorClient is the API client used by the generator
@@ -50,6 +52,7 @@ type GeneratorClient interface {
 	GetBuildConfig(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.BuildConfig, error)
 	UpdateBuildConfig(ctx context.Context, buildConfig *buildv1.BuildConfig, options metav1.UpdateOptions) error
 	GetBuild(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.Build, error)
+	ListBuilds(ctx context.Context, options metav1.ListOptions) (*buildv1.BuildList, error)
 	CreateBuild(ctx context.Context, build *buildv1.Build, options metav1.CreateOptions) error
 	UpdateBuild(ctx context.Context, build *buildv1.Build, options metav1.UpdateOptions) error
 	GetImageStream(ctx context.Context, name string, options metav1.GetOptions) (*imagev1.ImageStream, error)
@@ -82,6 +85,11 @@ func (c Client) GetBuild(ctx context.Context, name string, options metav1.GetOpt
 	return c.Builds.Builds(apirequest.NamespaceValue(ctx)).Get(ctx, name, options)
 }
 
+// ListBuilds lists builds
+func (c Client) ListBuilds(ctx context.Context, options metav1.ListOptions) (*buildv1.BuildList, error) {
+	return c.Builds.Builds(apirequest.NamespaceValue(ctx)).List(ctx, options)
+}
+
 // CreateBuild creates a new build
 func (c Client) CreateBuild(ctx context.Context, build *buildv1.Build, options metav1.CreateOptions) error {
 	_, err := c.Builds.Builds(apirequest.NamespaceValue(ctx)).Create(ctx, build, options)
@@ -279,7 +287,7 @@ func (g *BuildGenerator) instantiate(ctx context.Context, request *buildv1.Build
 		return nil, errors.NewInternalError(err)
 	}
 
-	newBuild, err := g.generateBuildFromConfig(ctx, bc, request.Revision, request.Binary)
+	newBuild, err := g.generateBuildFromConfig(ctx, bc, request.Revision, request.Binary, request.Parameters)
 	if err != nil {
 		if _, ok := err.(errors.APIStatus); ok {
 			return nil, err
@@ -347,7 +355,12 @@ func (g *BuildGenerator) instantiate(ctx context.Context, request *buildv1.Build
 	// create the corresponding build, however doing things in that order
 	// allows for a race condition in which two builds get kicked off.  Doing
 	// it in this order ensures that we catch the race while updating the BC.
-	return g.createBuild(ctx, newBuild, opts)
+	build, err := g.createBuild(ctx, newBuild, opts)
+	if err != nil {
+		return nil, err
+	}
+	g.supersedeBuilds(ctx, bc, build)
+	return build, nil
 }
 
 // checkLastVersion will return an error if the BuildConfig's LastVersion doesn't match the passed in lastVersion
@@ -435,6 +448,25 @@ func (g *BuildGenerator) updateImageTriggers(ctx context.Context, bc *buildv1.Bu
 	return nil
 }
 
+// Generate returns the build the named BuildConfig would produce if it was
+// instantiated now, with its images resolved. The build is not created and the
+// BuildConfig is not updated.
+func (g *BuildGenerator) Generate(ctx context.Context, name string) (*buildv1.Build, error) {
+	bc, err := g.Client.GetBuildConfig(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	build, err := g.generateBuildFromConfig(ctx, bc, nil, nil, nil)
+	if err != nil {
+		if _, ok := err.(errors.APIStatus); ok {
+			return nil, err
+		}
+		return nil, errors.NewInternalError(err)
+	}
+	build.Namespace = bc.Namespace
+	return build, nil
+}
+
 // Clone returns clone of a Build
 // DEPRECATED: Use only in apiserver
 func (g *BuildGenerator) CloneInternal(ctx context.Context, request *internal.BuildRequest) (*internal.Build, error) {
@@ -455,11 +487,22 @@ func (g *BuildGenerator) CloneInternal(ctx context.Context, request *internal.Bu
 
 // Clone returns clone of a Build
 func (g *BuildGenerator) Clone(ctx context.Context, request *buildv1.BuildRequest) (*buildv1.Build, error) {
+	return g.cloneWithOptions(ctx, request, cloneOptions{})
+}
+
+// cloneOptions customize the clones the apiserver makes itself, as opposed to
+// the clones requested on builds/clone.
+type cloneOptions struct {
+	// name, if set, replaces the generated name of the clone.
+	name string
+}
+
+func (g *BuildGenerator) cloneWithOptions(ctx context.Context, request *buildv1.BuildRequest, options cloneOptions) (*buildv1.Build, error) {
 	var build *buildv1.Build
 	var err error
 
 	for i := 0; i < conflictRetries; i++ {
-		build, err = g.clone(ctx, request)
+		build, err = g.clone(ctx, request, options)
 		if err == nil || !errors.IsConflict(err) {
 			break
 		}
@@ -469,7 +512,7 @@ func (g *BuildGenerator) Clone(ctx context.Context, request *buildv1.BuildReques
 	return build, err
 }
 
-func (g *BuildGenerator) clone(ctx context.Context, request *buildv1.BuildRequest) (*buildv1.Build, error) {
+func (g *BuildGenerator) clone(ctx context.Context, request *buildv1.BuildRequest, options cloneOptions) (*buildv1.Build, error) {
 	klog.V(4).Infof("Generating build from build %s/%s", request.Namespace, request.Name)
 	build, err := g.Client.GetBuild(ctx, request.Name, metav1.GetOptions{})
 	if err != nil {
@@ -491,6 +534,10 @@ func (g *BuildGenerator) clone(ctx context.Context, request *buildv1.BuildReques
 	newBuild := generateBuildFromBuild(build, buildConfig)
 	klog.V(4).Infof("Build %s/%s has been generated from Build %s/%s", newBuild.Namespace, newBuild.ObjectMeta.Name, build.Namespace, build.ObjectMeta.Name)
 
+	if len(options.name) > 0 {
+		newBuild.Name = options.name
+	}
+
 	// Copy build trigger information to the build object.
 	newBuild.Spec.TriggeredBy = request.TriggeredBy
 
@@ -517,7 +564,12 @@ func (g *BuildGenerator) clone(ctx context.Context, request *buildv1.BuildReques
 		}
 	}
 
-	return g.createBuild(ctx, newBuild, metav1.CreateOptions{})
+	created, err := g.createBuild(ctx, newBuild, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	g.supersedeBuilds(ctx, buildConfig, created)
+	return created, nil
 }
 
 // GeneratorFatalError represents a fatal error while generating a build.
@@ -553,8 +605,9 @@ func (g *BuildGenerator) createBuild(ctx context.Context, build *buildv1.Build,
 // from any ImageStream that is associated to the BuildConfig by From reference in
 // the Strategy, or uses the Image field of the Strategy. If binary is provided, override
 // the current build strategy with a binary artifact for this specific build.
-// Takes a BuildConfig to base the build on, and an optional SourceRevision to build.
-func (g *BuildGenerator) generateBuildFromConfig(ctx context.Context, bc *buildv1.BuildConfig, revision *buildv1.SourceRevision, binary *buildv1.BinaryBuildSource) (*buildv1.Build, error) {
+// Takes a BuildConfig to base the build on, an optional SourceRevision to build and
+// optional values for the parameters of the BuildConfig.
+func (g *BuildGenerator) generateBuildFromConfig(ctx context.Context, bc *buildv1.BuildConfig, revision *buildv1.SourceRevision, binary *buildv1.BinaryBuildSource, parameters map[string]string) (*buildv1.Build, error) {
 
 	// Need to copy the buildConfig here so that it doesn't share pointers with
 	// the build object which could be (will be) modified later.
@@ -612,6 +665,9 @@ func (g *BuildGenerator) generateBuildFromConfig(ctx context.Context, bc *buildv
 
 	setBuildSource(binary, build)
 	setBuildAnnotationAndLabel(bcCopy, build)
+	if err := applyBuildParameters(bcCopy, build, parameters); err != nil {
+		return nil, err
+	}
 
 	var builderSecrets []corev1.Secret
 	var err error
@@ -981,6 +1037,9 @@ func generateBuildFromBuild(build *buildv1.Build, buildConfig *buildv1.BuildConf
 	// remove the BuildPodNameAnnotation for good measure.
 	delete(newBuild.Annotations, buildv1.BuildPodNameAnnotation)
 
+	// the tagging state belongs to the cloned build.
+	delete(newBuild.Annotations, internal.BuildOutputTagsAppliedAnnotation)
+
 	return newBuild
 }
 
@@ -81,6 +81,29 @@ func TestInstantiateBinary(t *testing.T) {
 	}
 }
 
+func TestGenerate(t *testing.T) {
+	generator := mockBuildGenerator(nil,
+		func(ctx context.Context, buildConfig *buildv1.BuildConfig, _ metav1.UpdateOptions) error {
+			t.Errorf("unexpected BuildConfig update")
+			return nil
+		},
+		func(ctx context.Context, build *buildv1.Build, _ metav1.CreateOptions) error {
+			t.Errorf("unexpected build creation")
+			return nil
+		},
+		nil, nil, nil, nil)
+	build, err := generator.Generate(apirequest.NewDefaultContext(), "test-build-config")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if build.Status.Config == nil || build.Status.Config.Name != "test-build-config" {
+		t.Errorf("expected the build to refer to its BuildConfig, got %#v", build.Status.Config)
+	}
+	if from := build.Spec.Strategy.SourceStrategy.From; from.Kind != "DockerImage" {
+		t.Errorf("expected the builder image to be resolved, got %#v", from)
+	}
+}
+
 // TODO(agoldste): I'm not sure the intent of this test. Using the previous logic for
 // the generator, which would try to update the build config before creating
 // the build, I can see why the UpdateBuildConfigFunc is set up to return an
@@ -1057,7 +1080,7 @@ func TestGenerateBuildFromConfig(t *testing.T) {
 	}
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
 
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1109,7 +1132,7 @@ func TestGenerateBuildFromConfig(t *testing.T) {
 	}
 	// Test long name
 	bc.Name = strings.Repeat("a", 100)
-	build, err = generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil)
+	build, err = generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1189,7 +1212,7 @@ func TestGenerateBuildWithImageTagForSourceStrategyImageRepository(t *testing.T)
 			},
 		}}
 
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1259,7 +1282,7 @@ func TestGenerateBuildWithImageTagForDockerStrategyImageRepository(t *testing.T)
 			},
 		}}
 
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1329,7 +1352,7 @@ func TestGenerateBuildWithImageTagForCustomStrategyImageRepository(t *testing.T)
 			},
 		}}
 
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1495,7 +1518,7 @@ func TestSubstituteImageCustomAllMatch(t *testing.T) {
 	output := MockOutput()
 	bc := MockBuildConfig(source, strategy, output)
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1529,7 +1552,7 @@ func TestSubstituteImageCustomAllMismatch(t *testing.T) {
 	output := MockOutput()
 	bc := MockBuildConfig(source, strategy, output)
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1548,7 +1571,7 @@ func TestSubstituteImageCustomBaseMatchEnvMismatch(t *testing.T) {
 	output := MockOutput()
 	bc := MockBuildConfig(source, strategy, output)
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1576,7 +1599,7 @@ func TestSubstituteImageCustomBaseMatchEnvMissing(t *testing.T) {
 	output := MockOutput()
 	bc := MockBuildConfig(source, strategy, output)
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1605,7 +1628,7 @@ func TestSubstituteImageCustomBaseMatchEnvNil(t *testing.T) {
 	output := MockOutput()
 	bc := MockBuildConfig(source, strategy, output)
 	generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
-	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil)
+	build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error %v", err)
 	}
@@ -1967,7 +1990,7 @@ func TestGenerateBuildFromConfigWithSecrets(t *testing.T) {
 		output := mockOutputWithImageName(imageName, metav1.GetOptions{})
 		generator := mockBuildGenerator(nil, nil, nil, nil, nil, nil, nil)
 		bc := MockBuildConfig(source, strategy, output)
-		build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil)
+		build, err := generator.generateBuildFromConfig(apirequest.NewContext(), bc, revision, nil, nil)
 
 		if build.Spec.Strategy.DockerStrategy.PullSecret == nil {
 			t.Errorf("Expected PullSecret for image '%s' to be set, got nil", imageName)
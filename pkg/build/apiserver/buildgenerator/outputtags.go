@@ -0,0 +1,289 @@
+package buildgenerator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	buildv1clienttyped "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+	imagev1clienttyped "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/openshift/library-go/pkg/image/imageutil"
+
+	internal "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+)
+
+const maxTagRetries = 5
+
+var commitRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// OutputTagger tags the output images of completed builds with the additional
+// tags of their spec.output.additionalTags. All tags of a build are written to its
+// output image stream in a single update. Only image streams in the namespace of
+// the build are tagged.
+type OutputTagger struct {
+	BuildClient  buildv1clienttyped.BuildsGetter
+	ImageStreams imagev1clienttyped.ImageStreamsGetter
+
+	queue workqueue.TypedRateLimitingInterface[string]
+	store cache.Store
+}
+
+// NewOutputTagger creates an OutputTagger for the builds of all namespaces.
+func NewOutputTagger(buildClient buildv1clienttyped.BuildsGetter, imageStreams imagev1clienttyped.ImageStreamsGetter) *OutputTagger {
+	return &OutputTagger{BuildClient: buildClient, ImageStreams: imageStreams}
+}
+
+// Run tags the output images of the completed builds observed by informer until
+// stopCh is closed.
+func (t *OutputTagger) Run(informer cache.SharedIndexInformer, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	t.queue = workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "buildoutputtagger"},
+	)
+	defer t.queue.ShutDown()
+	t.store = informer.GetStore()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: t.enqueueBuild,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldObj.(*buildv1.Build).Status.Phase != buildv1.BuildPhaseComplete {
+				t.enqueueBuild(newObj)
+			}
+		},
+	})
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return
+	}
+
+	klog.Infof("Starting build output tagger")
+	go wait.Until(t.worker, time.Second, stopCh)
+	<-stopCh
+	klog.Infof("Shutting down build output tagger")
+}
+
+func (t *OutputTagger) enqueueBuild(obj interface{}) {
+	build, ok := obj.(*buildv1.Build)
+	if !ok || !needsOutputTags(build) {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(build)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	t.queue.Add(key)
+}
+
+func (t *OutputTagger) worker() {
+	for t.processNextItem() {
+	}
+}
+
+func (t *OutputTagger) processNextItem() bool {
+	key, quit := t.queue.Get()
+	if quit {
+		return false
+	}
+	defer t.queue.Done(key)
+
+	err := t.tag(context.TODO(), key)
+	if err == nil {
+		t.queue.Forget(key)
+		return true
+	}
+	if t.queue.NumRequeues(key) < maxTagRetries {
+		klog.V(4).Infof("retrying tagging the output of build %s: %v", key, err)
+		t.queue.AddRateLimited(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("unable to tag the output of build %s: %v", key, err))
+	t.queue.Forget(key)
+	return true
+}
+
+// tag writes the additional output tags of the build identified by key to its
+// output image stream and records them as applied on the build.
+func (t *OutputTagger) tag(ctx context.Context, key string) error {
+	obj, exists, err := t.store.GetByKey(key)
+	if err != nil || !exists {
+		return err
+	}
+	build := obj.(*buildv1.Build)
+	if !needsOutputTags(build) {
+		return nil
+	}
+
+	to := build.Spec.Output.To
+	streamName, _, err := imageutil.ParseImageStreamTagName(to.Name)
+	if err != nil {
+		return markOutputTagsApplied(ctx, t.BuildClient, build, nil)
+	}
+	tags := expandOutputTags(build)
+	if len(to.Namespace) > 0 && to.Namespace != build.Namespace {
+		klog.V(2).Infof("Not tagging the output of build %s/%s, image stream %s/%s is in another namespace", build.Namespace, build.Name, to.Namespace, streamName)
+		tags = nil
+	}
+	if len(tags) > 0 {
+		from := &corev1.ObjectReference{
+			Kind:      "ImageStreamImage",
+			Namespace: build.Namespace,
+			Name:      imageutil.JoinImageStreamImage(streamName, build.Status.Output.To.ImageDigest),
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			stream, err := t.ImageStreams.ImageStreams(build.Namespace).Get(ctx, streamName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			for _, tag := range tags {
+				setSpecTag(stream, tag, from)
+			}
+			_, err = t.ImageStreams.ImageStreams(build.Namespace).Update(ctx, stream, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		klog.V(4).Infof("Tagged the output of build %s/%s as %s", build.Namespace, build.Name, strings.Join(tags, ", "))
+	}
+	return markOutputTagsApplied(ctx, t.BuildClient, build, tags)
+}
+
+// needsOutputTags returns true if build completed with an image stream output and
+// its additional output tags were not applied yet.
+func needsOutputTags(build *buildv1.Build) bool {
+	if len(build.Spec.Output.AdditionalTags) == 0 {
+		return false
+	}
+	if _, ok := build.Annotations[internal.BuildOutputTagsAppliedAnnotation]; ok {
+		return false
+	}
+	return build.Status.Phase == buildv1.BuildPhaseComplete &&
+		build.Spec.Output.To != nil && build.Spec.Output.To.Kind == "ImageStreamTag" &&
+		build.Status.Output.To != nil && len(build.Status.Output.To.ImageDigest) > 0
+}
+
+// expandOutputTags returns the additional output tags of build with their
+// placeholders expanded. Tags whose placeholders have no value are skipped.
+func expandOutputTags(build *buildv1.Build) []string {
+	values := map[string]string{}
+	if revision := build.Spec.Revision; revision != nil && revision.Git != nil {
+		values["commit"] = revision.Git.Commit
+		values["shortCommit"] = revision.Git.Commit
+		if len(revision.Git.Commit) > 7 {
+			values["shortCommit"] = revision.Git.Commit[:7]
+		}
+	}
+	if git := build.Spec.Source.Git; git != nil {
+		if tag := strings.TrimPrefix(git.Ref, "refs/tags/"); tag != git.Ref {
+			values["tag"] = tag
+		} else {
+			values["branch"] = sourceBranch(build)
+		}
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, template := range build.Spec.Output.AdditionalTags {
+		tag, ok := internal.ExpandBuildOutputTag(template, values)
+		if !ok {
+			klog.V(4).Infof("Skipping output tag %q of build %s/%s, its placeholders have no value", template, build.Namespace, build.Name)
+			continue
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// sourceBranch returns the branch of the git source build built, or an empty
+// string if it is not known. A ref naming a commit is not a branch. Without a
+// ref the default branch of the repository is built, which is only known for
+// builds triggered by a webhook push event: webhooks trigger builds of sources
+// without a ref for pushes to webhook.DefaultConfigRef only.
+func sourceBranch(build *buildv1.Build) string {
+	ref := build.Spec.Source.Git.Ref
+	switch {
+	case strings.HasPrefix(ref, "refs/heads/"):
+		return strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/"), commitRegexp.MatchString(ref):
+		return ""
+	case len(ref) > 0:
+		return ref
+	}
+	for _, cause := range build.Spec.TriggeredBy {
+		if revision := webHookRevision(cause); revision != nil && revision.Git != nil {
+			return webhook.DefaultConfigRef
+		}
+	}
+	return ""
+}
+
+// webHookRevision returns the revision of the push event of the webhook cause,
+// or nil if cause is not a webhook cause or the event had no revision.
+func webHookRevision(cause buildv1.BuildTriggerCause) *buildv1.SourceRevision {
+	switch {
+	case cause.GenericWebHook != nil:
+		return cause.GenericWebHook.Revision
+	case cause.GitHubWebHook != nil:
+		return cause.GitHubWebHook.Revision
+	case cause.GitLabWebHook != nil:
+		return cause.GitLabWebHook.Revision
+	case cause.BitbucketWebHook != nil:
+		return cause.BitbucketWebHook.Revision
+	case cause.GiteaWebHook != nil:
+		return cause.GiteaWebHook.Revision
+	case cause.AzureDevOpsWebHook != nil:
+		return cause.AzureDevOpsWebHook.Revision
+	}
+	return nil
+}
+
+// setSpecTag points the spec tag of stream named tag to from.
+func setSpecTag(stream *imagev1.ImageStream, tag string, from *corev1.ObjectReference) {
+	for i := range stream.Spec.Tags {
+		if stream.Spec.Tags[i].Name == tag {
+			stream.Spec.Tags[i].From = from.DeepCopy()
+			stream.Spec.Tags[i].Generation = nil
+			return
+		}
+	}
+	stream.Spec.Tags = append(stream.Spec.Tags, imagev1.TagReference{
+		Name:            tag,
+		From:            from.DeepCopy(),
+		ReferencePolicy: imagev1.TagReferencePolicy{Type: imagev1.SourceTagReferencePolicy},
+	})
+}
+
+// markOutputTagsApplied records the applied output tags on build, so they are not
+// applied again. Only the annotation is patched, the build is not updated.
+func markOutputTagsApplied(ctx context.Context, buildClient buildv1clienttyped.BuildsGetter, build *buildv1.Build, tags []string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{internal.BuildOutputTagsAppliedAnnotation: strings.Join(tags, ",")},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = buildClient.Builds(build.Namespace).Patch(ctx, build.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return ignoreNotFound(err)
+}
@@ -0,0 +1,128 @@
+package buildgenerator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	buildfake "github.com/openshift/client-go/build/clientset/versioned/fake"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+)
+
+func TestOutputTagger(t *testing.T) {
+	build := &buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "app-1",
+		},
+		Spec: buildv1.BuildSpec{CommonSpec: buildv1.CommonSpec{
+			Source:   buildv1.BuildSource{Git: &buildv1.GitBuildSource{URI: "https://github.com/openshift/ruby-hello-world", Ref: "refs/heads/feature/login"}},
+			Revision: &buildv1.SourceRevision{Git: &buildv1.GitSourceRevision{Commit: "0123456789abcdef"}},
+			Output: buildv1.BuildOutput{
+				To:             &corev1.ObjectReference{Kind: "ImageStreamTag", Name: "app:build"},
+				AdditionalTags: []string{"latest", "${shortCommit}", "${branch}", "${tag}"},
+			},
+		}},
+		Status: buildv1.BuildStatus{
+			Phase:  buildv1.BuildPhaseComplete,
+			Output: buildv1.BuildStatusOutput{To: &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:4567"}},
+		},
+	}
+	stream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec: imagev1.ImageStreamSpec{Tags: []imagev1.TagReference{
+			{Name: "latest", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "app@sha256:0000"}},
+			{Name: "stable", From: &corev1.ObjectReference{Kind: "ImageStreamImage", Name: "app@sha256:0000"}},
+		}},
+	}
+	buildClient := buildfake.NewSimpleClientset(build)
+	imageClient := imagefake.NewSimpleClientset(stream)
+	tagger := NewOutputTagger(buildClient.BuildV1(), imageClient.ImageV1())
+	tagger.store = cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if err := tagger.store.Add(build); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tagger.tag(context.Background(), "default/app-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := imageClient.ImageV1().ImageStreams("default").Get(context.Background(), "app", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"latest":        "app@sha256:4567",
+		"stable":        "app@sha256:0000",
+		"0123456":       "app@sha256:4567",
+		"feature-login": "app@sha256:4567",
+	}
+	if len(updated.Spec.Tags) != len(expected) {
+		t.Errorf("unexpected tags %#v", updated.Spec.Tags)
+	}
+	for _, tag := range updated.Spec.Tags {
+		if tag.From == nil || tag.From.Name != expected[tag.Name] {
+			t.Errorf("unexpected tag %s: %#v", tag.Name, tag.From)
+		}
+	}
+	updates := 0
+	for _, action := range imageClient.Actions() {
+		if action.GetVerb() == "update" {
+			updates++
+		}
+	}
+	if updates != 1 {
+		t.Errorf("expected a single image stream update, got %d", updates)
+	}
+
+	for _, action := range buildClient.Actions() {
+		if action.GetVerb() == "update" {
+			t.Errorf("expected the build not to be updated")
+		}
+	}
+	tagged, err := buildClient.BuildV1().Builds("default").Get(context.Background(), "app-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied := tagged.Annotations["build.openshift.io/output-tags-applied"]; applied != "latest,0123456,feature-login" {
+		t.Errorf("unexpected applied tags %q", applied)
+	}
+	if needsOutputTags(tagged) {
+		t.Errorf("expected the tags of the build to be applied")
+	}
+}
+
+func TestSourceBranch(t *testing.T) {
+	pushed := buildv1.BuildTriggerCause{GitHubWebHook: &buildv1.GitHubWebHookCause{
+		Revision: &buildv1.SourceRevision{Git: &buildv1.GitSourceRevision{Commit: "0123456789abcdef"}},
+	}}
+	tests := []struct {
+		name        string
+		ref         string
+		triggeredBy []buildv1.BuildTriggerCause
+		expected    string
+	}{
+		{name: "branch ref", ref: "refs/heads/feature/login", expected: "feature/login"},
+		{name: "branch name", ref: "develop", expected: "develop"},
+		{name: "commit", ref: "0123456789abcdef0123456789abcdef01234567"},
+		{name: "other ref", ref: "refs/pull/1/head"},
+		{name: "no ref"},
+		{name: "no ref, manually triggered", triggeredBy: []buildv1.BuildTriggerCause{{Message: "Manually triggered"}}},
+		{name: "no ref, pushed", triggeredBy: []buildv1.BuildTriggerCause{pushed}, expected: "master"},
+		{name: "branch ref, pushed", ref: "main", triggeredBy: []buildv1.BuildTriggerCause{pushed}, expected: "main"},
+	}
+	for _, test := range tests {
+		build := &buildv1.Build{Spec: buildv1.BuildSpec{
+			CommonSpec:  buildv1.CommonSpec{Source: buildv1.BuildSource{Git: &buildv1.GitBuildSource{Ref: test.ref}}},
+			TriggeredBy: test.triggeredBy,
+		}}
+		if branch := sourceBranch(build); branch != test.expected {
+			t.Errorf("%s: expected branch %q, got %q", test.name, test.expected, branch)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package buildgenerator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/image/imageutil"
+
+	internal "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+var tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+// applyBuildParameters overrides the fields of build declared as parameters by bc
+// with the values requested for them. Values for undeclared parameters, values not
+// matching the pattern of their parameter and parameters not applicable to build
+// are rejected.
+func applyBuildParameters(bc *buildv1.BuildConfig, build *buildv1.Build, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	declared := map[string]*buildv1.BuildParameter{}
+	for i := range bc.Spec.Parameters {
+		declared[bc.Spec.Parameters[i].Name] = &bc.Spec.Parameters[i]
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := values[name]
+		parameter, ok := declared[name]
+		if !ok {
+			return errors.NewBadRequest(fmt.Sprintf("BuildConfig %s/%s has no parameter %q", bc.Namespace, bc.Name, name))
+		}
+		pattern, err := internal.CompileBuildParameterPattern(parameter.Pattern)
+		if err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("BuildConfig %s/%s has an invalid pattern for parameter %q: %v", bc.Namespace, bc.Name, name, err))
+		}
+		if pattern != nil && !pattern.MatchString(value) {
+			return errors.NewBadRequest(fmt.Sprintf("value %q of parameter %q does not match %q", value, name, parameter.Pattern))
+		}
+		if err := applyBuildParameter(build, parameter.Field, value); err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("unable to apply parameter %q: %v", name, err))
+		}
+	}
+	return nil
+}
+
+func applyBuildParameter(build *buildv1.Build, field buildv1.BuildParameterField, value string) error {
+	switch field {
+	case buildv1.BuildParameterFieldOutputTag:
+		if !tagRegexp.MatchString(value) {
+			return fmt.Errorf("%q is not a valid tag", value)
+		}
+		to := build.Spec.Output.To
+		if to == nil {
+			return fmt.Errorf("the build has no output image")
+		}
+		switch to.Kind {
+		case "ImageStreamTag":
+			name, _, err := imageutil.ParseImageStreamTagName(to.Name)
+			if err != nil {
+				return err
+			}
+			to.Name = imageutil.JoinImageStreamTag(name, value)
+		case "DockerImage":
+			ref, err := imageutil.ParseDockerImageReference(to.Name)
+			if err != nil {
+				return err
+			}
+			ref.Tag, ref.ID = value, ""
+			to.Name = dockerImageReferenceExact(ref)
+		default:
+			return fmt.Errorf("the output image kind %q has no tag", to.Kind)
+		}
+	case buildv1.BuildParameterFieldContextDir:
+		build.Spec.Source.ContextDir = value
+	case buildv1.BuildParameterFieldDockerfilePath:
+		if build.Spec.Strategy.DockerStrategy == nil {
+			return fmt.Errorf("the build does not use the Docker strategy")
+		}
+		build.Spec.Strategy.DockerStrategy.DockerfilePath = value
+	case buildv1.BuildParameterFieldGitRef:
+		if build.Spec.Source.Git == nil {
+			return fmt.Errorf("the build has no git source")
+		}
+		build.Spec.Source.Git.Ref = value
+	default:
+		return fmt.Errorf("unsupported field %q", field)
+	}
+	return nil
+}
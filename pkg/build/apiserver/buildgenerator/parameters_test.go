@@ -0,0 +1,69 @@
+package buildgenerator
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+func TestApplyBuildParameters(t *testing.T) {
+	bc := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "bc",
+		},
+		Spec: buildv1.BuildConfigSpec{
+			Parameters: []buildv1.BuildParameter{
+				{Name: "tag", Field: buildv1.BuildParameterFieldOutputTag, Pattern: "v[0-9.]+"},
+				{Name: "dir", Field: buildv1.BuildParameterFieldContextDir},
+				{Name: "dockerfile", Field: buildv1.BuildParameterFieldDockerfilePath},
+				{Name: "ref", Field: buildv1.BuildParameterFieldGitRef, Pattern: "release-.*"},
+			},
+		},
+	}
+	newBuild := func(outputKind, outputName string) *buildv1.Build {
+		return &buildv1.Build{Spec: buildv1.BuildSpec{CommonSpec: buildv1.CommonSpec{
+			Source:   buildv1.BuildSource{Git: &buildv1.GitBuildSource{URI: "https://github.com/openshift/ruby-hello-world"}},
+			Strategy: buildv1.BuildStrategy{DockerStrategy: &buildv1.DockerBuildStrategy{}},
+			Output:   buildv1.BuildOutput{To: &corev1.ObjectReference{Kind: outputKind, Name: outputName}},
+		}}}
+	}
+
+	build := newBuild("ImageStreamTag", "app:latest")
+	values := map[string]string{"tag": "v1.2", "dir": "services/api", "dockerfile": "Dockerfile.prod", "ref": "release-4"}
+	if err := applyBuildParameters(bc, build, values); err != nil {
+		t.Fatal(err)
+	}
+	if build.Spec.Output.To.Name != "app:v1.2" || build.Spec.Source.ContextDir != "services/api" ||
+		build.Spec.Strategy.DockerStrategy.DockerfilePath != "Dockerfile.prod" || build.Spec.Source.Git.Ref != "release-4" {
+		t.Errorf("parameters were not applied: %#v", build.Spec.CommonSpec)
+	}
+
+	build = newBuild("DockerImage", "quay.io/team/app@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+	if err := applyBuildParameters(bc, build, map[string]string{"tag": "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if build.Spec.Output.To.Name != "quay.io/team/app:v2" {
+		t.Errorf("unexpected output %q", build.Spec.Output.To.Name)
+	}
+
+	for name, values := range map[string]map[string]string{
+		"undeclared parameter": {"strategy": "Source"},
+		"pattern mismatch":     {"tag": "latest"},
+		"invalid tag":          {"tag": "v1..2/x"},
+	} {
+		if err := applyBuildParameters(bc, newBuild("ImageStreamTag", "app:latest"), values); !errors.IsBadRequest(err) {
+			t.Errorf("%s: expected a bad request, got %v", name, err)
+		}
+	}
+
+	build = newBuild("ImageStreamTag", "app:latest")
+	build.Spec.Strategy.DockerStrategy = nil
+	if err := applyBuildParameters(bc, build, map[string]string{"dockerfile": "Dockerfile"}); !errors.IsBadRequest(err) {
+		t.Errorf("expected a bad request for a build without Docker strategy, got %v", err)
+	}
+}
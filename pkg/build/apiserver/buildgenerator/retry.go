@@ -0,0 +1,207 @@
+package buildgenerator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kvalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/build/naming"
+
+	internal "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	conversions "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
+)
+
+const maxRetryRetries = 5
+
+// BuildRetrier clones builds of BuildConfigs with a retry policy which failed for
+// one of the retryable reasons of the policy. Retry builds are linked to the build
+// they retry by their BuildRetry trigger cause, and are named after it, so that a
+// build is retried at most once even if the retry is attempted again.
+type BuildRetrier struct {
+	Generator *BuildGenerator
+
+	queue workqueue.TypedRateLimitingInterface[string]
+	store cache.Store
+}
+
+// NewBuildRetrier creates a BuildRetrier cloning builds with generator.
+func NewBuildRetrier(generator *BuildGenerator) *BuildRetrier {
+	return &BuildRetrier{Generator: generator}
+}
+
+// Run retries the failed builds observed by informer until stopCh is closed.
+func (r *BuildRetrier) Run(informer cache.SharedIndexInformer, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	r.queue = workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "buildretrier"},
+	)
+	defer r.queue.ShutDown()
+	r.store = informer.GetStore()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: r.enqueueBuild,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldObj.(*buildv1.Build).Status.Phase != newObj.(*buildv1.Build).Status.Phase {
+				r.enqueueBuild(newObj)
+			}
+		},
+	})
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return
+	}
+
+	klog.Infof("Starting build retrier")
+	go wait.Until(r.worker, time.Second, stopCh)
+	<-stopCh
+	klog.Infof("Shutting down build retrier")
+}
+
+func (r *BuildRetrier) enqueueBuild(obj interface{}) {
+	build, ok := obj.(*buildv1.Build)
+	if !ok || !retryCandidate(build) {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(build)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	r.queue.Add(key)
+}
+
+func (r *BuildRetrier) worker() {
+	for r.processNextItem() {
+	}
+}
+
+func (r *BuildRetrier) processNextItem() bool {
+	key, quit := r.queue.Get()
+	if quit {
+		return false
+	}
+	defer r.queue.Done(key)
+
+	err := r.retry(context.TODO(), key)
+	if err == nil {
+		r.queue.Forget(key)
+		return true
+	}
+	if r.queue.NumRequeues(key) < maxRetryRetries {
+		klog.V(4).Infof("retrying the retry of build %s: %v", key, err)
+		r.queue.AddRateLimited(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("unable to retry build %s: %v", key, err))
+	r.queue.Forget(key)
+	return true
+}
+
+// retry clones the build identified by key if its retry policy allows another
+// attempt and its backoff passed. If the backoff did not pass yet, the build is
+// requeued for when it does.
+func (r *BuildRetrier) retry(ctx context.Context, key string) error {
+	obj, exists, err := r.store.GetByKey(key)
+	if err != nil || !exists {
+		return err
+	}
+	build := obj.(*buildv1.Build)
+	if !retryCandidate(build) {
+		return nil
+	}
+	name := retryName(build)
+	if _, exists, err := r.store.GetByKey(build.Namespace + "/" + name); err != nil || exists {
+		return err
+	}
+
+	ctx = apirequest.WithNamespace(ctx, build.Namespace)
+	// the retry may have been created after the cache was last updated
+	if _, err := r.Generator.Client.GetBuild(ctx, name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		return err
+	}
+	bc, err := r.Generator.Client.GetBuildConfig(ctx, build.Status.Config.Name, metav1.GetOptions{})
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	if isPaused(bc) {
+		return nil
+	}
+	if bc.Spec.RetryPolicy == nil {
+		return nil
+	}
+	internalPolicy := &internal.BuildRetryPolicy{}
+	if err := conversions.Convert_v1_BuildRetryPolicy_To_build_BuildRetryPolicy(bc.Spec.RetryPolicy, internalPolicy, nil); err != nil {
+		return err
+	}
+	policy := internal.DefaultedBuildRetryPolicy(internalPolicy)
+	attempt := retryAttempt(build)
+	if !policy.Retryable(internal.StatusReason(build.Status.Reason)) || attempt >= policy.MaxAttempts {
+		return nil
+	}
+	if delay := time.Until(build.Status.CompletionTimestamp.Add(policy.Backoff(attempt))); delay > 0 {
+		r.queue.AddAfter(key, delay)
+		return nil
+	}
+
+	created, err := r.Generator.cloneWithOptions(ctx, &buildv1.BuildRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: build.Name},
+		TriggeredBy: []buildv1.BuildTriggerCause{{
+			Message: fmt.Sprintf("Retry %d of %d of build %s, which failed with reason %s", attempt, policy.MaxAttempts-1, build.Name, build.Status.Reason),
+			BuildRetry: &buildv1.BuildRetryCause{
+				BuildName: build.Name,
+				Attempt:   attempt + 1,
+				Reason:    build.Status.Reason,
+			},
+		}},
+	}, cloneOptions{name: name})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+	klog.V(4).Infof("Build %s/%s is retried by build %s", build.Namespace, build.Name, created.Name)
+	return nil
+}
+
+// retryName returns the name of the build retrying build.
+func retryName(build *buildv1.Build) string {
+	return naming.GetName(build.Name, "retry", kvalidation.DNS1123SubdomainMaxLength)
+}
+
+// retryCandidate returns true if build failed and belongs to a BuildConfig.
+func retryCandidate(build *buildv1.Build) bool {
+	switch build.Status.Phase {
+	case buildv1.BuildPhaseFailed, buildv1.BuildPhaseError:
+		return build.Status.Config != nil && build.Status.CompletionTimestamp != nil
+	}
+	return false
+}
+
+// retryAttempt returns the attempt number of build, 1 unless it is a retry.
+func retryAttempt(build *buildv1.Build) int32 {
+	for _, cause := range build.Spec.TriggeredBy {
+		if cause.BuildRetry != nil && cause.BuildRetry.Attempt > 1 {
+			return cause.BuildRetry.Attempt
+		}
+	}
+	return 1
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+package buildgenerator
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+func retryTestBuild(name string, attempt int, reason buildv1.StatusReason, completed time.Time) *buildv1.Build {
+	build := cancelTestBuild(name, attempt, buildv1.BuildPhaseFailed, completed)
+	if attempt > 1 {
+		build.Spec.TriggeredBy = []buildv1.BuildTriggerCause{{
+			BuildRetry: &buildv1.BuildRetryCause{BuildName: "bc-" + strconv.Itoa(attempt-1), Attempt: int32(attempt)},
+		}}
+	}
+	completionTimestamp := metav1.NewTime(completed)
+	build.Status.Reason = reason
+	build.Status.CompletionTimestamp = &completionTimestamp
+	build.Status.Config = &corev1.ObjectReference{Name: "bc"}
+	return build
+}
+
+func TestBuildRetrier(t *testing.T) {
+	now := time.Now()
+	initialBackoff := int64(60)
+	bc := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "bc",
+		},
+		Spec: buildv1.BuildConfigSpec{
+			RetryPolicy: &buildv1.BuildRetryPolicy{MaxAttempts: 3, InitialBackoffSeconds: &initialBackoff},
+		},
+	}
+	testCases := map[string]struct {
+		build         *buildv1.Build
+		expectRetry   bool
+		expectAttempt int32
+	}{
+		"retryable failure": {
+			build:         retryTestBuild("bc-1", 1, buildv1.StatusReasonPushImageToRegistryFailed, now.Add(-2*time.Minute)),
+			expectRetry:   true,
+			expectAttempt: 2,
+		},
+		"second retry": {
+			build:         retryTestBuild("bc-2", 2, buildv1.StatusReasonBuildPodEvicted, now.Add(-3*time.Minute)),
+			expectRetry:   true,
+			expectAttempt: 3,
+		},
+		"within backoff": {
+			build: retryTestBuild("bc-1", 1, buildv1.StatusReasonFetchSourceFailed, now),
+		},
+		"reason not retryable": {
+			build: retryTestBuild("bc-1", 1, buildv1.StatusReasonDockerBuildFailed, now.Add(-time.Hour)),
+		},
+		"out of attempts": {
+			build: retryTestBuild("bc-3", 3, buildv1.StatusReasonPushImageToRegistryFailed, now.Add(-time.Hour)),
+		},
+	}
+	for name, tc := range testCases {
+		bc := bc.DeepCopy()
+		bc.Status.LastVersion = 3
+		builds := map[string]*buildv1.Build{tc.build.Name: tc.build}
+		generator := &BuildGenerator{Client: TestingClient{
+			GetBuildConfigFunc: func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.BuildConfig, error) {
+				return bc, nil
+			},
+			UpdateBuildConfigFunc: func(ctx context.Context, buildConfig *buildv1.BuildConfig, options metav1.UpdateOptions) error {
+				return nil
+			},
+			GetBuildFunc: func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.Build, error) {
+				build, ok := builds[name]
+				if !ok {
+					return nil, errors.NewNotFound(buildv1.Resource("builds"), name)
+				}
+				return build.DeepCopy(), nil
+			},
+			CreateBuildFunc: func(ctx context.Context, build *buildv1.Build, options metav1.CreateOptions) error {
+				builds[build.Name] = build.DeepCopy()
+				return nil
+			},
+		}}
+		retrier := NewBuildRetrier(generator)
+		retrier.queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+		retrier.store = cache.NewStore(cache.MetaNamespaceKeyFunc)
+		if err := retrier.store.Add(tc.build); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := retrier.retry(context.Background(), "default/"+tc.build.Name); err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+			continue
+		}
+		var retry *buildv1.Build
+		for _, build := range builds {
+			if build.Name != tc.build.Name {
+				retry = build
+			}
+		}
+		if (retry != nil) != tc.expectRetry {
+			t.Errorf("%s: expected retry %v, got %v", name, tc.expectRetry, retry)
+			continue
+		}
+		if retry == nil {
+			continue
+		}
+		if retry.Name != tc.build.Name+"-retry" {
+			t.Errorf("%s: expected the retry to be named after the build, got %s", name, retry.Name)
+		}
+		expectedCause := buildv1.BuildRetryCause{BuildName: tc.build.Name, Attempt: tc.expectAttempt, Reason: tc.build.Status.Reason}
+		if len(retry.Spec.TriggeredBy) != 1 || retry.Spec.TriggeredBy[0].BuildRetry == nil || *retry.Spec.TriggeredBy[0].BuildRetry != expectedCause {
+			t.Errorf("%s: expected a retry trigger cause %v, got %v", name, expectedCause, retry.Spec.TriggeredBy)
+		}
+
+		// the build is not retried again, even before the retry is observed
+		if err := retrier.retry(context.Background(), "default/"+tc.build.Name); err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if len(builds) != 2 {
+			t.Errorf("%s: expected a single retry, got %d builds", name, len(builds))
+		}
+	}
+}
@@ -13,6 +13,7 @@ type TestingClient struct {
 	GetBuildConfigFunc      func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.BuildConfig, error)
 	UpdateBuildConfigFunc   func(ctx context.Context, buildConfig *buildv1.BuildConfig, options metav1.UpdateOptions) error
 	GetBuildFunc            func(ctx context.Context, name string, options metav1.GetOptions) (*buildv1.Build, error)
+	ListBuildsFunc          func(ctx context.Context, options metav1.ListOptions) (*buildv1.BuildList, error)
 	CreateBuildFunc         func(ctx context.Context, build *buildv1.Build, options metav1.CreateOptions) error
 	UpdateBuildFunc         func(ctx context.Context, build *buildv1.Build, options metav1.UpdateOptions) error
 	GetImageStreamFunc      func(ctx context.Context, name string, options metav1.GetOptions) (*imagev1.ImageStream, error)
@@ -35,6 +36,11 @@ func (c TestingClient) GetBuild(ctx context.Context, name string, options metav1
 	return c.GetBuildFunc(ctx, name, options)
 }
 
+// ListBuilds lists builds
+func (c TestingClient) ListBuilds(ctx context.Context, options metav1.ListOptions) (*buildv1.BuildList, error) {
+	return c.ListBuildsFunc(ctx, options)
+}
+
 // CreateBuild creates a new build
 func (c TestingClient) CreateBuild(ctx context.Context, build *buildv1.Build, options metav1.CreateOptions) error {
 	return c.CreateBuildFunc(ctx, build, options)
@@ -0,0 +1,98 @@
+package apiserver
+
+import (
+	"context"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildtypedclient "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+)
+
+const (
+	// buildControllersLeaseNamespace and buildControllersLeaseName identify the
+	// lease electing the apiserver instance which runs the build controllers.
+	buildControllersLeaseNamespace = "openshift-apiserver"
+	buildControllersLeaseName      = "openshift-apiserver-build-controllers"
+)
+
+// buildController is a controller reacting to changes of builds. It runs until
+// stopCh is closed and may be run again with a new informer afterwards.
+type buildController interface {
+	Run(informer cache.SharedIndexInformer, stopCh <-chan struct{})
+}
+
+// runBuildControllers returns a start function running controllers in the
+// apiserver instance holding the build controllers lease only, so that every
+// change of a build is acted upon once rather than once per instance. The
+// controllers share a single build informer, which is recreated every time the
+// lease is acquired.
+func runBuildControllers(kubeClient kubernetes.Interface, buildClient buildtypedclient.BuildsGetter, controllers ...buildController) func(<-chan struct{}) {
+	return func(stopCh <-chan struct{}) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "openshift-apiserver"
+		}
+		lock := &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: buildControllersLeaseNamespace,
+				Name:      buildControllersLeaseName,
+			},
+			Client: kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: hostname + "_" + string(uuid.NewUUID()),
+			},
+		}
+		ctx := wait.ContextForChannel(stopCh)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+				Lock:            lock,
+				Name:            buildControllersLeaseName,
+				LeaseDuration:   137 * time.Second,
+				RenewDeadline:   107 * time.Second,
+				RetryPeriod:     26 * time.Second,
+				ReleaseOnCancel: true,
+				Callbacks: leaderelection.LeaderCallbacks{
+					OnStartedLeading: func(ctx context.Context) {
+						klog.Infof("Starting build controllers")
+						informer := newBuildInformer(buildClient)
+						for _, controller := range controllers {
+							go controller.Run(informer, ctx.Done())
+						}
+						informer.Run(ctx.Done())
+					},
+					OnStoppedLeading: func() {
+						klog.Infof("Stopped build controllers")
+					},
+				},
+			})
+		}, time.Second)
+	}
+}
+
+func newBuildInformer(buildClient buildtypedclient.BuildsGetter) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return buildClient.Builds(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return buildClient.Builds(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		},
+		&buildv1.Build{},
+		0,
+		cache.Indexers{},
+	)
+}
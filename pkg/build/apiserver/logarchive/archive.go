@@ -0,0 +1,67 @@
+// Package logarchive stores the logs of completed builds so that they remain
+// available after the build pod is gone.
+package logarchive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+)
+
+// ErrNotFound is returned by Archive.Get when no log is archived under a key.
+var ErrNotFound = errors.New("build log not found in the archive")
+
+// Archive is a store for build logs. Keys are slash separated paths.
+type Archive interface {
+	// Put stores the content read from r under key, replacing any existing content.
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Get returns the content stored under key, or ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Prune removes the content stored before cutoff.
+	Prune(ctx context.Context, cutoff time.Time) error
+}
+
+const defaultS3Region = "us-east-1"
+
+// FromConfig returns the archive configured by config, or nil if build log
+// archiving is not enabled.
+func FromConfig(config openshiftcontrolplanev1.BuildLogArchiveConfig) (Archive, error) {
+	switch {
+	case len(config.Directory) > 0 && config.S3 != nil:
+		return nil, fmt.Errorf("only one of buildLogArchiveConfig.directory and buildLogArchiveConfig.s3 may be set")
+	case len(config.Directory) > 0:
+		return NewDirectory(config.Directory)
+	case config.S3 != nil:
+		region := config.S3.Region
+		if len(region) == 0 {
+			region = defaultS3Region
+		}
+		accessKeyID, err := readCredential(config.S3.AccessKeyIDFile)
+		if err != nil {
+			return nil, err
+		}
+		secretAccessKey, err := readCredential(config.S3.SecretAccessKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3(config.S3.Endpoint, config.S3.Bucket, region, accessKeyID, secretAccessKey)
+	}
+	return nil, nil
+}
+
+func readCredential(file string) (string, error) {
+	if len(file) == 0 {
+		return "", nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the build log archive credentials: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
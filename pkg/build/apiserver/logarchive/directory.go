@@ -0,0 +1,95 @@
+package logarchive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// directory stores build logs as files below a local directory.
+type directory struct {
+	root string
+}
+
+// NewDirectory returns an Archive storing logs below root, which is created if
+// it does not exist.
+func NewDirectory(root string) (Archive, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create the build log archive directory: %v", err)
+	}
+	return &directory{root: root}, nil
+}
+
+func (d *directory) file(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", fmt.Errorf("invalid build log archive key %q", key)
+	}
+	return filepath.Join(d.root, filepath.FromSlash(strings.TrimPrefix(clean, "/"))), nil
+}
+
+func (d *directory) Put(ctx context.Context, key string, r io.Reader) error {
+	file, err := d.file(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	// write to a temporary file first so readers never see a partial log
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (d *directory) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := d.file(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (d *directory) Prune(ctx context.Context, cutoff time.Time) error {
+	return filepath.Walk(d.root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if info.IsDir() || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,339 @@
+package logarchive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// s3 stores build logs as objects in a bucket of an S3 compatible object store.
+// Requests use path style addressing and are signed with AWS Signature Version 4,
+// which is what AWS S3 as well as MinIO and other compatible stores accept.
+type s3 struct {
+	endpoint        *url.URL
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 returns an Archive storing logs in bucket at the S3 compatible endpoint.
+// Requests are sent anonymously if no access key is given.
+func NewS3(endpoint, bucket, region, accessKeyID, secretAccessKey string) (Archive, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid build log archive endpoint %q: %v", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid build log archive endpoint %q: an http or https URL is required", endpoint)
+	}
+	if len(bucket) == 0 {
+		return nil, fmt.Errorf("a bucket is required for the build log archive")
+	}
+	if (len(accessKeyID) == 0) != (len(secretAccessKey) == 0) {
+		return nil, fmt.Errorf("both an access key id and a secret access key are required for the build log archive")
+	}
+	return &s3{
+		endpoint:        u,
+		bucket:          bucket,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: 5 * time.Minute},
+		now:             time.Now,
+	}, nil
+}
+
+// s3PartSize is the size of the parts logs are uploaded in. S3 requires the
+// length and the hash of a payload before it is sent, so a log is read one part
+// at a time, and logs larger than a part use a multipart upload. Only a single
+// part is held in memory.
+const s3PartSize = 5 << 20
+
+func (s *s3) Put(ctx context.Context, key string, r io.Reader) error {
+	part, err := readPart(r)
+	if err != nil {
+		return err
+	}
+	if len(part) < s3PartSize {
+		return s.expect(s.do(ctx, http.MethodPut, key, nil, part))
+	}
+
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return err
+	}
+	initiated := struct {
+		UploadID string `xml:"UploadId"`
+	}{}
+	err = decodeResponse(resp, &initiated)
+	if err != nil {
+		return err
+	}
+	upload := url.Values{"uploadId": {initiated.UploadID}}
+	if err := s.putParts(ctx, key, upload, part, r); err != nil {
+		if abortErr := s.expect(s.do(ctx, http.MethodDelete, key, upload, nil)); abortErr != nil {
+			klog.V(4).Infof("unable to abort the upload of build log %s: %v", key, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+// putParts uploads part and the remainder of r as the parts of a multipart upload
+// and completes it.
+func (s *s3) putParts(ctx context.Context, key string, upload url.Values, part []byte, r io.Reader) error {
+	completed := struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{}
+	for number := 1; len(part) > 0; number++ {
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": upload["uploadId"]}
+		resp, err := s.do(ctx, http.MethodPut, key, query, part)
+		if err != nil {
+			return err
+		}
+		etag := resp.Header.Get("ETag")
+		if err := s.expect(resp, nil); err != nil {
+			return err
+		}
+		completed.Parts = append(completed.Parts, completedPart{PartNumber: number, ETag: etag})
+		if len(part) < s3PartSize {
+			break
+		}
+		if part, err = readPart(r); err != nil {
+			return err
+		}
+	}
+	body, err := xml.Marshal(completed)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPost, key, upload, body)
+	if err != nil {
+		return err
+	}
+	// a completion failing after the response started is reported in its body
+	failed := struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}{}
+	if err := decodeResponse(resp, &failed); err != nil {
+		return err
+	}
+	if failed.XMLName.Local == "Error" {
+		return fmt.Errorf("unable to complete the upload of build log %s: %s: %s", key, failed.Code, failed.Message)
+	}
+	return nil
+}
+
+func (s *s3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	defer resp.Body.Close()
+	return nil, responseError(resp)
+}
+
+func (s *s3) Prune(ctx context.Context, cutoff time.Time) error {
+	query := url.Values{"list-type": {"2"}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return err
+		}
+		list := struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}{}
+		if err := decodeResponse(resp, &list); err != nil {
+			return err
+		}
+		for _, object := range list.Contents {
+			if !object.LastModified.Before(cutoff) {
+				continue
+			}
+			resp, err := s.do(ctx, http.MethodDelete, object.Key, nil, nil)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode == http.StatusNotFound {
+				resp.Body.Close()
+				continue
+			}
+			if err := s.expect(resp, nil); err != nil {
+				return err
+			}
+		}
+		if !list.IsTruncated {
+			return nil
+		}
+		query.Set("continuation-token", list.NextContinuationToken)
+	}
+}
+
+// expect closes resp and returns an error unless the request succeeded.
+func (s *s3) expect(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (s *s3) do(ctx context.Context, method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if len(key) > 0 {
+		u.Path += "/" + key
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if method == http.MethodPut && len(query) == 0 {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	s.sign(req, body)
+	return s.client.Do(req)
+}
+
+// readPart reads up to s3PartSize bytes from r.
+func readPart(r io.Reader) ([]byte, error) {
+	part := make([]byte, s3PartSize)
+	n, err := io.ReadFull(r, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return part[:n], err
+}
+
+// decodeResponse decodes the XML body of a successful response into v.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return responseError(resp)
+	}
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// sign adds the AWS Signature Version 4 headers to req.
+func (s *s3) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if len(s.accessKeyID) == 0 {
+		return
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		fmt.Fprintf(canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEscapePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{now.Format("20060102"), s.region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := []byte("AWS4" + s.secretAccessKey)
+	for _, part := range []string{now.Format("20060102"), s.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEscapePath escapes p the way Signature Version 4 expects, keeping only
+// unreserved characters and the path separators.
+func uriEscapePath(p string) string {
+	escaped := &strings.Builder{}
+	for _, c := range []byte(p) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~', c == '/':
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+func responseError(resp *http.Response) error {
+	message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("build log archive request %s %s failed with %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,211 @@
+// Package provenance describes how the image of a completed build was produced as
+// an in-toto statement with a SLSA provenance predicate.
+package provenance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/build/buildutil"
+)
+
+const (
+	// StatementType is the in-toto statement type of provenance statements.
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the SLSA provenance predicate type of provenance statements.
+	PredicateType = "https://slsa.dev/provenance/v1"
+	// BuildType identifies how the external parameters of a build are interpreted.
+	BuildType = "https://build.openshift.io/provenance/v1"
+	// BuilderID identifies the OpenShift build system as the builder.
+	BuilderID = "https://build.openshift.io/builder/v1"
+
+	// Annotation holds the provenance statement of a build, recorded by the
+	// apiserver when the build completes. It holds a DSSE envelope when statements
+	// are signed.
+	Annotation = "build.openshift.io/provenance"
+)
+
+// Statement is an in-toto statement attesting the provenance of its subjects.
+type Statement struct {
+	Type          string      `json:"_type"`
+	Subject       []Subject   `json:"subject"`
+	PredicateType string      `json:"predicateType"`
+	Predicate     *Provenance `json:"predicate"`
+}
+
+// Subject is an artifact identified by its digests.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is the SLSA provenance predicate.
+type Provenance struct {
+	BuildDefinition BuildDefinition `json:"buildDefinition"`
+	RunDetails      RunDetails      `json:"runDetails"`
+}
+
+// BuildDefinition describes the inputs of a build.
+type BuildDefinition struct {
+	BuildType            string               `json:"buildType"`
+	ExternalParameters   ExternalParameters   `json:"externalParameters"`
+	InternalParameters   map[string]string    `json:"internalParameters,omitempty"`
+	ResolvedDependencies []ResourceDescriptor `json:"resolvedDependencies,omitempty"`
+}
+
+// ExternalParameters are the parameters of a build under the control of its user.
+type ExternalParameters struct {
+	BuildConfig string                    `json:"buildConfig,omitempty"`
+	Strategy    buildv1.BuildStrategyType `json:"strategy"`
+	Source      *Source                   `json:"source,omitempty"`
+	TriggeredBy []string                  `json:"triggeredBy,omitempty"`
+}
+
+// Source is the source a build was requested for.
+type Source struct {
+	URI        string `json:"uri,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	ContextDir string `json:"contextDir,omitempty"`
+}
+
+// ResourceDescriptor is an artifact consumed by a build.
+type ResourceDescriptor struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// RunDetails describes the execution of a build.
+type RunDetails struct {
+	Builder  Builder       `json:"builder"`
+	Metadata BuildMetadata `json:"metadata"`
+}
+
+// Builder identifies the system which ran a build.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// BuildMetadata identifies a build and when it ran.
+type BuildMetadata struct {
+	InvocationID string       `json:"invocationId"`
+	StartedOn    *metav1.Time `json:"startedOn,omitempty"`
+	FinishedOn   *metav1.Time `json:"finishedOn,omitempty"`
+}
+
+// NewStatement returns the provenance statement of build. The build must be
+// complete and have pushed its output image.
+func NewStatement(build *buildv1.Build) (*Statement, error) {
+	if build.Status.Phase != buildv1.BuildPhaseComplete {
+		return nil, fmt.Errorf("build %s/%s is not complete", build.Namespace, build.Name)
+	}
+	if build.Status.Output.To == nil || len(build.Status.Output.To.ImageDigest) == 0 || len(build.Status.OutputDockerImageReference) == 0 {
+		return nil, fmt.Errorf("build %s/%s did not push an output image", build.Namespace, build.Name)
+	}
+	algorithm, digest, ok := strings.Cut(build.Status.Output.To.ImageDigest, ":")
+	if !ok {
+		return nil, fmt.Errorf("build %s/%s has an invalid output image digest %q", build.Namespace, build.Name, build.Status.Output.To.ImageDigest)
+	}
+
+	definition := BuildDefinition{
+		BuildType: BuildType,
+		ExternalParameters: ExternalParameters{
+			Strategy: build.Spec.Strategy.Type,
+			Source:   source(build),
+		},
+		InternalParameters: map[string]string{
+			"namespace": build.Namespace,
+			"name":      build.Name,
+		},
+		ResolvedDependencies: dependencies(build),
+	}
+	if build.Status.Config != nil {
+		definition.ExternalParameters.BuildConfig = build.Status.Config.Name
+	}
+	if len(build.Spec.ServiceAccount) > 0 {
+		definition.InternalParameters["serviceAccount"] = build.Spec.ServiceAccount
+	}
+	for _, cause := range build.Spec.TriggeredBy {
+		definition.ExternalParameters.TriggeredBy = append(definition.ExternalParameters.TriggeredBy, cause.Message)
+	}
+
+	return &Statement{
+		Type: StatementType,
+		Subject: []Subject{{
+			Name:   imageName(build.Status.OutputDockerImageReference),
+			Digest: map[string]string{algorithm: digest},
+		}},
+		PredicateType: PredicateType,
+		Predicate: &Provenance{
+			BuildDefinition: definition,
+			RunDetails: RunDetails{
+				Builder: Builder{ID: BuilderID},
+				Metadata: BuildMetadata{
+					InvocationID: string(build.UID),
+					StartedOn:    build.Status.StartTimestamp,
+					FinishedOn:   build.Status.CompletionTimestamp,
+				},
+			},
+		},
+	}, nil
+}
+
+// Record returns the JSON encoded provenance statement of build, in a signed
+// envelope if signer is set, for storing in the Annotation of build.
+func Record(build *buildv1.Build, signer *Signer) (string, error) {
+	statement, err := NewStatement(build)
+	if err != nil {
+		return "", err
+	}
+	var record interface{} = statement
+	if signer != nil {
+		if record, err = signer.Sign(statement); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func source(build *buildv1.Build) *Source {
+	git := build.Spec.Source.Git
+	if git == nil {
+		return nil
+	}
+	return &Source{URI: git.URI, Ref: git.Ref, ContextDir: build.Spec.Source.ContextDir}
+}
+
+// dependencies returns the resolved source revision and the builder image of build.
+func dependencies(build *buildv1.Build) []ResourceDescriptor {
+	var resolved []ResourceDescriptor
+	if git := build.Spec.Source.Git; git != nil {
+		dependency := ResourceDescriptor{URI: "git+" + git.URI}
+		if revision := build.Spec.Revision; revision != nil && revision.Git != nil && len(revision.Git.Commit) > 0 {
+			dependency.Digest = map[string]string{"gitCommit": revision.Git.Commit}
+		}
+		resolved = append(resolved, dependency)
+	}
+	if from := buildutil.GetInputReference(build.Spec.Strategy); from != nil && from.Kind == "DockerImage" {
+		dependency := ResourceDescriptor{URI: from.Name}
+		if _, digest, ok := strings.Cut(from.Name, "@"); ok {
+			if algorithm, hex, ok := strings.Cut(digest, ":"); ok {
+				dependency.URI = imageName(from.Name)
+				dependency.Digest = map[string]string{algorithm: hex}
+			}
+		}
+		resolved = append(resolved, dependency)
+	}
+	return resolved
+}
+
+// imageName returns the image pull spec without its digest.
+func imageName(pullSpec string) string {
+	name, _, _ := strings.Cut(pullSpec, "@")
+	return name
+}
@@ -0,0 +1,170 @@
+package provenance
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+func testBuild() *buildv1.Build {
+	return &buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-1", UID: "uid"},
+		Spec: buildv1.BuildSpec{
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Git: &buildv1.GitBuildSource{URI: "https://github.com/openshift/ruby-hello-world", Ref: "main"},
+				},
+				Revision: &buildv1.SourceRevision{Git: &buildv1.GitSourceRevision{Commit: "abc123"}},
+				Strategy: buildv1.BuildStrategy{
+					Type: buildv1.SourceBuildStrategyType,
+					SourceStrategy: &buildv1.SourceBuildStrategy{
+						From: corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/ruby@sha256:0123"},
+					},
+				},
+			},
+			TriggeredBy: []buildv1.BuildTriggerCause{{Message: "Manually triggered"}},
+		},
+		Status: buildv1.BuildStatus{
+			Phase:                      buildv1.BuildPhaseComplete,
+			Config:                     &corev1.ObjectReference{Name: "app"},
+			OutputDockerImageReference: "image-registry:5000/default/app:latest",
+			Output:                     buildv1.BuildStatusOutput{To: &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:4567"}},
+		},
+	}
+}
+
+func TestNewStatement(t *testing.T) {
+	statement, err := NewStatement(testBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedSubject := []Subject{{Name: "image-registry:5000/default/app:latest", Digest: map[string]string{"sha256": "4567"}}}
+	if !reflect.DeepEqual(statement.Subject, expectedSubject) {
+		t.Errorf("unexpected subject %#v", statement.Subject)
+	}
+	expectedDependencies := []ResourceDescriptor{
+		{URI: "git+https://github.com/openshift/ruby-hello-world", Digest: map[string]string{"gitCommit": "abc123"}},
+		{URI: "quay.io/ruby", Digest: map[string]string{"sha256": "0123"}},
+	}
+	if !reflect.DeepEqual(statement.Predicate.BuildDefinition.ResolvedDependencies, expectedDependencies) {
+		t.Errorf("unexpected dependencies %#v", statement.Predicate.BuildDefinition.ResolvedDependencies)
+	}
+	parameters := statement.Predicate.BuildDefinition.ExternalParameters
+	if parameters.BuildConfig != "app" || parameters.Source.Ref != "main" || !reflect.DeepEqual(parameters.TriggeredBy, []string{"Manually triggered"}) {
+		t.Errorf("unexpected external parameters %#v", parameters)
+	}
+
+	running := testBuild()
+	running.Status.Phase = buildv1.BuildPhaseRunning
+	if _, err := NewStatement(running); err == nil {
+		t.Errorf("expected an error for a running build")
+	}
+	noOutput := testBuild()
+	noOutput.Status.Output.To = nil
+	if _, err := NewStatement(noOutput); err == nil {
+		t.Errorf("expected an error for a build without output")
+	}
+}
+
+func TestSign(t *testing.T) {
+	statement, err := NewStatement(testBuild())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifiers := map[string]struct {
+		key    interface{}
+		verify func(message, sig []byte) bool
+	}{
+		"ecdsa": {key: ecdsaKey, verify: func(message, sig []byte) bool {
+			digest := sha256.Sum256(message)
+			return ecdsa.VerifyASN1(&ecdsaKey.PublicKey, digest[:], sig)
+		}},
+		"ed25519": {key: ed25519Key, verify: func(message, sig []byte) bool {
+			return ed25519.Verify(ed25519Key.Public().(ed25519.PublicKey), message, sig)
+		}},
+	}
+	for name, v := range verifiers {
+		der, err := x509.MarshalPKCS8PrivateKey(v.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		envelope, err := signer.Sign(statement)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(envelope.Signatures) != 1 || !v.verify(pae(envelope.PayloadType, envelope.Payload), envelope.Signatures[0].Sig) {
+			t.Errorf("%s: invalid signature", name)
+		}
+		signed := &Statement{}
+		if err := json.Unmarshal(envelope.Payload, signed); err != nil || signed.Subject[0].Digest["sha256"] != "4567" {
+			t.Errorf("%s: unexpected payload %s: %v", name, envelope.Payload, err)
+		}
+	}
+
+	if _, err := NewSigner([]byte("not a key")); err == nil {
+		t.Errorf("expected an error for an invalid key")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	record, err := Record(testBuild(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement := &Statement{}
+	if err := json.Unmarshal([]byte(record), statement); err != nil || statement.Subject[0].Digest["sha256"] != "4567" {
+		t.Errorf("unexpected record %s: %v", record, err)
+	}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewSigner(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err = Record(testBuild(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := &Envelope{}
+	if err := json.Unmarshal([]byte(record), envelope); err != nil || envelope.PayloadType != PayloadType || len(envelope.Signatures) != 1 {
+		t.Errorf("unexpected signed record %s: %v", record, err)
+	}
+
+	running := testBuild()
+	running.Status.Phase = buildv1.BuildPhaseRunning
+	if _, err := Record(running, nil); err == nil {
+		t.Errorf("expected an error for a running build")
+	}
+}
@@ -0,0 +1,110 @@
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+)
+
+// PayloadType is the DSSE payload type of signed statements.
+const PayloadType = "application/vnd.in-toto+json"
+
+// Envelope is a DSSE envelope holding a signed statement.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     []byte      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is the signature of an Envelope payload.
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// Signer signs statements with a cluster held key.
+type Signer struct {
+	key   crypto.Signer
+	keyID string
+}
+
+// SignerFromConfig returns the signer configured by config, or nil if provenance
+// statements are not signed.
+func SignerFromConfig(config openshiftcontrolplanev1.BuildProvenanceConfig) (*Signer, error) {
+	if len(config.SigningKeyFile) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(config.SigningKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the build provenance signing key: %v", err)
+	}
+	return NewSigner(data)
+}
+
+// NewSigner returns a signer for a PEM encoded PKCS#8 private key.
+func NewSigner(keyPEM []byte) (*Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	switch signer.(type) {
+	case *ecdsa.PrivateKey, ed25519.PrivateKey, *rsa.PrivateKey:
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	keyID := sha256.Sum256(publicKey)
+	return &Signer{key: signer, keyID: hex.EncodeToString(keyID[:])}, nil
+}
+
+// Sign returns statement in a signed envelope.
+func (s *Signer) Sign(statement *Statement) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+	message := pae(PayloadType, payload)
+
+	var sig []byte
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		sig, err = s.key.Sign(rand.Reader, message, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		sig, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     payload,
+		Signatures:  []Signature{{KeyID: s.keyID, Sig: sig}},
+	}, nil
+}
+
+// pae returns the DSSE pre-authentication encoding of payload, which is what is signed.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/openshift/api/build"
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
 	buildregistry "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/build"
 	buildprinters "github.com/openshift/openshift-apiserver/pkg/build/printers/internalversion"
 )
@@ -31,7 +32,10 @@ func (r *REST) Categories() []string {
 }
 
 // NewREST returns a RESTStorage object that will work against Build objects.
-func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, *DetailsREST, error) {
+// provenanceSigner, if set, signs the provenance statements recorded for
+// completed builds.
+func NewREST(optsGetter generic.RESTOptionsGetter, provenanceSigner *provenance.Signer) (*REST, *DetailsREST, error) {
+	strategy := buildregistry.NewStrategy(provenanceSigner)
 	store := &registry.Store{
 		NewFunc:                   func() runtime.Object { return &buildapi.Build{} },
 		NewListFunc:               func() runtime.Object { return &buildapi.BuildList{} },
@@ -40,9 +44,9 @@ func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, *DetailsREST, error)
 
 		TableConvertor: printerstorage.TableConvertor{TableGenerator: printers.NewTableGenerator().With(buildprinters.AddBuildOpenShiftHandlers)},
 
-		CreateStrategy: buildregistry.Strategy,
-		UpdateStrategy: buildregistry.Strategy,
-		DeleteStrategy: buildregistry.Strategy,
+		CreateStrategy: strategy,
+		UpdateStrategy: strategy,
+		DeleteStrategy: strategy,
 	}
 
 	options := &generic.StoreOptions{
@@ -54,7 +58,7 @@ func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, *DetailsREST, error)
 	}
 
 	detailsStore := *store
-	detailsStore.UpdateStrategy = buildregistry.DetailsStrategy
+	detailsStore.UpdateStrategy = buildregistry.NewDetailsStrategy(provenanceSigner)
 
 	return &REST{store}, &DetailsREST{&detailsStore}, nil
 }
@@ -8,22 +8,38 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
+	buildv1 "github.com/openshift/api/build/v1"
+
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
 	buildinternalhelpers "github.com/openshift/openshift-apiserver/pkg/build/apis/build/internal_helpers"
+	buildv1conversions "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
 	"github.com/openshift/openshift-apiserver/pkg/build/apis/build/validation"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
 )
 
 // strategy implements behavior for Build objects
 type strategy struct {
 	runtime.ObjectTyper
 	names.NameGenerator
+	// provenanceSigner, if set, signs the provenance statements recorded for
+	// completed builds.
+	provenanceSigner *provenance.Signer
 }
 
 // Strategy is the default logic that applies when creating and updating Build objects.
-var Strategy = strategy{legacyscheme.Scheme, names.SimpleNameGenerator}
+var Strategy = strategy{legacyscheme.Scheme, names.SimpleNameGenerator, nil}
+
+// NewStrategy returns the Strategy signing the provenance statements of completed
+// builds with signer.
+func NewStrategy(signer *provenance.Signer) strategy {
+	s := Strategy
+	s.provenanceSigner = signer
+	return s
+}
 
 func (strategy) NamespaceScoped() bool {
 	return true
@@ -50,6 +66,9 @@ func manageConditions(build *buildapi.Build) {
 	now := metav1.Now()
 	found := false
 	for i, c := range build.Status.Conditions {
+		if c.Type == buildapi.BuildConditionCancelRequested {
+			continue
+		}
 		if buildapi.BuildPhase(c.Type) == build.Status.Phase {
 			found = true
 			if c.Status != kapi.ConditionTrue || c.Reason != string(build.Status.Reason) || c.Message != build.Status.Message {
@@ -88,6 +107,8 @@ func manageConditions(build *buildapi.Build) {
 func (strategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
 	build := obj.(*buildapi.Build)
 	build.Generation = 1
+	// the provenance is recorded by the apiserver only
+	delete(build.Annotations, provenance.Annotation)
 	if len(build.Status.Phase) == 0 {
 		build.Status.Phase = buildapi.BuildPhaseNew
 	}
@@ -95,7 +116,7 @@ func (strategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
 }
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
-func (strategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+func (s strategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
 	newBuild := obj.(*buildapi.Build)
 	oldBuild := old.(*buildapi.Build)
 	// If the build is already in a failed state, do not allow an update
@@ -111,12 +132,44 @@ func (strategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
 		newBuild.Status.Message = oldBuild.Status.Message
 	}
 	manageConditions(newBuild)
+	s.recordProvenance(newBuild, oldBuild)
 
 	if !reflect.DeepEqual(oldBuild.Spec, newBuild.Spec) {
 		newBuild.Generation = oldBuild.Generation + 1
 	}
 }
 
+// recordProvenance keeps the provenance statement of oldBuild, or records the
+// statement of newBuild if the update completes it. The statement is made from
+// the build as reported by the update completing it and is not changed afterwards.
+func (s strategy) recordProvenance(newBuild, oldBuild *buildapi.Build) {
+	if record, ok := oldBuild.Annotations[provenance.Annotation]; ok {
+		if newBuild.Annotations == nil {
+			newBuild.Annotations = map[string]string{}
+		}
+		newBuild.Annotations[provenance.Annotation] = record
+		return
+	}
+	delete(newBuild.Annotations, provenance.Annotation)
+	if oldBuild.Status.Phase == buildapi.BuildPhaseComplete || newBuild.Status.Phase != buildapi.BuildPhaseComplete {
+		return
+	}
+	build := &buildv1.Build{}
+	if err := buildv1conversions.Convert_build_Build_To_v1_Build(newBuild, build, nil); err != nil {
+		klog.V(2).Infof("Unable to record the provenance of build %s/%s: %v", newBuild.Namespace, newBuild.Name, err)
+		return
+	}
+	record, err := provenance.Record(build, s.provenanceSigner)
+	if err != nil {
+		klog.V(4).Infof("Not recording the provenance of build %s/%s: %v", newBuild.Namespace, newBuild.Name, err)
+		return
+	}
+	if newBuild.Annotations == nil {
+		newBuild.Annotations = map[string]string{}
+	}
+	newBuild.Annotations[provenance.Annotation] = record
+}
+
 // Canonicalize normalizes the object after validation.
 func (strategy) Canonicalize(obj runtime.Object) {
 }
@@ -153,7 +206,7 @@ type detailsStrategy struct {
 // Prepares a build for update by only allowing an update to build details.
 // Build details currently consists of: Spec.Revision, Status.Reason, and
 // Status.Message, all of which are updated from within the build pod
-func (detailsStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+func (s detailsStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
 	newBuild := obj.(*buildapi.Build)
 	oldBuild := old.(*buildapi.Build)
 
@@ -176,6 +229,7 @@ func (detailsStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Ob
 	newBuild.Status.Message = message
 	newBuild.Status.Output.To = outputTo
 	manageConditions(newBuild)
+	s.recordProvenance(newBuild, oldBuild)
 }
 
 // Validates that an update is valid by ensuring that no Revision exists and that it's not getting updated to blank
@@ -203,3 +257,9 @@ func (detailsStrategy) AllowUnconditionalUpdate() bool {
 
 // DetailsStrategy is the strategy used to manage updates to a Build revision
 var DetailsStrategy = detailsStrategy{Strategy}
+
+// NewDetailsStrategy returns the DetailsStrategy signing the provenance statements
+// of completed builds with signer.
+func NewDetailsStrategy(signer *provenance.Signer) detailsStrategy {
+	return detailsStrategy{NewStrategy(signer)}
+}
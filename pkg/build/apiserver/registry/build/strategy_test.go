@@ -1,13 +1,17 @@
 package build
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
 )
 
 func TestBuildStrategy(t *testing.T) {
@@ -415,6 +419,58 @@ func TestManageConditions(t *testing.T) {
 				},
 			},
 		},
+		// 6 - the cancel requested condition is left untouched
+		{
+			input: &buildapi.Build{
+				Status: buildapi.BuildStatus{
+					Phase: buildapi.BuildPhaseCancelled,
+					Conditions: []buildapi.BuildCondition{
+						{
+							Type:    buildapi.BuildConditionCancelRequested,
+							Status:  kapi.ConditionTrue,
+							Reason:  "Obsolete",
+							Message: "Cancelled by developer: obsolete",
+						},
+					},
+				},
+			},
+			expectedCreate: &buildapi.Build{
+				Status: buildapi.BuildStatus{
+					Conditions: []buildapi.BuildCondition{
+						{
+							Type:    buildapi.BuildConditionCancelRequested,
+							Status:  kapi.ConditionTrue,
+							Reason:  "Obsolete",
+							Message: "Cancelled by developer: obsolete",
+						},
+						{
+							Type:               buildapi.BuildConditionType(buildapi.BuildPhaseCancelled),
+							Status:             kapi.ConditionTrue,
+							LastUpdateTime:     now,
+							LastTransitionTime: now,
+						},
+					},
+				},
+			},
+			expectedUpdate: &buildapi.Build{
+				Status: buildapi.BuildStatus{
+					Conditions: []buildapi.BuildCondition{
+						{
+							Type:    buildapi.BuildConditionCancelRequested,
+							Status:  kapi.ConditionTrue,
+							Reason:  "Obsolete",
+							Message: "Cancelled by developer: obsolete",
+						},
+						{
+							Type:               buildapi.BuildConditionType(buildapi.BuildPhaseCancelled),
+							Status:             kapi.ConditionTrue,
+							LastUpdateTime:     now,
+							LastTransitionTime: now,
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for n, test := range tests {
@@ -541,3 +597,59 @@ func TestUpdateGeneration(t *testing.T) {
 	}
 
 }
+
+func TestRecordProvenance(t *testing.T) {
+	running := func() *buildapi.Build {
+		return &buildapi.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-1", Namespace: "default"},
+			Spec: buildapi.BuildSpec{
+				CommonSpec: buildapi.CommonSpec{
+					Strategy: buildapi.BuildStrategy{DockerStrategy: &buildapi.DockerBuildStrategy{}},
+				},
+			},
+			Status: buildapi.BuildStatus{
+				Phase:                      buildapi.BuildPhaseRunning,
+				OutputDockerImageReference: "image-registry:5000/default/app:latest",
+			},
+		}
+	}
+	complete := func() *buildapi.Build {
+		build := running()
+		build.Status.Phase = buildapi.BuildPhaseComplete
+		build.Status.Output.To = &buildapi.BuildStatusOutputTo{ImageDigest: "sha256:4567"}
+		return build
+	}
+
+	forged := running()
+	forged.Annotations = map[string]string{provenance.Annotation: "forged"}
+	Strategy.PrepareForCreate(apirequest.NewDefaultContext(), forged)
+	if _, ok := forged.Annotations[provenance.Annotation]; ok {
+		t.Errorf("expected the provenance to be removed on create")
+	}
+
+	forged = running()
+	forged.Annotations = map[string]string{provenance.Annotation: "forged"}
+	Strategy.PrepareForUpdate(apirequest.NewDefaultContext(), forged, running())
+	if _, ok := forged.Annotations[provenance.Annotation]; ok {
+		t.Errorf("expected the provenance to be removed on update")
+	}
+
+	for name, strategy := range map[string]interface {
+		PrepareForUpdate(ctx context.Context, obj, old runtime.Object)
+	}{"build": Strategy, "details": DetailsStrategy} {
+		completed := complete()
+		strategy.PrepareForUpdate(apirequest.NewDefaultContext(), completed, running())
+		record, ok := completed.Annotations[provenance.Annotation]
+		if !ok || !strings.Contains(record, "sha256") {
+			t.Errorf("%s: expected the provenance to be recorded, got %q", name, record)
+		}
+
+		update := complete()
+		update.Annotations = map[string]string{provenance.Annotation: "forged"}
+		update.Status.Output.To.ImageDigest = "sha256:89ab"
+		strategy.PrepareForUpdate(apirequest.NewDefaultContext(), update, completed)
+		if update.Annotations[provenance.Annotation] != record {
+			t.Errorf("%s: expected the recorded provenance to be kept, got %q", name, update.Annotations[provenance.Annotation])
+		}
+	}
+}
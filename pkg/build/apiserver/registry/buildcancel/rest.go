@@ -0,0 +1,159 @@
+package buildcancel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/openshift/api/build"
+	buildv1 "github.com/openshift/api/build/v1"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	v1 "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
+	"github.com/openshift/openshift-apiserver/pkg/build/apis/build/validation"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/buildgenerator"
+)
+
+// NewStorage creates the storage cancelling a single build.
+func NewStorage(generator *buildgenerator.BuildGenerator) *CancelREST {
+	return &CancelREST{generator: generator}
+}
+
+// NewBuildConfigStorage creates the storage cancelling the builds of a BuildConfig.
+func NewBuildConfigStorage(generator *buildgenerator.BuildGenerator) *BuildConfigCancelREST {
+	return &BuildConfigCancelREST{generator: generator}
+}
+
+// CancelREST implements builds/cancel.
+type CancelREST struct {
+	generator *buildgenerator.BuildGenerator
+}
+
+var _ rest.NamedCreater = &CancelREST{}
+var _ rest.StorageMetadata = &CancelREST{}
+
+// New creates a new build cancel request
+func (r *CancelREST) New() runtime.Object {
+	return &buildapi.BuildCancelRequest{}
+}
+
+func (r *CancelREST) Destroy() {}
+
+// Create cancels the named build and returns it.
+func (r *CancelREST) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	request, err := validateCancelRequest(ctx, name, obj, createValidation)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.LabelSelector) > 0 || request.OlderThanSeconds != nil {
+		return nil, errors.NewBadRequest("labelSelector and olderThanSeconds are only supported by buildconfigs/cancel")
+	}
+	build, err := r.generator.Cancel(ctx, name, cancelOptions(request))
+	if err != nil {
+		return nil, err
+	}
+	internalBuild := &buildapi.Build{}
+	if err := v1.Convert_v1_Build_To_build_Build(build, internalBuild, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return internalBuild, nil
+}
+
+func (r *CancelREST) ProducesObject(verb string) interface{} {
+	// for documentation purposes
+	return buildv1.Build{}
+}
+
+func (r *CancelREST) ProducesMIMETypes(verb string) []string {
+	return nil // no additional mime types
+}
+
+// BuildConfigCancelREST implements buildconfigs/cancel.
+type BuildConfigCancelREST struct {
+	generator *buildgenerator.BuildGenerator
+}
+
+var _ rest.NamedCreater = &BuildConfigCancelREST{}
+var _ rest.StorageMetadata = &BuildConfigCancelREST{}
+
+// New creates a new build cancel request
+func (r *BuildConfigCancelREST) New() runtime.Object {
+	return &buildapi.BuildCancelRequest{}
+}
+
+func (r *BuildConfigCancelREST) Destroy() {}
+
+// Create cancels the builds of the named BuildConfig selected by the request and
+// returns them.
+func (r *BuildConfigCancelREST) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	request, err := validateCancelRequest(ctx, name, obj, createValidation)
+	if err != nil {
+		return nil, err
+	}
+	// the selector was validated already
+	selector, _ := labels.Parse(request.LabelSelector)
+	var createdBefore *metav1.Time
+	if request.OlderThanSeconds != nil {
+		t := metav1.NewTime(time.Now().Add(-time.Duration(*request.OlderThanSeconds) * time.Second))
+		createdBefore = &t
+	}
+	builds, err := r.generator.CancelBuildConfigBuilds(ctx, name, selector, createdBefore, cancelOptions(request))
+	if err != nil {
+		return nil, err
+	}
+	internalList := &buildapi.BuildList{}
+	if err := v1.Convert_v1_BuildList_To_build_BuildList(&buildv1.BuildList{Items: builds}, internalList, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return internalList, nil
+}
+
+func (r *BuildConfigCancelREST) ProducesObject(verb string) interface{} {
+	// for documentation purposes
+	return buildv1.BuildList{}
+}
+
+func (r *BuildConfigCancelREST) ProducesMIMETypes(verb string) []string {
+	return nil // no additional mime types
+}
+
+func validateCancelRequest(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc) (*buildapi.BuildCancelRequest, error) {
+	request, ok := obj.(*buildapi.BuildCancelRequest)
+	if !ok {
+		return nil, errors.NewBadRequest("a BuildCancelRequest is required")
+	}
+	// the request names the build or BuildConfig in the path, the body may omit it
+	if len(request.Name) == 0 {
+		request.Name = name
+	}
+	if len(request.Namespace) == 0 {
+		request.Namespace = apirequest.NamespaceValue(ctx)
+	}
+	if request.Name != name {
+		return nil, errors.NewBadRequest(fmt.Sprintf("the name of the request %q does not match %q", request.Name, name))
+	}
+	if errs := validation.ValidateBuildCancelRequest(request); len(errs) > 0 {
+		return nil, errors.NewInvalid(build.Kind("BuildCancelRequest"), name, errs)
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+func cancelOptions(request *buildapi.BuildCancelRequest) buildgenerator.CancelOptions {
+	return buildgenerator.CancelOptions{
+		Reason:             request.Reason,
+		Message:            request.Message,
+		GracePeriodSeconds: request.GracePeriodSeconds,
+	}
+}
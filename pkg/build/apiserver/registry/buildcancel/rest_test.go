@@ -0,0 +1,51 @@
+package buildcancel
+
+import (
+	"context"
+	"testing"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/buildgenerator"
+)
+
+func TestCreate(t *testing.T) {
+	olderThan := int64(600)
+	negative := int64(-1)
+	testCases := map[string]struct {
+		request     *buildapi.BuildCancelRequest
+		expectError func(error) bool
+	}{
+		"empty request":           {request: &buildapi.BuildCancelRequest{}},
+		"reason and message":      {request: &buildapi.BuildCancelRequest{Reason: "Superseded", Message: "newer commit"}},
+		"reason is not camel":     {request: &buildapi.BuildCancelRequest{Reason: "not a reason"}, expectError: kerrors.IsInvalid},
+		"invalid selector":        {request: &buildapi.BuildCancelRequest{LabelSelector: "team in ("}, expectError: kerrors.IsInvalid},
+		"negative older than":     {request: &buildapi.BuildCancelRequest{OlderThanSeconds: &negative}, expectError: kerrors.IsInvalid},
+		"grace period":            {request: &buildapi.BuildCancelRequest{GracePeriodSeconds: &olderThan}},
+		"negative grace period":   {request: &buildapi.BuildCancelRequest{GracePeriodSeconds: &negative}, expectError: kerrors.IsInvalid},
+		"name mismatch":           {request: &buildapi.BuildCancelRequest{ObjectMeta: metav1.ObjectMeta{Name: "build-2"}}, expectError: kerrors.IsBadRequest},
+		"bulk options on a build": {request: &buildapi.BuildCancelRequest{LabelSelector: "team=a", OlderThanSeconds: &olderThan}, expectError: kerrors.IsBadRequest},
+	}
+	for name, tc := range testCases {
+		storage := NewStorage(&buildgenerator.BuildGenerator{Client: buildgenerator.TestingClient{
+			GetBuildFunc: func(ctx context.Context, name string, _ metav1.GetOptions) (*buildv1.Build, error) {
+				return &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: buildv1.BuildStatus{Phase: buildv1.BuildPhaseRunning}}, nil
+			},
+			UpdateBuildFunc: func(ctx context.Context, build *buildv1.Build, _ metav1.UpdateOptions) error {
+				return nil
+			},
+		}})
+		_, err := storage.Create(apirequest.NewDefaultContext(), "build-1", tc.request, rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+		switch {
+		case tc.expectError == nil && err != nil:
+			t.Errorf("%s: unexpected error: %v", name, err)
+		case tc.expectError != nil && !tc.expectError(err):
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
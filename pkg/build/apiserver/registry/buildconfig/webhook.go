@@ -17,6 +17,7 @@ import (
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	kubetypedclient "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
 	"github.com/openshift/api/build"
@@ -46,22 +47,24 @@ type WebHook struct {
 	buildConfigClient buildclienttyped.BuildConfigsGetter
 	secretsClient     kubetypedclient.SecretsGetter
 	instantiator      buildclienttyped.BuildConfigsGetter
+	recorder          record.EventRecorder
 	plugins           map[string]webhook.Plugin
 }
 
 // NewWebHookREST returns the webhook handler
-func NewWebHookREST(buildConfigClient buildclienttyped.BuildV1Interface, secretsClient kubetypedclient.SecretsGetter, groupVersion schema.GroupVersion, plugins map[string]webhook.Plugin) *WebHook {
-	return newWebHookREST(buildConfigClient, secretsClient, groupVersion, plugins)
+func NewWebHookREST(buildConfigClient buildclienttyped.BuildV1Interface, secretsClient kubetypedclient.SecretsGetter, recorder record.EventRecorder, groupVersion schema.GroupVersion, plugins map[string]webhook.Plugin) *WebHook {
+	return newWebHookREST(buildConfigClient, secretsClient, recorder, groupVersion, plugins)
 }
 
 // this supports simple unit testing
-func newWebHookREST(buildConfigClient buildclienttyped.BuildConfigsGetter, secretsClient kubetypedclient.SecretsGetter, groupVersion schema.GroupVersion,
+func newWebHookREST(buildConfigClient buildclienttyped.BuildConfigsGetter, secretsClient kubetypedclient.SecretsGetter, recorder record.EventRecorder, groupVersion schema.GroupVersion,
 	plugins map[string]webhook.Plugin) *WebHook {
 	return &WebHook{
 		groupVersion:      groupVersion,
 		buildConfigClient: buildConfigClient,
 		instantiator:      buildConfigClient,
 		secretsClient:     secretsClient,
+		recorder:          recorder,
 		plugins:           plugins,
 	}
 }
@@ -85,6 +88,7 @@ func (h *WebHook) Connect(ctx context.Context, name string, options runtime.Obje
 		buildConfigClient: h.buildConfigClient,
 		secretsClient:     h.secretsClient,
 		instantiator:      h.instantiator,
+		recorder:          h.recorder,
 	}, nil
 }
 
@@ -109,6 +113,7 @@ type WebHookHandler struct {
 	buildConfigClient buildclienttyped.BuildConfigsGetter
 	secretsClient     kubetypedclient.SecretsGetter
 	instantiator      buildclienttyped.BuildConfigsGetter
+	recorder          record.EventRecorder
 }
 
 // ServeHTTP implements the standard http.Handler
@@ -140,19 +145,33 @@ func (w *WebHookHandler) ProcessWebHook(writer http.ResponseWriter, req *http.Re
 		return errors.NewUnauthorized(fmt.Sprintf("the webhook %q for %q did not accept your secret", hookType, name))
 	}
 
+	delivery := &webhook.Delivery{Plugin: hookType}
+	defer func() {
+		delivery.Redact(secret)
+		w.recordDelivery(config, delivery)
+	}()
+	req = req.WithContext(webhook.WithDelivery(req.Context(), delivery))
+
 	triggers, err := plugin.GetTriggers(config)
 	if err != nil {
+		delivery.Outcome, delivery.Reason = webhook.DeliveryOutcomeRejected, fmt.Sprintf("no %s webhook trigger is enabled", hookType)
 		return errors.NewUnauthorized(fmt.Sprintf("the webhook %q for %q did not accept your secret", hookType, name))
 	}
 
 	klog.V(4).Infof("checking secret for %q webhook trigger of buildconfig %s/%s", hookType, config.Namespace, config.Name)
 	trigger, err := webhook.CheckSecret(ctx, config.Namespace, secret, triggers, w.secretsClient)
 	if err != nil {
+		delivery.Outcome, delivery.Reason = webhook.DeliveryOutcomeRejected, webhook.ErrSecretMismatch.Error()
 		return errors.NewUnauthorized(fmt.Sprintf("the webhook %q for %q did not accept your secret", hookType, name))
 	}
+	req = req.WithContext(webhook.WithSecret(req.Context(), secret))
 
-	revision, envvars, dockerStrategyOptions, proceed, err := plugin.Extract(config, trigger, req)
+	revision, envvars, dockerStrategyOptions, parameters, proceed, err := plugin.Extract(config, trigger, req)
 	if !proceed {
+		delivery.Outcome = webhook.DeliveryOutcomeRejected
+		if err != nil {
+			delivery.Reason = err.Error()
+		}
 		switch err {
 		case webhook.ErrSecretMismatch, webhook.ErrHookNotEnabled:
 			return errors.NewUnauthorized(fmt.Sprintf("the webhook %q for %q did not accept your secret", hookType, name))
@@ -160,8 +179,14 @@ func (w *WebHookHandler) ProcessWebHook(writer http.ResponseWriter, req *http.Re
 			return errors.NewMethodNotSupported(build.Resource("buildconfighook"), req.Method)
 		}
 		if _, ok := err.(*errors.StatusError); !ok && err != nil {
+			delivery.Outcome = webhook.DeliveryOutcomeFailed
 			return errors.NewInternalError(fmt.Errorf("hook failed: %v", err))
 		}
+		if err == nil {
+			delivery.Outcome, delivery.Reason = webhook.DeliveryOutcomeSkipped, "the event does not match the build configuration"
+		} else if status := err.(*errors.StatusError); status.ErrStatus.Status == metav1.StatusSuccess {
+			delivery.Outcome = webhook.DeliveryOutcomeSkipped
+		}
 		return err
 	}
 	warning := err
@@ -174,12 +199,18 @@ func (w *WebHookHandler) ProcessWebHook(writer http.ResponseWriter, req *http.Re
 		Revision:              revision,
 		Env:                   envvars,
 		DockerStrategyOptions: dockerStrategyOptions,
+		Parameters:            parameters,
 	}
 
 	newBuild, err := w.instantiator.BuildConfigs(config.Namespace).Instantiate(ctx, config.Namespace, request, metav1.CreateOptions{})
 	if err != nil {
+		delivery.Outcome, delivery.Reason = webhook.DeliveryOutcomeFailed, fmt.Sprintf("could not generate a build: %v", err)
 		return errors.NewInternalError(fmt.Errorf("could not generate a build: %v", err))
 	}
+	delivery.Outcome, delivery.Build = webhook.DeliveryOutcomeBuildTriggered, newBuild.Name
+	if warning != nil {
+		delivery.Reason = warning.Error()
+	}
 
 	// Send back the build name so that the client can alert the user.
 	if newBuildEncoded, err := runtime.Encode(webhookEncodingCodecFactory.LegacyCodec(w.groupVersion), newBuild); err != nil {
@@ -190,3 +221,18 @@ func (w *WebHookHandler) ProcessWebHook(writer http.ResponseWriter, req *http.Re
 
 	return warning
 }
+
+// recordDelivery emits an event on the BuildConfig describing the outcome of a
+// webhook request, so that users without access to the apiserver logs can see why
+// a push did or did not result in a build. The BuildConfig itself is never
+// written, and the event recorder rate limits the events emitted for a single
+// BuildConfig, so unauthenticated requests cannot be used to flood etcd.
+func (w *WebHookHandler) recordDelivery(config *buildv1.BuildConfig, delivery *webhook.Delivery) {
+	if delivery.Outcome == webhook.DeliveryOutcomeRejected {
+		klog.V(2).Infof("rejected %s webhook request for buildconfig %s/%s: %s", delivery.Plugin, config.Namespace, config.Name, delivery.Reason)
+	}
+	if w.recorder == nil {
+		return
+	}
+	w.recorder.Event(config, delivery.EventType(), delivery.EventReason(), delivery.String())
+}
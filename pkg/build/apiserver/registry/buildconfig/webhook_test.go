@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	clientesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
 	buildv1 "github.com/openshift/api/build/v1"
@@ -135,12 +136,13 @@ type plugin struct {
 	Err                   error
 	Env                   []corev1.EnvVar
 	DockerStrategyOptions *buildv1.DockerStrategyOptions
+	Parameters            map[string]string
 	Proceed               bool
 }
 
-func (p *plugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision, []corev1.EnvVar, *buildv1.DockerStrategyOptions, bool, error) {
+func (p *plugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision, []corev1.EnvVar, *buildv1.DockerStrategyOptions, map[string]string, bool, error) {
 	p.Triggers = []*buildv1.WebHookTrigger{trigger}
-	return nil, p.Env, p.DockerStrategyOptions, p.Proceed, p.Err
+	return nil, p.Env, p.DockerStrategyOptions, p.Parameters, p.Proceed, p.Err
 }
 func (p *plugin) GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error) {
 	trigger := &buildv1.WebHookTrigger{
@@ -162,11 +164,15 @@ func newStorage() (*WebHook, *buildConfigInstantiator, *buildfake.Clientset) {
 			},
 			Proceed: true,
 		},
+		"okparameters": &plugin{
+			Parameters: map[string]string{"tag": "v2"},
+			Proceed:    true,
+		},
 		"errsecret": &plugin{Err: webhook.ErrSecretMismatch},
 		"errhook":   &plugin{Err: webhook.ErrHookNotEnabled},
 		"err":       &plugin{Err: fmt.Errorf("test error")},
 	}
-	hook := newWebHookREST(fakeBuildClient, nil, buildv1.SchemeGroupVersion, plugins)
+	hook := newWebHookREST(fakeBuildClient, nil, nil, buildv1.SchemeGroupVersion, plugins)
 
 	return hook, bci, fakeBuildClient.(*fakeBuildConfigClient).fakeclient
 }
@@ -204,6 +210,7 @@ func TestConnectWebHook(t *testing.T) {
 		ErrFn       func(error) bool
 		WFn         func(*httptest.ResponseRecorder) bool
 		EnvLen      int
+		Parameters  map[string]string
 		Instantiate bool
 	}{
 		"hook returns generic error": {
@@ -269,6 +276,17 @@ func TestConnectWebHook(t *testing.T) {
 			EnvLen:      1,
 			Instantiate: true,
 		},
+		"hook returns 200 for okparameters hook": {
+			Name:  "test",
+			Path:  "secret/okparameters",
+			Obj:   &buildv1.BuildConfig{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+			ErrFn: func(err error) bool { return err == nil },
+			WFn: func(w *httptest.ResponseRecorder) bool {
+				return w.Code == http.StatusOK
+			},
+			Parameters:  map[string]string{"tag": "v2"},
+			Instantiate: true,
+		},
 	}
 	for k, testCase := range testCases {
 		hook, bci, fakeBuildClient := newStorage()
@@ -316,6 +334,9 @@ func TestConnectWebHook(t *testing.T) {
 		if bci.Request != nil && testCase.EnvLen != len(bci.Request.Env) {
 			t.Errorf("%s: build request does not have correct env vars:  %+v \n", k, bci.Request)
 		}
+		if bci.Request != nil && !reflect.DeepEqual(testCase.Parameters, bci.Request.Parameters) {
+			t.Errorf("%s: build request does not have correct parameters: %v", k, bci.Request.Parameters)
+		}
 	}
 }
 
@@ -352,8 +373,8 @@ type pathPlugin struct {
 }
 
 func (p *pathPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision,
-	[]corev1.EnvVar, *buildv1.DockerStrategyOptions, bool, error) {
-	return nil, []corev1.EnvVar{}, nil, true, nil
+	[]corev1.EnvVar, *buildv1.DockerStrategyOptions, map[string]string, bool, error) {
+	return nil, []corev1.EnvVar{}, nil, nil, true, nil
 }
 
 func (p *pathPlugin) GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error) {
@@ -367,8 +388,8 @@ type errPlugin struct {
 }
 
 func (*errPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision,
-	[]corev1.EnvVar, *buildv1.DockerStrategyOptions, bool, error) {
-	return nil, []corev1.EnvVar{}, nil, false, errors.New("Plugin error!")
+	[]corev1.EnvVar, *buildv1.DockerStrategyOptions, map[string]string, bool, error) {
+	return nil, []corev1.EnvVar{}, nil, nil, false, errors.New("Plugin error!")
 }
 func (p *errPlugin) GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error) {
 	trigger := &buildv1.WebHookTrigger{
@@ -422,7 +443,7 @@ var mockBuildStrategy = buildv1.BuildStrategy{
 func TestParseUrlError(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{})
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion,
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion,
 		map[string]webhook.Plugin{"github": github.New(), "gitlab": gitlab.New(), "bitbucket": bitbucket.New()}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: ""}, responder)
 	server := httptest.NewServer(handler)
@@ -441,7 +462,7 @@ func TestParseUrlError(t *testing.T) {
 func TestParseUrlOK(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: "secret101/pathplugin"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -459,7 +480,7 @@ func TestParseUrlLong(t *testing.T) {
 	plugin := &pathPlugin{}
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": plugin}).
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": plugin}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: "secret101/pathplugin/some/more/args"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -477,7 +498,7 @@ func TestParseUrlLong(t *testing.T) {
 func TestInvokeWebhookMissingPlugin(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(),
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(),
 		testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: "secret101/missingplugin"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -495,7 +516,7 @@ func TestInvokeWebhookMissingPlugin(t *testing.T) {
 func TestInvokeWebhookErrorBuildConfigInstantiate(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&errorBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: "secret101/pathplugin"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -513,7 +534,7 @@ func TestInvokeWebhookErrorBuildConfigInstantiate(t *testing.T) {
 func TestInvokeWebhookErrorGetConfig(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"pathplugin": &pathPlugin{}}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "badbuild100", &kapi.PodProxyOptions{Path: "secret101/pathplugin"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -533,7 +554,7 @@ func TestInvokeWebhookErrorGetConfig(t *testing.T) {
 func TestInvokeWebhookErrorCreateBuild(t *testing.T) {
 	responder := &fakeResponder{}
 	client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig)
-	handler, _ := newWebHookREST(client, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"errPlugin": &errPlugin{}}).
+	handler, _ := newWebHookREST(client, nil, nil, buildv1.SchemeGroupVersion, map[string]webhook.Plugin{"errPlugin": &errPlugin{}}).
 		Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: "secret101/errPlugin"}, responder)
 	server := httptest.NewServer(handler)
 	defer server.Close()
@@ -548,6 +569,66 @@ func TestInvokeWebhookErrorCreateBuild(t *testing.T) {
 	}
 }
 
+func TestInvokeWebhookRecordsDelivery(t *testing.T) {
+	testCases := map[string]struct {
+		path    string
+		plugins map[string]webhook.Plugin
+		outcome webhook.DeliveryOutcome
+		build   string
+	}{
+		"build triggered": {
+			path:    "secret101/pathplugin",
+			plugins: map[string]webhook.Plugin{"pathplugin": &pathPlugin{}},
+			outcome: webhook.DeliveryOutcomeBuildTriggered,
+			build:   "build100",
+		},
+		"secret mismatch": {
+			path:    "wrongsecret/pathplugin",
+			plugins: map[string]webhook.Plugin{"pathplugin": &pathPlugin{}},
+			outcome: webhook.DeliveryOutcomeRejected,
+		},
+		"plugin failure": {
+			path:    "secret101/errPlugin",
+			plugins: map[string]webhook.Plugin{"errPlugin": &errPlugin{}},
+			outcome: webhook.DeliveryOutcomeFailed,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			responder := &fakeResponder{}
+			client := newBuildConfigClient(&okBuildConfigInstantiator{}, testBuildConfig.DeepCopy())
+			recorder := record.NewFakeRecorder(10)
+			handler, _ := newWebHookREST(client, nil, recorder, buildv1.SchemeGroupVersion, tc.plugins).
+				Connect(apirequest.WithNamespace(apirequest.NewDefaultContext(), testBuildConfig.Namespace), "build100", &kapi.PodProxyOptions{Path: tc.path}, responder)
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			if _, err := http.Post(server.URL, "application/json", nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(recorder.Events) != 1 {
+				t.Fatalf("Expected one recorded event, got %d", len(recorder.Events))
+			}
+			event := <-recorder.Events
+			if !strings.Contains(event, "WebHook"+string(tc.outcome)) {
+				t.Errorf("Expected an event for outcome %s, got %q", tc.outcome, event)
+			}
+			if !strings.Contains(event, tc.build) {
+				t.Errorf("Expected the event to name build %q, got %q", tc.build, event)
+			}
+			if strings.Contains(event, "secret101") || strings.Contains(event, "wrongsecret") {
+				t.Errorf("Expected the secret to be redacted from the event, got %q", event)
+			}
+			for _, action := range client.(*fakeBuildConfigClient).fakeclient.Actions() {
+				if action.GetVerb() == "update" && action.GetSubresource() == "" {
+					t.Errorf("Expected the buildconfig not to be updated, got %#v", action)
+				}
+			}
+		})
+	}
+}
+
 func TestGeneratedBuildTriggerInfoGenericWebHook(t *testing.T) {
 	externalRevision := &buildv1.SourceRevision{
 		Git: &buildv1.GitSourceRevision{
@@ -667,3 +748,63 @@ func TestGeneratedBuildTriggerInfoBitbucketWebHook(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneratedBuildTriggerInfoGiteaWebHook(t *testing.T) {
+	externalRevision := &buildv1.SourceRevision{
+		Git: &buildv1.GitSourceRevision{
+			Author: buildv1.SourceControlUser{
+				Name:  "John Doe",
+				Email: "john.doe@test.com",
+			},
+			Committer: buildv1.SourceControlUser{
+				Name:  "John Doe",
+				Email: "john.doe@test.com",
+			},
+			Message: "A random act of kindness",
+		},
+	}
+
+	buildtriggerCause := webhook.GenerateBuildTriggerInfo(externalRevision, "gitea")
+	hiddenSecret := "<secret>"
+	for _, cause := range buildtriggerCause {
+		if !reflect.DeepEqual(externalRevision, cause.GiteaWebHook.Revision) {
+			t.Errorf("Expected returned externalRevision to equal: %v", externalRevision)
+		}
+		if cause.GiteaWebHook.Secret != hiddenSecret {
+			t.Errorf("Expected obfuscated secret to be: %s", hiddenSecret)
+		}
+		if cause.Message != apiserverbuildutil.BuildTriggerCauseGiteaMsg {
+			t.Errorf("Expected build reason to be 'Gitea WebHook, go %s'", cause.Message)
+		}
+	}
+}
+
+func TestGeneratedBuildTriggerInfoAzureDevOpsWebHook(t *testing.T) {
+	externalRevision := &buildv1.SourceRevision{
+		Git: &buildv1.GitSourceRevision{
+			Author: buildv1.SourceControlUser{
+				Name:  "John Doe",
+				Email: "john.doe@test.com",
+			},
+			Committer: buildv1.SourceControlUser{
+				Name:  "John Doe",
+				Email: "john.doe@test.com",
+			},
+			Message: "A random act of kindness",
+		},
+	}
+
+	buildtriggerCause := webhook.GenerateBuildTriggerInfo(externalRevision, "azuredevops")
+	hiddenSecret := "<secret>"
+	for _, cause := range buildtriggerCause {
+		if !reflect.DeepEqual(externalRevision, cause.AzureDevOpsWebHook.Revision) {
+			t.Errorf("Expected returned externalRevision to equal: %v", externalRevision)
+		}
+		if cause.AzureDevOpsWebHook.Secret != hiddenSecret {
+			t.Errorf("Expected obfuscated secret to be: %s", hiddenSecret)
+		}
+		if cause.Message != apiserverbuildutil.BuildTriggerCauseAzureDevOpsMsg {
+			t.Errorf("Expected build reason to be 'Azure DevOps WebHook, go %s'", cause.Message)
+		}
+	}
+}
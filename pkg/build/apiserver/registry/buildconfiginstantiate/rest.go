@@ -5,15 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
-	v1 "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/registry/rest"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/remotecommand"
@@ -22,6 +23,7 @@ import (
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
 	buildv1 "github.com/openshift/api/build/v1"
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
 	buildtypedclient "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
 	buildinternalhelpers "github.com/openshift/openshift-apiserver/pkg/build/apis/build/internal_helpers"
@@ -30,11 +32,6 @@ import (
 	buildwait "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/wait"
 )
 
-var (
-	cancelPollInterval = 500 * time.Millisecond
-	cancelPollDuration = 30 * time.Second
-)
-
 // NewStorage creates a new storage object for build generation
 func NewStorage(generator *buildgenerator.BuildGenerator) *InstantiateREST {
 	return &InstantiateREST{generator: generator}
@@ -93,7 +90,12 @@ func (s *InstantiateREST) ProducesMIMETypes(verb string) []string {
 	return nil // no additional mime types
 }
 
-func NewBinaryStorage(generator *buildgenerator.BuildGenerator, buildClient buildtypedclient.BuildsGetter, inClientConfig *restclient.Config) *BinaryInstantiateREST {
+func NewBinaryStorage(generator *buildgenerator.BuildGenerator, buildClient buildtypedclient.BuildsGetter, inClientConfig *restclient.Config, uploadConfig openshiftcontrolplanev1.BinaryBuildUploadConfig) (*BinaryInstantiateREST, error) {
+	stager, err := newUploadStagerFromConfig(uploadConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	clientConfig := restclient.CopyConfig(inClientConfig)
 	clientConfig.APIPath = "/api"
 	clientConfig.GroupVersion = &schema.GroupVersion{Version: "v1"}
@@ -104,7 +106,8 @@ func NewBinaryStorage(generator *buildgenerator.BuildGenerator, buildClient buil
 		BuildClient:  buildClient,
 		ClientConfig: clientConfig,
 		Timeout:      5 * time.Minute,
-	}
+		stager:       stager,
+	}, nil
 }
 
 type BinaryInstantiateREST struct {
@@ -112,6 +115,9 @@ type BinaryInstantiateREST struct {
 	BuildClient  buildtypedclient.BuildsGetter
 	ClientConfig *restclient.Config
 	Timeout      time.Duration
+
+	// stager is nil when staging is disabled
+	stager *uploadStager
 }
 
 var _ rest.Connecter = &BinaryInstantiateREST{}
@@ -168,6 +174,15 @@ var _ http.Handler = &binaryInstantiateHandler{}
 
 func (h *binaryInstantiateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
+	upload, err := parseStagedUpload(r)
+	if err != nil {
+		h.responder.Error(err)
+		return
+	}
+	if upload != nil {
+		h.serveStaged(w, r, upload)
+		return
+	}
 	build, err := h.handle(r.Body)
 	if err != nil {
 		h.responder.Error(err)
@@ -176,6 +191,46 @@ func (h *binaryInstantiateHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	h.responder.Object(http.StatusCreated, build)
 }
 
+// serveStaged stages a chunk of a binary upload, and once the upload is complete
+// and verified, instantiates the build from the staged content.
+func (h *binaryInstantiateHandler) serveStaged(w http.ResponseWriter, r *http.Request, upload *stagedUpload) {
+	if h.r.stager == nil {
+		h.responder.Error(errors.NewBadRequest(fmt.Sprintf("staged binary uploads are not enabled on this server, send the archive without the %s header", UploadIDHeader)))
+		return
+	}
+	namespace := apirequest.NamespaceValue(h.ctx)
+	offset, complete, err := h.r.stager.Stage(namespace, h.name, upload, r.Body)
+	w.Header().Set(UploadOffsetHeader, strconv.FormatInt(offset, 10))
+	if err != nil {
+		h.responder.Error(err)
+		return
+	}
+	if !complete {
+		h.responder.Object(http.StatusAccepted, &metav1.Status{
+			Status:  metav1.StatusSuccess,
+			Code:    http.StatusAccepted,
+			Message: fmt.Sprintf("staged %d of %d bytes of upload %s", offset, upload.Info.Length, upload.ID),
+		})
+		return
+	}
+
+	content, err := h.r.stager.Open(namespace, h.name, upload)
+	if err != nil {
+		h.responder.Error(errors.NewInternalError(err))
+		return
+	}
+	defer content.Close()
+	build, err := h.handle(content)
+	if err != nil {
+		// the verified content is kept, so that the client can retry the
+		// instantiation by sending an empty chunk at the final offset
+		h.responder.Error(err)
+		return
+	}
+	h.r.stager.Remove(namespace, h.name, upload)
+	h.responder.Object(http.StatusCreated, build)
+}
+
 func (h *binaryInstantiateHandler) handle(r io.Reader) (runtime.Object, error) {
 	h.options.Name = h.name
 	objectMeta, err := meta.Accessor(h.options)
@@ -327,22 +382,10 @@ func (h *binaryInstantiateHandler) handle(r io.Reader) (runtime.Object, error) {
 // cancelBuild will mark a build for cancellation unless
 // cancel is false in which case it is a no-op.
 func (h *binaryInstantiateHandler) cancelBuild(build *buildapi.Build) {
-	var versionedBuild = &buildv1.Build{}
-	if err := v1.Convert_build_Build_To_v1_Build(build, versionedBuild, nil); err != nil {
-		klog.Errorf("Unable to convert build to versioned build: %v", err)
-		return
-	}
-	versionedBuild.Status.Cancelled = true
-	h.r.Generator.Client.UpdateBuild(h.ctx, versionedBuild, metav1.UpdateOptions{})
-	wait.Poll(cancelPollInterval, cancelPollDuration, func() (bool, error) {
-		versionedBuild.Status.Cancelled = true
-		err := h.r.Generator.Client.UpdateBuild(h.ctx, versionedBuild, metav1.UpdateOptions{})
-		switch {
-		case err != nil && errors.IsConflict(err):
-			versionedBuild, err = h.r.Generator.Client.GetBuild(h.ctx, versionedBuild.Name, metav1.GetOptions{})
-			return false, err
-		default:
-			return true, err
-		}
+	_, err := h.r.Generator.Cancel(h.ctx, build.Name, buildgenerator.CancelOptions{
+		Message: "The binary content of the build could not be uploaded.",
 	})
+	if err != nil {
+		klog.Errorf("Unable to cancel build %s/%s: %v", build.Namespace, build.Name, err)
+	}
 }
@@ -0,0 +1,392 @@
+package buildconfiginstantiate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+// Binary uploads may be staged on the apiserver before a build is created. A
+// client opts into staging by sending UploadIDHeader together with the declared
+// size and SHA-256 of the archive, and then POSTs the archive in one or more
+// chunks, each declaring the offset it starts at. Once the staged content is
+// complete and matches the declared checksum, the build is instantiated and the
+// archive is fed to the build pod. Every response reports the number of bytes
+// staged so far in UploadOffsetHeader, and a chunk starting at any other offset
+// is rejected with a conflict, which lets a client resume an interrupted upload.
+//
+// Staging is disabled unless a staging directory is configured. Staged content
+// lives in that directory, which is local to the apiserver instance. Staging
+// therefore assumes that every chunk of an upload reaches the same instance: when
+// several apiservers are load balanced without session affinity, a chunk resuming
+// an upload on another instance is rejected with a conflict reporting an offset
+// of 0, and the client has to restart the upload there. The declared size of an
+// upload is bounded, and so is the total size of the uploads staged by an
+// instance, so that staging cannot exhaust the disk.
+const (
+	// UploadIDHeader identifies a staged upload. It is chosen by the client and
+	// must be a DNS-1123 label.
+	UploadIDHeader = "X-Upload-Id"
+	// UploadLengthHeader declares the total size of the staged archive in bytes.
+	UploadLengthHeader = "X-Upload-Length"
+	// UploadSHA256Header declares the hex encoded SHA-256 of the staged archive.
+	UploadSHA256Header = "X-Upload-Sha256"
+	// UploadOffsetHeader declares the offset a chunk starts at on requests, and
+	// reports the number of bytes staged so far on responses.
+	UploadOffsetHeader = "X-Upload-Offset"
+
+	defaultStagedUploadTTL = time.Hour
+	// stagedUploadRetryAfterSeconds is returned to clients staging an upload while
+	// the quota is exhausted.
+	stagedUploadRetryAfterSeconds = 60
+)
+
+// stagedUploadInfo is persisted next to the staged content so that every chunk
+// of an upload is checked against what the first chunk declared.
+type stagedUploadInfo struct {
+	Length int64  `json:"length"`
+	SHA256 string `json:"sha256"`
+}
+
+// stagedUpload describes the chunk carried by a single request.
+type stagedUpload struct {
+	ID     string
+	Info   stagedUploadInfo
+	Offset int64
+}
+
+// parseStagedUpload extracts the staging headers from req. It returns nil if the
+// request does not opt into staging.
+func parseStagedUpload(req *http.Request) (*stagedUpload, error) {
+	id := req.Header.Get(UploadIDHeader)
+	if len(id) == 0 {
+		return nil, nil
+	}
+	if errs := validation.IsDNS1123Label(id); len(errs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid %s %q: %s", UploadIDHeader, id, strings.Join(errs, ", ")))
+	}
+	length, err := strconv.ParseInt(req.Header.Get(UploadLengthHeader), 10, 64)
+	if err != nil || length <= 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("%s must be a positive number of bytes", UploadLengthHeader))
+	}
+	checksum := strings.ToLower(req.Header.Get(UploadSHA256Header))
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return nil, errors.NewBadRequest(fmt.Sprintf("%s must be a hex encoded SHA-256 digest", UploadSHA256Header))
+	}
+	offset := int64(0)
+	if value := req.Header.Get(UploadOffsetHeader); len(value) > 0 {
+		offset, err = strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("%s must be a non-negative number of bytes", UploadOffsetHeader))
+		}
+	}
+	return &stagedUpload{
+		ID:     id,
+		Info:   stagedUploadInfo{Length: length, SHA256: checksum},
+		Offset: offset,
+	}, nil
+}
+
+// uploadStager stores staged binary build archives in a local directory.
+type uploadStager struct {
+	dir       string
+	ttl       time.Duration
+	maxLength int64
+	quota     int64
+
+	lock sync.Mutex
+	// locks serializes the requests for a single upload, entries are removed once
+	// no request holds them.
+	locks map[string]*uploadLock
+	// reserved holds the declared length of every staged upload, keyed by path.
+	reserved  map[string]int64
+	lastPrune time.Time
+}
+
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newUploadStager(dir string, ttl time.Duration, maxLength, quota int64) *uploadStager {
+	return &uploadStager{
+		dir:       dir,
+		ttl:       ttl,
+		maxLength: maxLength,
+		quota:     quota,
+		locks:     map[string]*uploadLock{},
+		reserved:  map[string]int64{},
+	}
+}
+
+// newUploadStagerFromConfig returns the stager configured by config, or nil if
+// staging is disabled.
+func newUploadStagerFromConfig(config openshiftcontrolplanev1.BinaryBuildUploadConfig) (*uploadStager, error) {
+	if len(config.Directory) == 0 {
+		return nil, nil
+	}
+	if config.QuotaBytes <= 0 {
+		return nil, fmt.Errorf("binaryBuildUploadConfig.quotaBytes must be set when binaryBuildUploadConfig.directory is set")
+	}
+	maxLength := config.MaxUploadBytes
+	if maxLength <= 0 || maxLength > config.QuotaBytes {
+		maxLength = config.QuotaBytes
+	}
+	if err := os.MkdirAll(config.Directory, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create the binary build upload directory: %v", err)
+	}
+	return newUploadStager(config.Directory, defaultStagedUploadTTL, maxLength, config.QuotaBytes), nil
+}
+
+func (s *uploadStager) key(namespace, name, id string) string {
+	return filepath.Join(s.dir, namespace, name, id)
+}
+
+// lockUpload serializes requests for the same upload and returns the unlock function.
+func (s *uploadStager) lockUpload(key string) func() {
+	s.lock.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = &uploadLock{}
+		s.locks[key] = l
+	}
+	l.refs++
+	s.lock.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(s.locks, key)
+		}
+	}
+}
+
+// reserve accounts for the declared length of an upload against the quota. It is
+// a no-op for uploads that are already accounted for.
+func (s *uploadStager) reserve(key string, length int64, enforce bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.reserved[key]; ok {
+		return nil
+	}
+	total := int64(0)
+	for _, reserved := range s.reserved {
+		total += reserved
+	}
+	if enforce && total+length > s.quota {
+		return errors.NewTooManyRequests(fmt.Sprintf("binary uploads of %d bytes are already staged, retry once they complete", total), stagedUploadRetryAfterSeconds)
+	}
+	s.reserved[key] = length
+	return nil
+}
+
+// Stage appends the chunk read from r to the staged upload and returns the number
+// of bytes staged so far. When the upload is complete, its content is verified
+// against the declared checksum, and complete is true.
+func (s *uploadStager) Stage(namespace, name string, upload *stagedUpload, r io.Reader) (offset int64, complete bool, err error) {
+	for _, segment := range []string{namespace, name} {
+		if errs := path.IsValidPathSegmentName(segment); len(errs) > 0 {
+			return 0, false, errors.NewBadRequest(fmt.Sprintf("invalid name %q: %s", segment, strings.Join(errs, ", ")))
+		}
+	}
+	if upload.Info.Length > s.maxLength {
+		return 0, false, errors.NewRequestEntityTooLargeError(fmt.Sprintf("staged uploads are limited to %d bytes", s.maxLength))
+	}
+	s.pruneIfDue()
+
+	key := s.key(namespace, name, upload.ID)
+	defer s.lockUpload(key)()
+
+	if err := os.MkdirAll(filepath.Dir(key), 0700); err != nil {
+		return 0, false, errors.NewInternalError(err)
+	}
+	if err := s.checkInfo(key, upload); err != nil {
+		return 0, false, err
+	}
+
+	f, err := os.OpenFile(key, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, false, errors.NewInternalError(err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, false, errors.NewInternalError(err)
+	}
+	offset = stat.Size()
+	if upload.Offset != offset {
+		return offset, false, errors.NewConflict(buildapi.Resource("buildconfigs"), name, fmt.Errorf("upload %s has %d bytes staged, the chunk starts at offset %d", upload.ID, offset, upload.Offset))
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, false, errors.NewInternalError(err)
+	}
+
+	remaining := upload.Info.Length - offset
+	written, err := io.Copy(f, io.LimitReader(r, remaining+1))
+	offset += written
+	if err != nil {
+		// keep what was received so the client can resume from the new offset
+		klog.V(4).Infof("binary upload %s for %s/%s interrupted at offset %d: %v", upload.ID, namespace, name, offset, err)
+		return offset, false, errors.NewBadRequest(fmt.Sprintf("upload %s was interrupted at offset %d: %v", upload.ID, offset, err))
+	}
+	if offset > upload.Info.Length {
+		s.remove(key)
+		return 0, false, errors.NewBadRequest(fmt.Sprintf("upload %s exceeds the declared length of %d bytes", upload.ID, upload.Info.Length))
+	}
+	if offset < upload.Info.Length {
+		return offset, false, nil
+	}
+
+	if err := f.Sync(); err != nil {
+		return offset, false, errors.NewInternalError(err)
+	}
+	if err := verifyChecksum(key, upload.Info.SHA256); err != nil {
+		s.remove(key)
+		return 0, false, err
+	}
+	return offset, true, nil
+}
+
+// Open returns the content of a complete staged upload.
+func (s *uploadStager) Open(namespace, name string, upload *stagedUpload) (*os.File, error) {
+	return os.Open(s.key(namespace, name, upload.ID))
+}
+
+// Remove discards a staged upload.
+func (s *uploadStager) Remove(namespace, name string, upload *stagedUpload) {
+	key := s.key(namespace, name, upload.ID)
+	defer s.lockUpload(key)()
+	s.remove(key)
+}
+
+// remove discards a staged upload and releases its reservation. The caller must
+// hold the lock of the upload.
+func (s *uploadStager) remove(key string) {
+	for _, file := range []string{key, key + ".json"} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("unable to remove staged binary upload %s: %v", file, err)
+		}
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.reserved, key)
+}
+
+// checkInfo records the declared length and checksum on the first chunk and
+// rejects later chunks declaring different values. The declared length of a new
+// upload is reserved against the quota. A chunk resuming an upload that is not
+// staged here, because it expired or was started on another apiserver instance,
+// is rejected.
+func (s *uploadStager) checkInfo(key string, upload *stagedUpload) error {
+	info := upload.Info
+	data, err := os.ReadFile(key + ".json")
+	if os.IsNotExist(err) {
+		if upload.Offset != 0 {
+			return errors.NewConflict(buildapi.Resource("buildconfigs"), filepath.Base(filepath.Dir(key)), fmt.Errorf("upload %s is not staged on this apiserver, it expired or was started on another apiserver instance; restart the upload at offset 0", upload.ID))
+		}
+		if err := s.reserve(key, info.Length, true); err != nil {
+			return err
+		}
+		data, err = json.Marshal(info)
+		if err != nil {
+			return errors.NewInternalError(err)
+		}
+		if err := os.WriteFile(key+".json", data, 0600); err != nil {
+			return errors.NewInternalError(err)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	existing := stagedUploadInfo{}
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return errors.NewInternalError(err)
+	}
+	if existing != info {
+		return errors.NewBadRequest(fmt.Sprintf("upload %s was started with length %d and checksum %s", filepath.Base(key), existing.Length, existing.SHA256))
+	}
+	// uploads staged before a restart are accounted for once they are resumed
+	return s.reserve(key, existing.Length, false)
+}
+
+// pruneIfDue removes expired uploads at most once per pruneInterval, so that
+// chunks do not walk the staging directory.
+func (s *uploadStager) pruneIfDue() {
+	s.lock.Lock()
+	due := time.Since(s.lastPrune) >= s.pruneInterval()
+	if due {
+		s.lastPrune = time.Now()
+	}
+	s.lock.Unlock()
+	if due {
+		s.pruneExpired()
+	}
+}
+
+func (s *uploadStager) pruneInterval() time.Duration {
+	return s.ttl / 4
+}
+
+// pruneExpired removes staged uploads that have not been written to within the TTL.
+// Every upload is checked again under its lock, so that an upload receiving a
+// chunk is never removed.
+func (s *uploadStager) pruneExpired() {
+	var expired []string
+	cutoff := time.Now().Add(-s.ttl)
+	filepath.Walk(s.dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(file) == ".json" {
+			return nil
+		}
+		if info.ModTime().Before(cutoff) {
+			expired = append(expired, file)
+		}
+		return nil
+	})
+	for _, file := range expired {
+		func() {
+			defer s.lockUpload(file)()
+			info, err := os.Stat(file)
+			if err != nil || !info.ModTime().Before(time.Now().Add(-s.ttl)) {
+				return
+			}
+			klog.V(4).Infof("removing expired staged binary upload %s", file)
+			s.remove(file)
+		}()
+	}
+}
+
+func verifyChecksum(file, expected string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return errors.NewInternalError(err)
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return errors.NewBadRequest(fmt.Sprintf("staged upload checksum %s does not match the declared %s", actual, expected))
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package buildconfiginstantiate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+)
+
+func newStagedUpload(content string, offset int64) *stagedUpload {
+	sum := sha256.Sum256([]byte(content))
+	return &stagedUpload{
+		ID:     "upload-1",
+		Info:   stagedUploadInfo{Length: int64(len(content)), SHA256: hex.EncodeToString(sum[:])},
+		Offset: offset,
+	}
+}
+
+func TestParseStagedUpload(t *testing.T) {
+	sum := sha256.Sum256([]byte("content"))
+	checksum := hex.EncodeToString(sum[:])
+	testCases := map[string]struct {
+		headers  map[string]string
+		expected *stagedUpload
+		errText  string
+	}{
+		"no staging": {
+			headers: map[string]string{},
+		},
+		"valid": {
+			headers: map[string]string{UploadIDHeader: "upload-1", UploadLengthHeader: "7", UploadSHA256Header: checksum, UploadOffsetHeader: "3"},
+			expected: &stagedUpload{
+				ID:     "upload-1",
+				Info:   stagedUploadInfo{Length: 7, SHA256: checksum},
+				Offset: 3,
+			},
+		},
+		"invalid id": {
+			headers: map[string]string{UploadIDHeader: "../etc", UploadLengthHeader: "7", UploadSHA256Header: checksum},
+			errText: "invalid X-Upload-Id",
+		},
+		"missing length": {
+			headers: map[string]string{UploadIDHeader: "upload-1", UploadSHA256Header: checksum},
+			errText: "X-Upload-Length must be a positive number of bytes",
+		},
+		"invalid checksum": {
+			headers: map[string]string{UploadIDHeader: "upload-1", UploadLengthHeader: "7", UploadSHA256Header: "abc"},
+			errText: "X-Upload-Sha256 must be a hex encoded SHA-256 digest",
+		},
+		"negative offset": {
+			headers: map[string]string{UploadIDHeader: "upload-1", UploadLengthHeader: "7", UploadSHA256Header: checksum, UploadOffsetHeader: "-1"},
+			errText: "X-Upload-Offset must be a non-negative number of bytes",
+		},
+	}
+	for name, tc := range testCases {
+		req, _ := http.NewRequest("POST", "http://some.url", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		upload, err := parseStagedUpload(req)
+		if len(tc.errText) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.errText) {
+				t.Errorf("%s: expected error containing %q, got %v", name, tc.errText, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if (upload == nil) != (tc.expected == nil) || (upload != nil && *upload != *tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", name, tc.expected, upload)
+		}
+	}
+}
+
+func TestStageResumableUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	content := "0123456789"
+	offset, complete, err := stager.Stage("ns", "bc", newStagedUpload(content, 0), strings.NewReader(content[:4]))
+	if err != nil || complete || offset != 4 {
+		t.Fatalf("expected an incomplete upload at offset 4, got %d %t %v", offset, complete, err)
+	}
+
+	// a chunk at the wrong offset is rejected and reports the staged offset
+	offset, complete, err = stager.Stage("ns", "bc", newStagedUpload(content, 2), strings.NewReader(content[2:]))
+	if !errors.IsConflict(err) || complete || offset != 4 {
+		t.Fatalf("expected a conflict at offset 4, got %d %t %v", offset, complete, err)
+	}
+
+	offset, complete, err = stager.Stage("ns", "bc", newStagedUpload(content, 4), strings.NewReader(content[4:]))
+	if err != nil || !complete || offset != int64(len(content)) {
+		t.Fatalf("expected a complete upload, got %d %t %v", offset, complete, err)
+	}
+
+	f, err := stager.Open("ns", "bc", newStagedUpload(content, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil || string(data) != content {
+		t.Errorf("expected staged content %q, got %q (%v)", content, string(data), err)
+	}
+
+	stager.Remove("ns", "bc", newStagedUpload(content, 0))
+	if _, err := os.Stat(stager.key("ns", "bc", "upload-1")); !os.IsNotExist(err) {
+		t.Errorf("expected the staged upload to be removed, got %v", err)
+	}
+}
+
+func TestStageRejectsResumingUnknownUpload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	// the upload was started on another apiserver instance
+	offset, complete, err := stager.Stage("ns", "bc", newStagedUpload("0123456789", 4), strings.NewReader("456789"))
+	if !errors.IsConflict(err) || complete || offset != 0 || !strings.Contains(err.Error(), "restart the upload at offset 0") {
+		t.Fatalf("expected a conflict asking to restart the upload, got %d %t %v", offset, complete, err)
+	}
+	if _, err := os.Stat(stager.key("ns", "bc", "upload-1") + ".json"); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be staged, got %v", err)
+	}
+	if len(stager.reserved) != 0 {
+		t.Errorf("expected no quota to be reserved, got %v", stager.reserved)
+	}
+}
+
+func TestNewUploadStagerFromConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if stager, err := newUploadStagerFromConfig(openshiftcontrolplanev1.BinaryBuildUploadConfig{}); stager != nil || err != nil {
+		t.Errorf("expected staging to be disabled by default, got %v %v", stager, err)
+	}
+	if _, err := newUploadStagerFromConfig(openshiftcontrolplanev1.BinaryBuildUploadConfig{Directory: dir}); err == nil {
+		t.Errorf("expected a quota to be required")
+	}
+	stager, err := newUploadStagerFromConfig(openshiftcontrolplanev1.BinaryBuildUploadConfig{Directory: dir, QuotaBytes: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stager.dir != dir || stager.quota != 100 || stager.maxLength != 100 {
+		t.Errorf("unexpected stager %#v", stager)
+	}
+}
+
+func TestStageRejectsChecksumMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	upload := newStagedUpload("0123456789", 0)
+	_, complete, err := stager.Stage("ns", "bc", upload, strings.NewReader("9876543210"))
+	if !errors.IsBadRequest(err) || complete || !strings.Contains(err.Error(), "does not match the declared") {
+		t.Fatalf("expected a checksum mismatch, got %t %v", complete, err)
+	}
+	if _, err := os.Stat(stager.key("ns", "bc", upload.ID)); !os.IsNotExist(err) {
+		t.Errorf("expected the corrupt upload to be discarded, got %v", err)
+	}
+}
+
+func TestStageRejectsChangedDeclaration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	if _, _, err := stager.Stage("ns", "bc", newStagedUpload("0123456789", 0), strings.NewReader("0123")); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = stager.Stage("ns", "bc", newStagedUpload("0123456789abc", 4), strings.NewReader("456789abc"))
+	if !errors.IsBadRequest(err) || !strings.Contains(err.Error(), "was started with length "+strconv.Itoa(10)) {
+		t.Errorf("expected the changed declaration to be rejected, got %v", err)
+	}
+}
+
+func TestStageRejectsOversizedChunk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	_, _, err = stager.Stage("ns", "bc", newStagedUpload("0123", 0), strings.NewReader("0123456789"))
+	if !errors.IsBadRequest(err) || !strings.Contains(err.Error(), "exceeds the declared length") {
+		t.Errorf("expected the oversized upload to be rejected, got %v", err)
+	}
+}
+
+func TestStageEnforcesLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 8, 12)
+
+	if _, _, err := stager.Stage("ns", "bc", newStagedUpload("0123456789", 0), strings.NewReader("0123")); !errors.IsRequestEntityTooLargeError(err) {
+		t.Errorf("expected an upload above the maximum length to be rejected, got %v", err)
+	}
+
+	first := newStagedUpload("01234567", 0)
+	if _, _, err := stager.Stage("ns", "bc", first, strings.NewReader("0123")); err != nil {
+		t.Fatal(err)
+	}
+	second := newStagedUpload("abcdef", 0)
+	second.ID = "upload-2"
+	if _, _, err := stager.Stage("ns", "bc", second, strings.NewReader("abc")); !errors.IsTooManyRequests(err) {
+		t.Errorf("expected an upload above the quota to be rejected, got %v", err)
+	}
+
+	stager.Remove("ns", "bc", first)
+	if _, _, err := stager.Stage("ns", "bc", second, strings.NewReader("abc")); err != nil {
+		t.Errorf("expected the quota to be released once an upload is removed, got %v", err)
+	}
+	if len(stager.locks) != 0 {
+		t.Errorf("expected no upload locks to be retained, got %v", stager.locks)
+	}
+}
+
+func TestStagePrunesExpiredUploads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stager := newUploadStager(dir, time.Hour, 1024, 1024)
+
+	expired := newStagedUpload("0123456789", 0)
+	if _, _, err := stager.Stage("ns", "bc", expired, strings.NewReader("0123")); err != nil {
+		t.Fatal(err)
+	}
+	key := stager.key("ns", "bc", expired.ID)
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(key, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// pruning is not due yet
+	active := newStagedUpload("abcdef", 0)
+	active.ID = "upload-2"
+	if _, _, err := stager.Stage("ns", "bc", active, strings.NewReader("abc")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(key); err != nil {
+		t.Fatalf("expected the expired upload to be kept until pruning is due, got %v", err)
+	}
+
+	stager.lastPrune = time.Time{}
+	if _, _, err := stager.Stage("ns", "bc", active, strings.NewReader("")); err == nil || !errors.IsConflict(err) {
+		t.Fatalf("expected a conflict for a chunk at a stale offset, got %v", err)
+	}
+	if _, err := os.Stat(key); !os.IsNotExist(err) {
+		t.Errorf("expected the expired upload to be pruned, got %v", err)
+	}
+	if _, ok := stager.reserved[key]; ok {
+		t.Errorf("expected the reservation of the pruned upload to be released")
+	}
+	if _, err := os.Stat(stager.key("ns", "bc", active.ID)); err != nil {
+		t.Errorf("expected the active upload to be kept, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package builddiff
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+
+	"github.com/openshift/api/build"
+	buildv1 "github.com/openshift/api/build/v1"
+	buildtypedclient "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+	"github.com/openshift/library-go/pkg/authorization/authorizationutil"
+
+	"github.com/openshift/openshift-apiserver/pkg/api/diff"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	buildv1conversions "github.com/openshift/openshift-apiserver/pkg/build/apis/build/v1"
+	"github.com/openshift/openshift-apiserver/pkg/build/apis/build/validation"
+)
+
+// Generator generates the build a BuildConfig would produce now.
+type Generator interface {
+	Generate(ctx context.Context, name string) (*buildv1.Build, error)
+}
+
+// DiffREST implements builds/diff, which returns the changes from a build to
+// another build of its namespace, or to the build its BuildConfig would produce now.
+type DiffREST struct {
+	BuildClient buildtypedclient.BuildsGetter
+	Generator   Generator
+	SARClient   authorizationclient.SubjectAccessReviewInterface
+}
+
+var _ rest.GetterWithOptions = &DiffREST{}
+var _ rest.StorageMetadata = &DiffREST{}
+
+// NewREST creates the storage serving build diffs.
+func NewREST(buildClient buildtypedclient.BuildsGetter, generator Generator, sarClient authorizationclient.SubjectAccessReviewInterface) *DiffREST {
+	return &DiffREST{BuildClient: buildClient, Generator: generator, SARClient: sarClient}
+}
+
+// New returns a new BuildDiff.
+func (r *DiffREST) New() runtime.Object {
+	return &buildapi.BuildDiff{}
+}
+
+func (r *DiffREST) Destroy() {}
+
+// Get returns the diff of the named build.
+func (r *DiffREST) Get(ctx context.Context, name string, opts runtime.Object) (runtime.Object, error) {
+	diffOpts, ok := opts.(*buildapi.BuildDiffOptions)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("did not get an expected options: %T", opts))
+	}
+	if errs := validation.ValidateBuildDiffOptions(diffOpts); len(errs) > 0 {
+		return nil, errors.NewInvalid(build.Kind("BuildDiffOptions"), "", errs)
+	}
+
+	namespace := apirequest.NamespaceValue(ctx)
+	from, err := r.BuildClient.Builds(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := &buildv1.BuildDiff{
+		ObjectMeta: metav1.ObjectMeta{Namespace: from.Namespace, Name: from.Name},
+		From:       corev1.ObjectReference{Kind: "Build", Namespace: from.Namespace, Name: from.Name},
+		Changes:    []buildv1.BuildDiffChange{},
+	}
+
+	var to *buildv1.Build
+	if len(diffOpts.Build) > 0 {
+		if to, err = r.BuildClient.Builds(namespace).Get(ctx, diffOpts.Build, metav1.GetOptions{}); err != nil {
+			return nil, err
+		}
+		result.To = corev1.ObjectReference{Kind: "Build", Namespace: to.Namespace, Name: to.Name}
+	} else {
+		if from.Status.Config == nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("build %s/%s was not created from a BuildConfig, specify a build to compare with using the %q parameter", from.Namespace, from.Name, "build"))
+		}
+		configName := from.Status.Config.Name
+		// generating the build resolves the images and secrets of the BuildConfig
+		// with the privileged clients of the generator, which is only granted to
+		// the users who may start the BuildConfig.
+		if err := r.checkInstantiate(ctx, namespace, configName); err != nil {
+			return nil, err
+		}
+		if to, err = r.Generator.Generate(ctx, configName); err != nil {
+			return nil, err
+		}
+		result.To = corev1.ObjectReference{Kind: "BuildConfig", Namespace: namespace, Name: configName}
+	}
+
+	for _, change := range diff.Builds(from, to) {
+		result.Changes = append(result.Changes, buildv1.BuildDiffChange{
+			Category: string(change.Category),
+			Field:    change.Field,
+			From:     change.From,
+			To:       change.To,
+		})
+	}
+
+	internalResult := &buildapi.BuildDiff{}
+	if err := buildv1conversions.Convert_v1_BuildDiff_To_build_BuildDiff(result, internalResult, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	return internalResult, nil
+}
+
+// checkInstantiate returns a forbidden error unless the user of ctx may
+// instantiate the named BuildConfig.
+func (r *DiffREST) checkInstantiate(ctx context.Context, namespace, name string) error {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return errors.NewForbidden(buildapi.Resource("buildconfigs/instantiate"), name, fmt.Errorf("no user in the request"))
+	}
+	sar := authorizationutil.AddUserToSAR(user, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "create",
+				Group:       buildv1.GroupName,
+				Resource:    "buildconfigs",
+				Subresource: "instantiate",
+				Name:        name,
+			},
+		},
+	})
+	resp, err := r.SARClient.Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	if !resp.Status.Allowed {
+		return errors.NewForbidden(buildapi.Resource("buildconfigs/instantiate"), name, fmt.Errorf("comparing with the build the BuildConfig would produce requires permission to instantiate it"))
+	}
+	return nil
+}
+
+// NewGetOptions returns the options of the diff.
+func (r *DiffREST) NewGetOptions() (runtime.Object, bool, string) {
+	return &buildapi.BuildDiffOptions{}, false, ""
+}
+
+func (r *DiffREST) ProducesObject(verb string) interface{} {
+	// for documentation purposes
+	return buildv1.BuildDiff{}
+}
+
+func (r *DiffREST) ProducesMIMETypes(verb string) []string {
+	return nil // no additional mime types
+}
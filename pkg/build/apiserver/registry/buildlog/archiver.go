@@ -0,0 +1,224 @@
+package buildlog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubetypedclient "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildtypedclient "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/logarchive"
+)
+
+const (
+	maxArchiveRetries = 5
+	// archivePruneInterval is how often logs older than the retention are
+	// removed from the archive.
+	archivePruneInterval = time.Hour
+)
+
+// archiveKey returns the key the log of build is archived under. The uid keeps
+// the log of a deleted build from being served for a new build of the same name.
+func archiveKey(build *buildv1.Build) string {
+	return fmt.Sprintf("%s/%s/%s.log", build.Namespace, build.Name, build.UID)
+}
+
+// Archiver copies the logs of builds into an archive when they complete, so that
+// they can be served after the build pod is gone. The Archiver is run by a single
+// apiserver instance at a time; on start it also archives the logs of completed
+// builds which are missing from the archive, such as builds which completed while
+// no instance was running it.
+type Archiver struct {
+	BuildClient buildtypedclient.BuildsGetter
+	PodClient   kubetypedclient.PodsGetter
+	Archive     logarchive.Archive
+	// Retention is how long archived logs are kept. Zero keeps them forever.
+	Retention time.Duration
+
+	queue workqueue.TypedRateLimitingInterface[string]
+	store cache.Store
+}
+
+// NewArchiver creates an Archiver storing build logs in archive for retention.
+func NewArchiver(buildClient buildtypedclient.BuildsGetter, podClient kubetypedclient.PodsGetter, archive logarchive.Archive, retention time.Duration) *Archiver {
+	return &Archiver{
+		BuildClient: buildClient,
+		PodClient:   podClient,
+		Archive:     archive,
+		Retention:   retention,
+	}
+}
+
+// Run archives the logs of the builds observed by informer until stopCh is closed.
+func (a *Archiver) Run(informer cache.SharedIndexInformer, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+
+	a.queue = workqueue.NewTypedRateLimitingQueueWithConfig(
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+		workqueue.TypedRateLimitingQueueConfig[string]{Name: "buildlogarchiver"},
+	)
+	defer a.queue.ShutDown()
+	a.store = informer.GetStore()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if build := obj.(*buildv1.Build); isCompleted(build) {
+				a.enqueue(build)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBuild, newBuild := oldObj.(*buildv1.Build), newObj.(*buildv1.Build)
+			if !isCompleted(oldBuild) && isCompleted(newBuild) {
+				a.enqueue(newBuild)
+			}
+		},
+	})
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return
+	}
+
+	klog.Infof("Starting build log archiver")
+	go wait.Until(a.worker, time.Second, stopCh)
+	if a.Retention > 0 {
+		go wait.Until(a.prune, archivePruneInterval, stopCh)
+	}
+	<-stopCh
+	klog.Infof("Shutting down build log archiver")
+}
+
+func (a *Archiver) enqueue(build *buildv1.Build) {
+	if key, err := cache.MetaNamespaceKeyFunc(build); err == nil {
+		a.queue.Add(key)
+	}
+}
+
+func (a *Archiver) prune() {
+	if err := a.Archive.Prune(context.TODO(), time.Now().Add(-a.Retention)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("unable to prune the build log archive: %v", err))
+	}
+}
+
+func (a *Archiver) worker() {
+	for a.processNextItem() {
+	}
+}
+
+func (a *Archiver) processNextItem() bool {
+	key, quit := a.queue.Get()
+	if quit {
+		return false
+	}
+	defer a.queue.Done(key)
+
+	obj, exists, err := a.store.GetByKey(key)
+	if err == nil && exists {
+		err = a.archiveIfMissing(context.TODO(), obj.(*buildv1.Build))
+	}
+	if err == nil {
+		a.queue.Forget(key)
+		return true
+	}
+	if a.queue.NumRequeues(key) < maxArchiveRetries {
+		klog.V(4).Infof("retrying archiving the logs of build %s: %v", key, err)
+		a.queue.AddRateLimited(key)
+		return true
+	}
+	utilruntime.HandleError(fmt.Errorf("unable to archive the logs of build %s: %v", key, err))
+	a.queue.Forget(key)
+	return true
+}
+
+// archiveIfMissing archives the logs of build unless they are archived already.
+// Builds whose pod is gone have no logs left to archive.
+func (a *Archiver) archiveIfMissing(ctx context.Context, build *buildv1.Build) error {
+	archived, err := a.Archive.Get(ctx, archiveKey(build))
+	switch {
+	case err == nil:
+		archived.Close()
+		return nil
+	case err != logarchive.ErrNotFound:
+		return err
+	}
+	err = a.ArchiveBuildLogs(ctx, build)
+	if kerrors.IsNotFound(err) {
+		klog.V(4).Infof("not archiving the logs of build %s/%s, the build pod is gone", build.Namespace, build.Name)
+		return nil
+	}
+	return err
+}
+
+// ArchiveBuildLogs stores the logs of the init containers and the build container
+// of the build pod in the archive, in the order they ran.
+func (a *Archiver) ArchiveBuildLogs(ctx context.Context, build *buildv1.Build) error {
+	buildPodName := getBuildPodName(build)
+	pod, err := a.PodClient.Pods(build.Namespace).Get(ctx, buildPodName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(a.copyPodLogs(ctx, pod, writer))
+	}()
+	err = a.Archive.Put(ctx, archiveKey(build), reader)
+	reader.Close()
+	if err != nil {
+		return err
+	}
+	klog.V(4).Infof("archived the logs of build %s/%s", build.Namespace, build.Name)
+	return nil
+}
+
+func (a *Archiver) copyPodLogs(ctx context.Context, pod *corev1.Pod, writer io.Writer) error {
+	for _, status := range pod.Status.InitContainerStatuses {
+		// init containers which never ran have no logs
+		if status.State.Waiting != nil {
+			continue
+		}
+		if err := a.copyContainerLogs(ctx, pod, status.Name, writer); err != nil {
+			return err
+		}
+		// no additional init containers nor the build container ran after a failure
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return nil
+		}
+	}
+	container := selectBuilderContainer(pod.Spec.Containers)
+	if len(container) == 0 {
+		return nil
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container && status.State.Waiting != nil {
+			return nil
+		}
+	}
+	return a.copyContainerLogs(ctx, pod, container, writer)
+}
+
+func (a *Archiver) copyContainerLogs(ctx context.Context, pod *corev1.Pod, container string, writer io.Writer) error {
+	stream, err := a.PodClient.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	_, err = io.Copy(writer, stream)
+	return err
+}
+
+func isCompleted(build *buildv1.Build) bool {
+	switch build.Status.Phase {
+	case buildv1.BuildPhaseComplete, buildv1.BuildPhaseFailed, buildv1.BuildPhaseCancelled, buildv1.BuildPhaseError:
+		return true
+	}
+	return false
+}
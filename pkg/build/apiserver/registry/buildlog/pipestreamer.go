@@ -19,10 +19,13 @@ package buildlog
 import (
 	"context"
 	"io"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/registry/rest"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
 )
 
 // PipeStreamer is a resource that streams the contents of a particular
@@ -32,6 +35,28 @@ type PipeStreamer struct {
 	Out         *io.PipeReader
 	Flush       bool
 	ContentType string
+
+	// format is the format requested in the build log options. When it is not
+	// set, the stream is structured if the client accepts JSON lines.
+	format buildapi.BuildLogFormat
+	// requested is closed by InputStream, after which structured reports
+	// whether the stream is returned as JSON lines.
+	requested  chan struct{}
+	once       sync.Once
+	structured bool
+}
+
+// NewPipeStreamer returns a PipeStreamer streaming the content written to its
+// In pipe.
+func NewPipeStreamer(flush bool, contentType string) *PipeStreamer {
+	reader, writer := io.Pipe()
+	return &PipeStreamer{
+		In:          writer,
+		Out:         reader,
+		Flush:       flush,
+		ContentType: contentType,
+		requested:   make(chan struct{}),
+	}
 }
 
 // a PipeStreamer must implement a rest.ResourceStreamer
@@ -49,5 +74,23 @@ func (obj *PipeStreamer) DeepCopyObject() runtime.Object {
 func (s *PipeStreamer) InputStream(ctx context.Context, apiVersion, acceptHeader string) (stream io.ReadCloser, flush bool, contentType string, err error) {
 	flush = s.Flush
 	stream = s.Out
+	if s.format == buildapi.BuildLogFormatJSONLines || (len(s.format) == 0 && acceptsJSONLines(acceptHeader)) {
+		s.structured = true
+		contentType = JSONLinesContentType
+	}
+	if s.requested != nil {
+		s.once.Do(func() { close(s.requested) })
+	}
 	return
 }
+
+// waitForRequest blocks until the stream is requested and returns whether it is
+// returned as JSON lines, or false if ctx is done first.
+func (s *PipeStreamer) waitForRequest(ctx context.Context) (structured bool, ok bool) {
+	select {
+	case <-s.requested:
+		return s.structured, true
+	case <-ctx.Done():
+		return false, false
+	}
+}
@@ -31,6 +31,7 @@ import (
 	buildinternalhelpers "github.com/openshift/openshift-apiserver/pkg/build/apis/build/internal_helpers"
 	"github.com/openshift/openshift-apiserver/pkg/build/apis/build/validation"
 	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/apiserverbuildutil"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/logarchive"
 	buildwait "github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/wait"
 )
 
@@ -40,6 +41,9 @@ type REST struct {
 	PodClient   kubetypedclient.PodsGetter
 	PodLister   corev1listers.PodLister
 	Timeout     time.Duration
+	// Archive holds the logs of completed builds whose pods may be gone. It is
+	// optional.
+	Archive logarchive.Archive
 
 	// for unit testing
 	getSimpleLogsFn func(ctx context.Context, podNamespace, podName string, logOpts *kapi.PodLogOptions) (runtime.Object, error)
@@ -50,12 +54,13 @@ const defaultTimeout time.Duration = 30 * time.Second
 // NewREST creates a new REST for BuildLog
 // Takes build registry and pod client to get necessary attributes to assemble
 // URL to which the request shall be redirected in order to get build logs.
-func NewREST(buildClient buildtypedclient.BuildsGetter, podClient kubetypedclient.PodsGetter, podLister corev1listers.PodLister) *REST {
+func NewREST(buildClient buildtypedclient.BuildsGetter, podClient kubetypedclient.PodsGetter, podLister corev1listers.PodLister, archive logarchive.Archive) *REST {
 	r := &REST{
 		BuildClient: buildClient,
 		PodClient:   podClient,
 		PodLister:   podLister,
 		Timeout:     defaultTimeout,
+		Archive:     archive,
 	}
 	r.getSimpleLogsFn = r.getSimpleLogs
 	return r
@@ -106,6 +111,12 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 			return nil, errors.NewBadRequest(fmt.Sprintf("unable to wait for build %s to run: %v", build.Name, err))
 		}
 		switch latest.Status.Phase {
+		case buildv1.BuildPhaseError, buildv1.BuildPhaseCancelled:
+			if archived, ok, err := r.getArchivedLogs(ctx, latest, buildLogOpts); ok || err != nil {
+				return archived, err
+			}
+		}
+		switch latest.Status.Phase {
 		case buildv1.BuildPhaseError:
 			return nil, errors.NewBadRequest(fmt.Sprintf("build %s encountered an error: %s", build.Name, apiserverbuildutil.NoBuildLogsMessage))
 		case buildv1.BuildPhaseCancelled:
@@ -117,10 +128,16 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 
 	// The build was cancelled
 	case buildv1.BuildPhaseCancelled:
+		if archived, ok, err := r.getArchivedLogs(ctx, build, buildLogOpts); ok || err != nil {
+			return archived, err
+		}
 		return nil, errors.NewBadRequest(fmt.Sprintf("build %s was cancelled. %s", build.Name, apiserverbuildutil.NoBuildLogsMessage))
 
 	// An error occurred launching the build, return an error
 	case buildv1.BuildPhaseError:
+		if archived, ok, err := r.getArchivedLogs(ctx, build, buildLogOpts); ok || err != nil {
+			return archived, err
+		}
 		return nil, errors.NewBadRequest(fmt.Sprintf("build %s is in an error state. %s", build.Name, apiserverbuildutil.NoBuildLogsMessage))
 	}
 
@@ -131,27 +148,37 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 	// error out now.
 	buildPod, err := r.PodLister.Pods(build.Namespace).Get(buildPodName)
 	if err != nil {
+		// the pod of a completed build may have been deleted, serve the archived logs instead
+		if errors.IsNotFound(err) {
+			if archived, ok, archiveErr := r.getArchivedLogs(ctx, build, buildLogOpts); ok || archiveErr != nil {
+				return archived, archiveErr
+			}
+		}
 		return nil, errors.NewBadRequest(err.Error())
 	}
 
 	// check for old style builds with a single container/no initcontainers
 	// and handle them w/ the old logging code.
 	if len(buildPod.Spec.InitContainers) == 0 {
+		if buildLogOpts.Format == buildapi.BuildLogFormatJSONLines {
+			return nil, errors.NewBadRequest(fmt.Sprintf("the log of build %s is only available as %s", build.Name, buildapi.BuildLogFormatText))
+		}
 		logOpts := buildinternalhelpers.BuildToPodLogOptions(buildLogOpts)
 		return r.getSimpleLogsFn(ctx, build.Namespace, buildPodName, logOpts)
 	}
 
 	// new style builds w/ init containers from here out.
 
-	// we'll funnel all the initcontainer+main container logs into this single stream
-	reader, writer := io.Pipe()
-	pipeStreamer := PipeStreamer{
-		In:          writer,
-		Out:         reader,
-		Flush:       buildLogOpts.Follow,
-		ContentType: "text/plain",
+	// the container option selects the containers, stages or steps to stream
+	filter := newContainerFilter(buildLogOpts.Container)
+	if err := filter.validate(buildPod); err != nil {
+		return nil, errors.NewBadRequest(err.Error())
 	}
 
+	// we'll funnel all the initcontainer+main container logs into this single stream
+	pipeStreamer := NewPipeStreamer(buildLogOpts.Follow, "text/plain")
+	pipeStreamer.format = buildLogOpts.Format
+
 	// background thread will poll the init containers until they are running/terminated
 	// and then stream the logs from them into the pipe, one by one, before streaming
 	// the primary container logs into the pipe.  Any errors that occur will result
@@ -159,6 +186,35 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 	go func() {
 		defer pipeStreamer.In.Close()
 
+		// structured logs have every line tagged with its container, build stage
+		// and step. Clients may ask for them in the Accept header, which is only
+		// known once the stream is requested.
+		structured, ok := pipeStreamer.waitForRequest(ctx)
+		if !ok {
+			return
+		}
+		streamContainer := func(containerLogOpts *kapi.PodLogOptions) error {
+			if !filter.selects(containerLogOpts.Container) {
+				return nil
+			}
+			if !structured {
+				return r.pipeLogs(ctx, build.Namespace, buildPodName, containerLogOpts, pipeStreamer.In)
+			}
+			containerLogOpts.Timestamps = true
+			lines := newLineWriter(pipeStreamer.In, containerLogOpts.Container, build.Status.Stages)
+			if err := r.pipeLogs(ctx, build.Namespace, buildPodName, containerLogOpts, lines); err != nil {
+				return err
+			}
+			return lines.Close()
+		}
+		reportError := func(message string) {
+			if structured {
+				newLineWriter(pipeStreamer.In, "", nil).writeLine(message)
+				return
+			}
+			pipeStreamer.In.Write([]byte(message))
+		}
+
 		// containers that we've successfully streamed the logs for and don't need
 		// to worry about it anymore.
 		doneWithContainer := map[string]bool{}
@@ -191,7 +247,7 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 				s := fmt.Sprintf("error retrieving build pod %s/%s : %v", build.Namespace, buildPodName, err.Error())
 				// we're sending the error message as the log output so the user at least sees some indication of why
 				// they didn't get the logs they expected.
-				pipeStreamer.In.Write([]byte(s))
+				reportError(s)
 				return
 			}
 
@@ -236,7 +292,7 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 					containerLogOpts.Follow = false
 				}
 
-				if err := r.pipeLogs(ctx, build.Namespace, buildPodName, containerLogOpts, pipeStreamer.In); err != nil {
+				if err := streamContainer(containerLogOpts); err != nil {
 					klog.Errorf("error: failed to stream logs for build pod: %s/%s container: %s, due to: %v", build.Namespace, buildPodName, status.Name, err)
 					return
 				}
@@ -279,7 +335,7 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 				buildPod, err = r.PodLister.Pods(build.Namespace).Get(buildPodName)
 				if err != nil {
 					s := fmt.Sprintf("error while getting build logs, could not retrieve build pod %s/%s : %v", build.Namespace, buildPodName, err.Error())
-					pipeStreamer.In.Write([]byte(s))
+					reportError(s)
 					return false, err
 				}
 				// we can get logs from a pod in any state other than pending.
@@ -304,14 +360,14 @@ func (r *REST) Get(ctx context.Context, name string, opts runtime.Object) (runti
 				containerLogOpts.Follow = false
 			}
 
-			if err := r.pipeLogs(ctx, build.Namespace, buildPodName, containerLogOpts, pipeStreamer.In); err != nil {
+			if err := streamContainer(containerLogOpts); err != nil {
 				klog.Errorf("error: failed to stream logs for build pod: %s/%s due to: %v", build.Namespace, buildPodName, err)
 				return
 			}
 		}
 	}()
 
-	return &pipeStreamer, nil
+	return pipeStreamer, nil
 }
 
 // BuildNameForConfigVersion returns the name of the version-th build
@@ -392,6 +448,38 @@ func (r *REST) getSimpleLogs(ctx context.Context, podNamespace, podName string,
 	}, nil
 }
 
+// getArchivedLogs returns the archived logs of build, and false if there are none.
+// Archived logs are complete, so only the limitBytes option applies to them.
+func (r *REST) getArchivedLogs(ctx context.Context, build *buildv1.Build, buildLogOpts *buildapi.BuildLogOptions) (runtime.Object, bool, error) {
+	if r.Archive == nil {
+		return nil, false, nil
+	}
+	archived, err := r.Archive.Get(ctx, archiveKey(build))
+	if err == logarchive.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.NewInternalError(fmt.Errorf("unable to read the archived logs of build %s: %v", build.Name, err))
+	}
+	if buildLogOpts.Format == buildapi.BuildLogFormatJSONLines {
+		archived.Close()
+		return nil, false, errors.NewBadRequest(fmt.Sprintf("the archived log of build %s is only available as %s", build.Name, buildapi.BuildLogFormatText))
+	}
+	klog.V(4).Infof("serving archived logs for build %s/%s", build.Namespace, build.Name)
+	if buildLogOpts.LimitBytes != nil {
+		archived = &limitedReadCloser{Reader: io.LimitReader(archived, *buildLogOpts.LimitBytes), Closer: archived}
+	}
+	return &apiserverrest.PassThroughStreamer{
+		In:          archived,
+		ContentType: "text/plain",
+	}, true, nil
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
 // versionForBuild returns the version from the provided build name.
 // If no version can be found, 0 is returned to indicate no version.
 func versionForBuild(build *buildv1.Build) int {
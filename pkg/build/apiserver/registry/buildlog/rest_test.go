@@ -3,6 +3,8 @@ package buildlog
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
@@ -22,6 +24,7 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	buildfakeclient "github.com/openshift/client-go/build/clientset/versioned/fake"
 	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/logarchive"
 )
 
 func newPodClient() *fake.Clientset {
@@ -369,3 +372,180 @@ func TestPreviousBuildLogs(t *testing.T) {
 		t.Errorf("expected %v, actual %v", e, a)
 	}
 }
+
+func TestArchivedBuildLogs(t *testing.T) {
+	informersCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "buildlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	archive, err := logarchive.NewDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pods of these builds are gone
+	complete := mockBuild(buildv1.BuildPhaseComplete, "complete", 1)
+	cancelled := mockBuild(buildv1.BuildPhaseCancelled, "cancelled", 1)
+	notArchived := mockBuild(buildv1.BuildPhaseFailed, "not-archived", 1)
+	for _, build := range []*buildv1.Build{complete, cancelled} {
+		if err := archive.Put(context.Background(), archiveKey(build), strings.NewReader("archived "+build.Name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := newPodClient()
+	podInformer := fakeCoreV1PodInformer(client, informersCtx.Done())
+	buildClient := buildfakeclient.NewSimpleClientset(complete, cancelled, notArchived)
+	storage := NewREST(buildClient.BuildV1(), client.CoreV1(), podInformer.Lister(), archive)
+
+	ctx := apirequest.NewDefaultContext()
+	limit := int64(8)
+	testCases := map[string]struct {
+		build    string
+		opts     *buildapi.BuildLogOptions
+		expected string
+		errText  string
+	}{
+		"complete build": {
+			build:    "complete",
+			opts:     &buildapi.BuildLogOptions{},
+			expected: "archived complete",
+		},
+		"cancelled build": {
+			build:    "cancelled",
+			opts:     &buildapi.BuildLogOptions{},
+			expected: "archived cancelled",
+		},
+		"limit bytes": {
+			build:    "complete",
+			opts:     &buildapi.BuildLogOptions{LimitBytes: &limit},
+			expected: "archived",
+		},
+		"not archived": {
+			build:   "not-archived",
+			opts:    &buildapi.BuildLogOptions{},
+			errText: `pod "not-archived-build" not found`,
+		},
+	}
+	for name, tc := range testCases {
+		obj, err := storage.Get(ctx, tc.build, tc.opts)
+		if len(tc.errText) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.errText) {
+				t.Errorf("%s: expected error containing %q, got %v", name, tc.errText, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		stream, _, _, err := obj.(rest.ResourceStreamer).InputStream(ctx, "", "")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(stream)
+		stream.Close()
+		if err != nil || string(data) != tc.expected {
+			t.Errorf("%s: expected %q, got %q (%v)", name, tc.expected, string(data), err)
+		}
+	}
+}
+
+func TestArchiveBuildLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	archive, err := logarchive.NewDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		initStatuses []corev1.ContainerStatus
+		expected     string
+	}{
+		"all containers ran": {
+			initStatuses: []corev1.ContainerStatus{
+				{Name: "git-clone", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+				{Name: "manage-dockerfile", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+			},
+			// the fake client returns "fake logs" for every container
+			expected: "fake logsfake logsfake logs",
+		},
+		"init container failed": {
+			initStatuses: []corev1.ContainerStatus{
+				{Name: "git-clone", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+				{Name: "manage-dockerfile", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+			},
+			expected: "fake logs",
+		},
+	}
+	for name, tc := range testCases {
+		build := mockBuild(buildv1.BuildPhaseFailed, "bc-1", 1)
+		pod := mockPod(corev1.PodFailed, "bc-1-build")
+		pod.Spec.Containers[0].Name = "sti-build"
+		pod.Status.InitContainerStatuses = tc.initStatuses
+		archiver := NewArchiver(buildfakeclient.NewSimpleClientset(build).BuildV1(), fake.NewSimpleClientset(pod).CoreV1(), archive, 0)
+
+		if err := archiver.ArchiveBuildLogs(context.Background(), build); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		r, err := archive.Get(context.Background(), archiveKey(build))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		data, _ := ioutil.ReadAll(r)
+		r.Close()
+		if string(data) != tc.expected {
+			t.Errorf("%s: expected %q, got %q", name, tc.expected, string(data))
+		}
+	}
+}
+
+func TestArchiveIfMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buildlogs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	archive, err := logarchive.NewDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := mockBuild(buildv1.BuildPhaseComplete, "bc-1", 1)
+	archiver := NewArchiver(buildfakeclient.NewSimpleClientset(build).BuildV1(), fake.NewSimpleClientset().CoreV1(), archive, 0)
+	if err := archiver.archiveIfMissing(context.Background(), build); err != nil {
+		t.Errorf("expected a build without a pod to be skipped, got %v", err)
+	}
+	if _, err := archive.Get(context.Background(), archiveKey(build)); err != logarchive.ErrNotFound {
+		t.Errorf("expected nothing to be archived, got %v", err)
+	}
+
+	if err := archive.Put(context.Background(), archiveKey(build), strings.NewReader("archived logs")); err != nil {
+		t.Fatal(err)
+	}
+	pod := mockPod(corev1.PodSucceeded, "bc-1-build")
+	archiver = NewArchiver(buildfakeclient.NewSimpleClientset(build).BuildV1(), fake.NewSimpleClientset(pod).CoreV1(), archive, 0)
+	if err := archiver.archiveIfMissing(context.Background(), build); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, err := archive.Get(context.Background(), archiveKey(build))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(r)
+	r.Close()
+	if string(data) != "archived logs" {
+		t.Errorf("expected the archived logs to be kept, got %q", string(data))
+	}
+}
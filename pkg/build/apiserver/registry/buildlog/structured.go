@@ -0,0 +1,206 @@
+package buildlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/apiserverbuildutil"
+)
+
+// Structured build logs are requested by setting the format option of
+// BuildLogOptions to JSONLines, or by accepting one of these media types when
+// the format is not set. Every line of the log is then returned as a JSON
+// encoded LogLine.
+const (
+	JSONLinesContentType = "application/jsonl"
+	NDJSONContentType    = "application/x-ndjson"
+)
+
+// LogLine is a single line of a structured build log.
+type LogLine struct {
+	// Container is the build pod container which wrote the line.
+	Container string `json:"container"`
+	// Stage and Step are the names of the build stage and step the container
+	// performs, as reported in the build status.
+	Stage buildapi.StageName `json:"stage,omitempty"`
+	Step  buildapi.StepName  `json:"step,omitempty"`
+	// Timestamp is when the line was written, in RFC3339Nano format.
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
+
+type containerStep struct {
+	stage buildapi.StageName
+	step  buildapi.StepName
+}
+
+// containerSteps maps the containers of a build pod to the build stage and step
+// they perform. It is only used for lines written outside of the stages and steps
+// reported in the build status, which the builder records as the build completes:
+// while a build is running, the lines of the builder container, which also pulls
+// the builder image and pushes the output image, are all tagged with StageBuild.
+var containerSteps = map[string]containerStep{
+	apiserverbuildutil.GitCloneContainer:            {stage: buildapi.StageFetchInputs, step: buildapi.StepFetchGitSource},
+	apiserverbuildutil.ExtractImageContentContainer: {stage: buildapi.StageFetchInputs, step: buildapi.StepPullInputImage},
+	apiserverbuildutil.ManageDockerfileContainer:    {stage: buildapi.StageFetchInputs},
+	apiserverbuildutil.StiBuild:                     {stage: buildapi.StageBuild},
+	apiserverbuildutil.DockerBuild:                  {stage: buildapi.StageBuild, step: buildapi.StepDockerBuild},
+	apiserverbuildutil.CustomBuild:                  {stage: buildapi.StageBuild},
+}
+
+// acceptsJSONLines returns true if the Accept header asks for structured logs.
+func acceptsJSONLines(acceptHeader string) bool {
+	for _, accepted := range strings.Split(acceptHeader, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		if mediaType == JSONLinesContentType || mediaType == NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// containerFilter selects the build pod containers to return logs for. The
+// container option of BuildLogOptions is a comma separated list of container,
+// stage or step names; an empty option selects every container.
+type containerFilter sets.Set[string]
+
+func newContainerFilter(container string) containerFilter {
+	filter := containerFilter{}
+	for _, name := range strings.Split(container, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			filter[name] = sets.Empty{}
+		}
+	}
+	return filter
+}
+
+func (f containerFilter) selects(container string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	step := containerSteps[container]
+	return sets.Set[string](f).HasAny(container, string(step.stage), string(step.step))
+}
+
+// validate returns an error if a name in the filter matches none of the
+// containers of pod.
+func (f containerFilter) validate(pod *corev1.Pod) error {
+	valid := sets.New[string]()
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		valid.Insert(c.Name)
+		if step, ok := containerSteps[c.Name]; ok {
+			valid.Insert(string(step.stage))
+			if len(step.step) > 0 {
+				valid.Insert(string(step.step))
+			}
+		}
+	}
+	if unknown := sets.Set[string](f).Difference(valid); unknown.Len() > 0 {
+		return fmt.Errorf("unknown build log container %s, expected one of %s", strings.Join(sets.List(unknown), ", "), strings.Join(sets.List(valid), ", "))
+	}
+	return nil
+}
+
+// stageAt returns the stage and step of stages running at t, or false if none
+// was running.
+func stageAt(stages []buildv1.StageInfo, t time.Time) (containerStep, bool) {
+	for _, stage := range stages {
+		if !within(t, stage.StartTime, stage.DurationMilliseconds) {
+			continue
+		}
+		found := containerStep{stage: buildapi.StageName(stage.Name)}
+		for _, step := range stage.Steps {
+			if within(t, step.StartTime, step.DurationMilliseconds) {
+				found.step = buildapi.StepName(step.Name)
+				break
+			}
+		}
+		return found, true
+	}
+	return containerStep{}, false
+}
+
+func within(t time.Time, start metav1.Time, durationMilliseconds int64) bool {
+	if start.IsZero() {
+		return false
+	}
+	// the reported times have a precision of one second
+	end := start.Add(time.Duration(durationMilliseconds)*time.Millisecond + time.Second)
+	return !t.Before(start.Time) && t.Before(end)
+}
+
+// lineWriter encodes the log of a container as JSON lines. The log must be
+// requested with timestamps, which are moved into the Timestamp field and
+// matched against the stages of the build.
+type lineWriter struct {
+	out     io.Writer
+	line    LogLine
+	stages  []buildv1.StageInfo
+	partial []byte
+}
+
+func newLineWriter(out io.Writer, container string, stages []buildv1.StageInfo) *lineWriter {
+	step := containerSteps[container]
+	return &lineWriter{
+		out:    out,
+		line:   LogLine{Container: container, Stage: step.stage, Step: step.step},
+		stages: stages,
+	}
+}
+
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.partial = append(w.partial, data...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(data), nil
+		}
+		if err := w.writeLine(string(w.partial[:i])); err != nil {
+			return 0, err
+		}
+		w.partial = w.partial[i+1:]
+	}
+}
+
+// Close writes the last line of the log if it was not terminated by a newline.
+func (w *lineWriter) Close() error {
+	if len(w.partial) == 0 {
+		return nil
+	}
+	err := w.writeLine(string(w.partial))
+	w.partial = nil
+	return err
+}
+
+func (w *lineWriter) writeLine(text string) error {
+	line := w.line
+	line.Message = text
+	if i := strings.IndexByte(text, ' '); i > 0 {
+		if timestamp, err := time.Parse(time.RFC3339Nano, text[:i]); err == nil {
+			line.Timestamp, line.Message = text[:i], text[i+1:]
+			if step, ok := stageAt(w.stages, timestamp); ok {
+				line.Stage, line.Step = step.stage, step.step
+			}
+		}
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(append(data, '\n'))
+	return err
+}
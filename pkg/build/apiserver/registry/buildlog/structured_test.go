@@ -0,0 +1,199 @@
+package buildlog
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildfakeclient "github.com/openshift/client-go/build/clientset/versioned/fake"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+)
+
+func TestAcceptsJSONLines(t *testing.T) {
+	testCases := map[string]bool{
+		"":                                 false,
+		"application/json, */*":            false,
+		"application/jsonl":                true,
+		"text/plain, application/x-ndjson": true,
+		"application/jsonl; charset=utf-8": true,
+	}
+	for accept, expected := range testCases {
+		if actual := acceptsJSONLines(accept); actual != expected {
+			t.Errorf("%q: expected %t, got %t", accept, expected, actual)
+		}
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := newLineWriter(out, "git-clone", nil)
+	w.Write([]byte("2024-01-02T03:04:05.000000006Z Cloning \"https://github.com/openshift/ruby-hello-world\" ...\n2024-01-02T03:04:06Z \tCommit:"))
+	w.Write([]byte(" 1234\nno timestamp"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"container":"git-clone","stage":"FetchInputs","step":"FetchGitSource","timestamp":"2024-01-02T03:04:05.000000006Z","message":"Cloning \"https://github.com/openshift/ruby-hello-world\" ..."}
+{"container":"git-clone","stage":"FetchInputs","step":"FetchGitSource","timestamp":"2024-01-02T03:04:06Z","message":"\tCommit: 1234"}
+{"container":"git-clone","stage":"FetchInputs","step":"FetchGitSource","message":"no timestamp"}
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestLineWriterStages(t *testing.T) {
+	start := metav1.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	stages := []buildv1.StageInfo{
+		{Name: buildv1.StageBuild, StartTime: start, DurationMilliseconds: 10000, Steps: []buildv1.StepInfo{
+			{Name: buildv1.StepDockerBuild, StartTime: start, DurationMilliseconds: 10000},
+		}},
+		{Name: buildv1.StagePushImage, StartTime: metav1.NewTime(start.Add(10 * time.Second)), DurationMilliseconds: 5000, Steps: []buildv1.StepInfo{
+			{Name: buildv1.StepPushDockerImage, StartTime: metav1.NewTime(start.Add(10 * time.Second)), DurationMilliseconds: 5000},
+		}},
+	}
+	out := &bytes.Buffer{}
+	w := newLineWriter(out, "docker-build", stages)
+	w.Write([]byte("2024-01-02T03:04:05Z STEP 1/2: FROM busybox\n2024-01-02T03:04:12Z Pushing image ...\n2024-01-02T03:05:00Z done\n"))
+	expected := `{"container":"docker-build","stage":"Build","step":"DockerBuild","timestamp":"2024-01-02T03:04:05Z","message":"STEP 1/2: FROM busybox"}
+{"container":"docker-build","stage":"PushImage","step":"PushDockerImage","timestamp":"2024-01-02T03:04:12Z","message":"Pushing image ..."}
+{"container":"docker-build","stage":"Build","step":"DockerBuild","timestamp":"2024-01-02T03:05:00Z","message":"done"}
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestContainerFilter(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "git-clone"}, {Name: "manage-dockerfile"}},
+			Containers:     []corev1.Container{{Name: "docker-build"}},
+		},
+	}
+	testCases := map[string]struct {
+		filter   string
+		selected []string
+		errText  string
+	}{
+		"all":       {filter: "", selected: []string{"git-clone", "manage-dockerfile", "docker-build"}},
+		"container": {filter: "manage-dockerfile", selected: []string{"manage-dockerfile"}},
+		"stage":     {filter: "FetchInputs", selected: []string{"git-clone", "manage-dockerfile"}},
+		"step":      {filter: "DockerBuild", selected: []string{"docker-build"}},
+		"list":      {filter: "FetchGitSource, Build", selected: []string{"git-clone", "docker-build"}},
+		"unknown":   {filter: "git-clone,sti-build", errText: "unknown build log container sti-build"},
+	}
+	for name, tc := range testCases {
+		filter := newContainerFilter(tc.filter)
+		err := filter.validate(pod)
+		if len(tc.errText) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.errText) {
+				t.Errorf("%s: expected error containing %q, got %v", name, tc.errText, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		selected := []string{}
+		for _, container := range []string{"git-clone", "manage-dockerfile", "docker-build"} {
+			if filter.selects(container) {
+				selected = append(selected, container)
+			}
+		}
+		if strings.Join(selected, ",") != strings.Join(tc.selected, ",") {
+			t.Errorf("%s: expected %v to be selected, got %v", name, tc.selected, selected)
+		}
+	}
+}
+
+func TestStructuredBuildLogs(t *testing.T) {
+	informersCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	pod := mockPod(corev1.PodSucceeded, "bc-1-build")
+	pod.Spec.InitContainers = []corev1.Container{{Name: "git-clone"}, {Name: "manage-dockerfile"}}
+	pod.Spec.Containers = []corev1.Container{{Name: "docker-build"}}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "git-clone", State: terminated}, {Name: "manage-dockerfile", State: terminated}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "docker-build", State: terminated}}
+	client := fake.NewSimpleClientset(pod)
+	podInformer := fakeCoreV1PodInformer(client, informersCtx.Done())
+	if !cache.WaitForCacheSync(informersCtx.Done(), podInformer.Informer().HasSynced) {
+		t.Fatal("Informer's cache is not updated!")
+	}
+	build := mockBuild(buildv1.BuildPhaseComplete, "bc-1", 1)
+	storage := NewREST(buildfakeclient.NewSimpleClientset(build).BuildV1(), client.CoreV1(), podInformer.Lister(), nil)
+
+	ctx, cancelRequest := context.WithCancel(apirequest.WithNamespace(context.Background(), metav1.NamespaceDefault))
+	defer cancelRequest()
+	obj, err := storage.Get(ctx, "bc-1", &buildapi.BuildLogOptions{Container: "FetchInputs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream, _, contentType, err := obj.(rest.ResourceStreamer).InputStream(ctx, "", "application/jsonl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != JSONLinesContentType {
+		t.Errorf("expected content type %s, got %s", JSONLinesContentType, contentType)
+	}
+	// the fake client returns "fake logs" for every container
+	expected := `{"container":"git-clone","stage":"FetchInputs","step":"FetchGitSource","message":"fake logs"}
+{"container":"manage-dockerfile","stage":"FetchInputs","message":"fake logs"}
+`
+	if string(data) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(data))
+	}
+
+	// the format option selects structured logs regardless of the Accept header
+	obj, err = storage.Get(ctx, "bc-1", &buildapi.BuildLogOptions{Container: "FetchInputs", Format: buildapi.BuildLogFormatJSONLines})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream, _, contentType, err = obj.(rest.ResourceStreamer).InputStream(ctx, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err = ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != JSONLinesContentType || string(data) != expected {
+		t.Errorf("expected structured logs, got %s:\n%s", contentType, string(data))
+	}
+
+	obj, err = storage.Get(ctx, "bc-1", &buildapi.BuildLogOptions{Container: "FetchInputs", Format: buildapi.BuildLogFormatText})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream, _, contentType, err = obj.(rest.ResourceStreamer).InputStream(ctx, "", "application/jsonl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err = ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType == JSONLinesContentType || string(data) != "fake logsfake logs" {
+		t.Errorf("expected text logs, got %s:\n%s", contentType, string(data))
+	}
+
+	if _, err := storage.Get(ctx, "bc-1", &buildapi.BuildLogOptions{Container: "PushImage"}); err == nil || !strings.Contains(err.Error(), "unknown build log container PushImage") {
+		t.Errorf("expected the unknown container to be rejected, got %v", err)
+	}
+}
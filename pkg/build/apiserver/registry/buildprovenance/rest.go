@@ -0,0 +1,70 @@
+package buildprovenance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	buildtypedclient "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
+)
+
+// ProvenanceREST implements builds/provenance, which serves the provenance
+// statement recorded when a build completed as JSON. If a signer was configured at
+// that time the statement is served in a signed DSSE envelope.
+type ProvenanceREST struct {
+	BuildClient buildtypedclient.BuildsGetter
+}
+
+var _ rest.Connecter = &ProvenanceREST{}
+
+// NewREST creates the storage serving build provenance statements.
+func NewREST(buildClient buildtypedclient.BuildsGetter) *ProvenanceREST {
+	return &ProvenanceREST{BuildClient: buildClient}
+}
+
+// New returns the build the provenance is served for.
+func (r *ProvenanceREST) New() runtime.Object {
+	return &buildapi.Build{}
+}
+
+func (r *ProvenanceREST) Destroy() {}
+
+// Connect returns a handler serving the provenance recorded for the named build.
+func (r *ProvenanceREST) Connect(ctx context.Context, name string, _ runtime.Object, responder rest.Responder) (http.Handler, error) {
+	build, err := r.BuildClient.Builds(apirequest.NamespaceValue(ctx)).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if build.Status.Phase != buildv1.BuildPhaseComplete {
+		return nil, errors.NewBadRequest(fmt.Sprintf("build %s has not completed", name))
+	}
+	record, ok := build.Annotations[provenance.Annotation]
+	if !ok {
+		return nil, errors.NewNotFound(buildapi.Resource("builds/provenance"), name)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(record))
+	}), nil
+}
+
+// NewConnectOptions returns nil, provenance requests have no options.
+func (r *ProvenanceREST) NewConnectOptions() (runtime.Object, bool, string) {
+	return nil, false, ""
+}
+
+// ConnectMethods returns GET, the only supported method.
+func (r *ProvenanceREST) ConnectMethods() []string {
+	return []string{"GET"}
+}
@@ -0,0 +1,41 @@
+package etcd
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/openshift/api/build"
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/registry/buildstrategypolicy"
+)
+
+type REST struct {
+	*registry.Store
+}
+
+var _ rest.StandardStorage = &REST{}
+
+// NewREST returns a RESTStorage object that will work against BuildStrategyPolicy.
+func NewREST(optsGetter generic.RESTOptionsGetter) (*REST, error) {
+	store := &registry.Store{
+		NewFunc:                   func() runtime.Object { return &buildapi.BuildStrategyPolicy{} },
+		NewListFunc:               func() runtime.Object { return &buildapi.BuildStrategyPolicyList{} },
+		DefaultQualifiedResource:  build.Resource("buildstrategypolicies"),
+		SingularQualifiedResource: build.Resource("buildstrategypolicy"),
+
+		TableConvertor: rest.NewDefaultTableConvertor(build.Resource("buildstrategypolicies")),
+
+		CreateStrategy: buildstrategypolicy.Strategy,
+		UpdateStrategy: buildstrategypolicy.Strategy,
+		DeleteStrategy: buildstrategypolicy.Strategy,
+	}
+
+	options := &generic.StoreOptions{RESTOptions: optsGetter}
+	if err := store.CompleteWithOptions(options); err != nil {
+		return nil, err
+	}
+
+	return &REST{store}, nil
+}
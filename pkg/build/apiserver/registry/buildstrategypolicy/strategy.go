@@ -0,0 +1,72 @@
+package buildstrategypolicy
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/kubernetes/pkg/api/legacyscheme"
+
+	buildapi "github.com/openshift/openshift-apiserver/pkg/build/apis/build"
+	"github.com/openshift/openshift-apiserver/pkg/build/apis/build/validation"
+)
+
+// strategy implements the behavior of BuildStrategyPolicy objects.
+type strategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating
+// BuildStrategyPolicy objects.
+var Strategy = strategy{legacyscheme.Scheme, names.SimpleNameGenerator}
+
+// NamespaceScoped is false, a policy selects the namespaces it applies to.
+func (strategy) NamespaceScoped() bool {
+	return false
+}
+
+func (strategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (strategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+// Canonicalize normalizes the object after validation.
+func (strategy) Canonicalize(obj runtime.Object) {
+}
+
+func (strategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+	policy := obj.(*buildapi.BuildStrategyPolicy)
+	policy.Generation = 1
+}
+
+func (strategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newPolicy := obj.(*buildapi.BuildStrategyPolicy)
+	oldPolicy := old.(*buildapi.BuildStrategyPolicy)
+	if !reflect.DeepEqual(oldPolicy.Spec, newPolicy.Spec) {
+		newPolicy.Generation = oldPolicy.Generation + 1
+	}
+}
+
+func (strategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return validation.ValidateBuildStrategyPolicy(obj.(*buildapi.BuildStrategyPolicy))
+}
+
+// WarningsOnCreate returns warnings for the creation of the given object.
+func (strategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
+	return nil
+}
+
+func (strategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	return validation.ValidateBuildStrategyPolicyUpdate(obj.(*buildapi.BuildStrategyPolicy), old.(*buildapi.BuildStrategyPolicy))
+}
+
+// WarningsOnUpdate returns warnings for the given update.
+func (strategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return nil
+}
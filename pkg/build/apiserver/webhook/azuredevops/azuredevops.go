@@ -0,0 +1,161 @@
+package azuredevops
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/build/buildutil"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+)
+
+const pushEventType = "git.push"
+
+// WebHookPlugin used for processing Azure DevOps service hook requests.
+type WebHookPlugin struct{}
+
+// New returns azure devops webhook plugin.
+func New() *WebHookPlugin {
+	return &WebHookPlugin{}
+}
+
+// A git.push service hook event. Unlike the other providers, Azure DevOps
+// sends the event type in the payload instead of a header.
+type pushEvent struct {
+	EventType string   `json:"eventType"`
+	Resource  resource `json:"resource"`
+}
+
+type resource struct {
+	Commits    []commit    `json:"commits"`
+	RefUpdates []refUpdate `json:"refUpdates"`
+}
+
+type commit struct {
+	CommitID  string `json:"commitId"`
+	Author    user   `json:"author"`
+	Committer user   `json:"committer"`
+	Comment   string `json:"comment"`
+}
+
+type user struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type refUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId"`
+}
+
+// Extract services webhooks from Azure DevOps
+func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, parameters map[string]string, proceed bool, err error) {
+	klog.V(4).Infof("Verifying build request for BuildConfig %s/%s", buildCfg.Namespace, buildCfg.Name)
+	if err = verifyRequest(req, trigger); err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
+	}
+	var event pushEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
+	}
+	webhook.SetDeliveryEvent(req, event.EventType, "")
+	if event.EventType != pushEventType {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(fmt.Sprintf("Unknown Azure DevOps eventType %s", event.EventType))
+	}
+	if len(event.Resource.RefUpdates) == 0 {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest("Unable to extract valid event from payload: no refUpdates")
+	}
+
+	// A single push may update several refs, build from the first one matching
+	// the configuration.
+	var matched *refUpdate
+	for i := range event.Resource.RefUpdates {
+		update := &event.Resource.RefUpdates[i]
+		webhook.SetDeliveryEvent(req, "", update.Name)
+		if webhook.GitRefMatches(update.Name, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
+			matched = update
+			break
+		}
+	}
+	if matched == nil {
+		klog.V(2).Infof("Skipping build for BuildConfig %s/%s.  None of the updated refs match configuration", buildCfg.Namespace, buildCfg.Name)
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
+	}
+
+	revision = &buildv1.SourceRevision{
+		Git: &buildv1.GitSourceRevision{
+			Commit: matched.NewObjectID,
+		},
+	}
+	for _, c := range event.Resource.Commits {
+		if c.CommitID != matched.NewObjectID {
+			continue
+		}
+		revision.Git.Author = buildv1.SourceControlUser{Name: c.Author.Name, Email: c.Author.Email}
+		revision.Git.Committer = buildv1.SourceControlUser{Name: c.Committer.Name, Email: c.Committer.Email}
+		revision.Git.Message = c.Comment
+		break
+	}
+	return revision, envvars, dockerStrategyOptions, parameters, true, err
+}
+
+// GetTriggers retrieves the WebHookTriggers for this webhook type (if any)
+func (p *WebHookPlugin) GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error) {
+	triggers := buildutil.FindTriggerPolicy(buildv1.AzureDevOpsWebHookBuildTriggerType, buildConfig)
+	webhookTriggers := []*buildv1.WebHookTrigger{}
+	for _, trigger := range triggers {
+		if trigger.AzureDevOpsWebHook != nil {
+			webhookTriggers = append(webhookTriggers, trigger.AzureDevOpsWebHook)
+		}
+	}
+	if len(webhookTriggers) == 0 {
+		return nil, webhook.ErrHookNotEnabled
+	}
+	return webhookTriggers, nil
+}
+
+func verifyRequest(req *http.Request, trigger *buildv1.WebHookTrigger) error {
+	if method := req.Method; method != "POST" {
+		return webhook.MethodNotSupported
+	}
+	contentType := req.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return errors.NewBadRequest(fmt.Sprintf("non-parseable Content-Type %s (%s)", contentType, err))
+	}
+	if mediaType != "application/json" {
+		return errors.NewBadRequest(fmt.Sprintf("unsupported Content-Type %s", contentType))
+	}
+	return verifyCredentials(req, trigger)
+}
+
+// verifyCredentials checks the basic authentication credentials Azure DevOps
+// service hooks can be configured to send. Requests for a trigger protected by a
+// secret must carry credentials whose password matches the webhook secret.
+func verifyCredentials(req *http.Request, trigger *buildv1.WebHookTrigger) error {
+	if !webhook.HasSecret(trigger) {
+		return nil
+	}
+	_, password, ok := req.BasicAuth()
+	if !ok {
+		return webhook.ErrSecretMismatch
+	}
+	secret, ok := webhook.SecretFrom(req.Context())
+	if !ok || !hmac.Equal([]byte(password), []byte(secret)) {
+		return webhook.ErrSecretMismatch
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package azuredevops
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+)
+
+var mockBuildStrategy = buildv1.BuildStrategy{
+	SourceStrategy: &buildv1.SourceBuildStrategy{
+		From: corev1.ObjectReference{
+			Kind: "DockerImage",
+			Name: "repository/image",
+		},
+	},
+}
+
+func newBuildConfig(ref string) *buildv1.BuildConfig {
+	return &buildv1.BuildConfig{
+		Spec: buildv1.BuildConfigSpec{
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{
+					Type: buildv1.AzureDevOpsWebHookBuildTriggerType,
+					AzureDevOpsWebHook: &buildv1.WebHookTrigger{
+						Secret: "secret100",
+					},
+				},
+			},
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Git: &buildv1.GitBuildSource{
+						URI: "https://fabrikam-fiber-inc.visualstudio.com/DefaultCollection/_git/Fabrikam-Fiber-Git",
+						Ref: ref,
+					},
+				},
+				Strategy: mockBuildStrategy,
+			},
+		},
+	}
+}
+
+func postFile(t *testing.T, filename string) *http.Request {
+	data, err := ioutil.ReadFile("testdata/" + filename)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", filename, err)
+	}
+	return post(t, data)
+}
+
+func post(t *testing.T, data []byte) *http.Request {
+	req, err := http.NewRequest("POST", "http://some.url", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error creating POST request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.SetBasicAuth("openshift", "secret100")
+	return req.WithContext(webhook.WithSecret(req.Context(), "secret100"))
+}
+
+func TestGetTriggers(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	triggers, err := New().GetTriggers(buildConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].Secret != "secret100" {
+		t.Errorf("Expected the azure devops trigger, got %#v", triggers)
+	}
+
+	buildConfig.Spec.Triggers[0].Type = buildv1.GenericWebHookBuildTriggerType
+	if _, err := New().GetTriggers(buildConfig); err != webhook.ErrHookNotEnabled {
+		t.Errorf("Expected %v, got %v", webhook.ErrHookNotEnabled, err)
+	}
+}
+
+func TestVerifyRequestForMethod(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req, _ := http.NewRequest("GET", "http://someurl.com", nil)
+	revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err != webhook.MethodNotSupported {
+		t.Errorf("Expected unsupported HTTP method, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+	if revision != nil {
+		t.Error("Expected the 'revision' return value to be nil")
+	}
+}
+
+func TestWrongEventType(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req := post(t, []byte(`{"eventType": "git.pullrequest.created"}`))
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err == nil || !strings.Contains(err.Error(), "Unknown Azure DevOps eventType") {
+		t.Errorf("Expected Unknown Azure DevOps eventType, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+}
+
+func TestJsonPushEventError(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req := post(t, []byte{})
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err == nil || !strings.Contains(err.Error(), "unexpected end of JSON input") {
+		t.Errorf("Expected unexpected end of JSON input, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+}
+
+func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req := postFile(t, "pushevent.json")
+	revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err != nil {
+		t.Errorf("Error while extracting build info: %v", err)
+	}
+	if !proceed {
+		t.Error("The 'proceed' return value should equal 'true'")
+	}
+	if revision == nil {
+		t.Fatal("Expecting the revision to not be nil")
+	}
+	if revision.Git.Commit != "33b55f7cb7e7e245323987634f960cf4a6e6bc74" {
+		t.Errorf("Expecting the revision to contain the commit id from the push event, got %q", revision.Git.Commit)
+	}
+	if revision.Git.Message != "Fixed bug in web.config file" {
+		t.Errorf("Expecting the revision to contain the commit message from the push event, got %q", revision.Git.Message)
+	}
+}
+
+func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
+	buildConfig := newBuildConfig("my_other_branch")
+	req := postFile(t, "pushevent-not-master-branch.json")
+	revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err != nil {
+		t.Errorf("Error while extracting build info: %v", err)
+	}
+	if !proceed {
+		t.Error("The 'proceed' return value should equal 'true'")
+	}
+	if revision == nil {
+		t.Fatal("Expecting the revision to not be nil")
+	}
+}
+
+func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
+	buildConfig := newBuildConfig("wrongref")
+	req := postFile(t, "pushevent.json")
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+	if err != nil {
+		t.Errorf("Error while extracting build info: %v", err)
+	}
+	if proceed {
+		t.Errorf("Expecting to not continue from this event because the branch is not for this buildConfig '%s'", buildConfig.Spec.Source.Git.Ref)
+	}
+}
+
+func TestExtractVerifiesBasicAuthentication(t *testing.T) {
+	testCases := map[string]struct {
+		password string
+		proceed  bool
+	}{
+		"matching password": {
+			password: "secret100",
+			proceed:  true,
+		},
+		"wrong password": {
+			password: "othersecret",
+		},
+		"missing credentials": {},
+	}
+	for name, tc := range testCases {
+		buildConfig := newBuildConfig("")
+		req := postFile(t, "pushevent.json")
+		req.Header.Del("Authorization")
+		if len(tc.password) > 0 {
+			req.SetBasicAuth("openshift", tc.password)
+		}
+		_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].AzureDevOpsWebHook, req)
+
+		if proceed != tc.proceed {
+			t.Errorf("%s: expected proceed %t, got %t", name, tc.proceed, proceed)
+		}
+		if !tc.proceed && err != webhook.ErrSecretMismatch {
+			t.Errorf("%s: expected %v, got %v", name, webhook.ErrSecretMismatch, err)
+		}
+	}
+}
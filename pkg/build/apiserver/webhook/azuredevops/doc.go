@@ -0,0 +1,3 @@
+// Package azuredevops contains webhook.Plugin implementation of Azure DevOps service hooks
+// according to https://learn.microsoft.com/en-us/azure/devops/service-hooks/events#git.push
+package azuredevops
@@ -72,38 +72,40 @@ type ref struct {
 }
 
 // Extract services webhooks from bitbucket.com
-func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, proceed bool, err error) {
+func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, parameters map[string]string, proceed bool, err error) {
 	klog.V(4).Infof("Verifying build request for BuildConfig %s/%s", buildCfg.Namespace, buildCfg.Name)
 	if err = verifyRequest(req); err != nil {
-		return revision, envvars, dockerStrategyOptions, false, err
+		return revision, envvars, dockerStrategyOptions, parameters, false, err
 	}
 
 	method := getEvent(req.Header)
+	webhook.SetDeliveryEvent(req, method, "")
 	branch := ""
 	switch method {
 	// https://confluence.atlassian.com/bitbucket/event-payloads-740262817.html
 	case "repo:push":
 		branch, revision, err = getInfoFromEvent(req.Body)
 		if err != nil {
-			return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(err.Error())
+			return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(err.Error())
 		}
 
 	// https://confluence.atlassian.com/bitbucketserver/event-payload-938025882.html
 	case "repo:refs_changed":
 		branch, revision, err = getInfoFromEvent54(req.Body)
 		if err != nil {
-			return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(err.Error())
+			return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(err.Error())
 		}
 	default:
-		return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(fmt.Sprintf("Unknown Bitbucket X-Event-Key %s", method))
+		return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(fmt.Sprintf("Unknown Bitbucket X-Event-Key %s", method))
 	}
 
+	webhook.SetDeliveryEvent(req, "", branch)
 	if !webhook.GitRefMatches(branch, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
 		klog.V(2).Infof("Skipping build for BuildConfig %s/%s.  Branch reference '%s' does not match configuration", buildCfg.Namespace, buildCfg.Name, branch)
-		return revision, envvars, dockerStrategyOptions, false, err
+		return revision, envvars, dockerStrategyOptions, parameters, false, err
 	}
 
-	return revision, envvars, dockerStrategyOptions, true, err
+	return revision, envvars, dockerStrategyOptions, parameters, true, err
 }
 
 // GetTriggers retrieves the WebHookTriggers for this webhook type (if any)
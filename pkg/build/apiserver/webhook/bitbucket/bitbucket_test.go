@@ -124,7 +124,7 @@ func setup(t *testing.T, filename, eventType, ref string) *testContext {
 func TestVerifyRequestForMethod(t *testing.T) {
 	req := GivenRequest("GET")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unsupported HTTP method") {
 		t.Errorf("Expected unsupported HTTP method, got %v", err)
@@ -141,7 +141,7 @@ func TestMissingEvent(t *testing.T) {
 	req := GivenRequest("POST")
 	req.Header.Add("Content-Type", "application/json")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "missing X-Event-Key") {
 		t.Errorf("Expected missing X-Event-Key, got %v", err)
@@ -159,7 +159,7 @@ func TestWrongEventKey(t *testing.T) {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("X-Event-Key", "wrong")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "Unknown Bitbucket X-Event-Key") {
 		t.Errorf("Expected missing Unknown Bitbucket X-Event-Key, got %v", err)
@@ -175,7 +175,7 @@ func TestWrongEventKey(t *testing.T) {
 func TestJsonPushEventError(t *testing.T) {
 	req := post("X-Event-Key", "repo:push", []byte{}, "http://some.url", http.StatusBadRequest, t)
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unexpected end of JSON input") {
 		t.Errorf("Expected unexpected end of JSON input, got %v", err)
@@ -191,7 +191,7 @@ func TestJsonPushEventError(t *testing.T) {
 func TestJsonBitbucketPushEvent(t *testing.T) {
 	req := postFile("X-Event-Key", "repo:push", "pushevent.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -204,7 +204,7 @@ func TestJsonBitbucketPushEvent(t *testing.T) {
 func TestJsonBitbucketPushEvent54(t *testing.T) {
 	req := postFile("X-Event-Key", "repo:refs_changed", "pushevent54.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -217,7 +217,7 @@ func TestJsonBitbucketPushEvent54(t *testing.T) {
 func TestJsonGitHubPushEventWithCharset(t *testing.T) {
 	req := postFileWithCharset("X-Event-Key", "repo:push", "pushevent.json", "http://some.url", "application/json; charset=utf-8", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -230,7 +230,7 @@ func TestJsonGitHubPushEventWithCharset(t *testing.T) {
 func TestJsonGitHubPushEventWithCharset54(t *testing.T) {
 	req := postFileWithCharset("X-Event-Key", "repo:refs_changed", "pushevent54.json", "http://some.url", "application/json; charset=utf-8", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].BitbucketWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -245,7 +245,7 @@ func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
 	context := setup(t, "pushevent.json", "repo:push", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -268,7 +268,7 @@ func TestExtractProvidesValidBuildForAPushEvent54(t *testing.T) {
 	context := setup(t, "pushevent54.json", "repo:refs_changed", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -290,7 +290,7 @@ func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
 	//setup
 	context := setup(t, "pushevent-not-master.json", "repo:push", "this-is-not-master")
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -311,7 +311,7 @@ func TestExtractProvidesValidBuildForAPushEventOtherThanMaster54(t *testing.T) {
 	//setup
 	context := setup(t, "pushevent54-not-master.json", "repo:refs_changed", "other")
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -333,7 +333,7 @@ func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
 	context := setup(t, "pushevent.json", "repo:push", "wrongref")
 
 	//execute
-	_, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	_, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 	if err != nil {
 		t.Errorf("unexpected error %v", err)
 	}
@@ -347,7 +347,7 @@ func TestExtractSkipsBuildForUnmatchedBranches54(t *testing.T) {
 	context := setup(t, "pushevent54.json", "repo:refs_changed", "wrongref")
 
 	//execute
-	_, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	_, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 	if err != nil {
 		t.Errorf("unexpected error %v", err)
 	}
@@ -361,7 +361,7 @@ func TestExtractErrorForWrongEventPayload(t *testing.T) {
 	context := setup(t, "pushevent.json", "repo:refs_changed", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 	if err == nil {
 		t.Errorf("Did not get expected error due to mismatched payload and event type")
 	}
@@ -381,7 +381,7 @@ func TestExtractErrorForWrongEventPayload54(t *testing.T) {
 	context := setup(t, "pushevent54.json", "repo:push", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].BitbucketWebHook, context.req)
 	if err == nil {
 		t.Errorf("Did not get expected error due to mismatched payload and event type")
 	}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// maxDeliveryReasonLength bounds the number of characters of the reason
+	// recorded for a delivery.
+	maxDeliveryReasonLength = 256
+
+	redactedSecret = "<secret>"
+)
+
+// DeliveryOutcome describes what happened to a webhook delivery.
+type DeliveryOutcome string
+
+const (
+	// DeliveryOutcomeBuildTriggered means the delivery resulted in a new build.
+	DeliveryOutcomeBuildTriggered DeliveryOutcome = "BuildTriggered"
+	// DeliveryOutcomeSkipped means the delivery was accepted but did not match
+	// the BuildConfig, for example because the pushed ref differs.
+	DeliveryOutcomeSkipped DeliveryOutcome = "Skipped"
+	// DeliveryOutcomeRejected means the delivery was refused, for example because
+	// of a secret mismatch, an unsupported content type or an unknown event.
+	DeliveryOutcomeRejected DeliveryOutcome = "Rejected"
+	// DeliveryOutcomeFailed means the delivery was accepted but the build could
+	// not be created.
+	DeliveryOutcomeFailed DeliveryOutcome = "Failed"
+)
+
+// Delivery describes a single webhook request made against a BuildConfig. It is
+// recorded as an event on the BuildConfig.
+type Delivery struct {
+	Plugin  string
+	Event   string
+	Ref     string
+	Outcome DeliveryOutcome
+	Build   string
+	Reason  string
+}
+
+type deliveryKey struct{}
+
+// WithDelivery returns a copy of ctx carrying delivery, so plugins can describe
+// the event they parsed.
+func WithDelivery(ctx context.Context, delivery *Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, delivery)
+}
+
+// DeliveryFrom returns the delivery carried by ctx, if any.
+func DeliveryFrom(ctx context.Context) (*Delivery, bool) {
+	delivery, ok := ctx.Value(deliveryKey{}).(*Delivery)
+	return delivery, ok && delivery != nil
+}
+
+// SetDeliveryEvent records the event type and git ref of the request on the
+// delivery being tracked for req. It is a no-op when no delivery is tracked.
+func SetDeliveryEvent(req *http.Request, event, ref string) {
+	delivery, ok := DeliveryFrom(req.Context())
+	if !ok {
+		return
+	}
+	if len(event) > 0 {
+		delivery.Event = event
+	}
+	if len(ref) > 0 {
+		delivery.Ref = ref
+	}
+}
+
+// Redact removes any occurrence of secret from reason and bounds its length.
+func (d *Delivery) Redact(secret string) {
+	if len(secret) > 0 {
+		d.Reason = strings.ReplaceAll(d.Reason, secret, redactedSecret)
+	}
+	if reason := []rune(d.Reason); len(reason) > maxDeliveryReasonLength {
+		d.Reason = string(reason[:maxDeliveryReasonLength])
+	}
+}
+
+// EventType returns the type of the event recording the delivery.
+func (d *Delivery) EventType() string {
+	switch d.Outcome {
+	case DeliveryOutcomeRejected, DeliveryOutcomeFailed:
+		return corev1.EventTypeWarning
+	}
+	return corev1.EventTypeNormal
+}
+
+// EventReason returns the reason of the event recording the delivery.
+func (d *Delivery) EventReason() string {
+	return "WebHook" + string(d.Outcome)
+}
+
+// String describes the delivery in the message of the event recording it.
+func (d *Delivery) String() string {
+	message := d.Plugin + " webhook"
+	if len(d.Event) > 0 {
+		message += fmt.Sprintf(" %s event", d.Event)
+	}
+	if len(d.Ref) > 0 {
+		message += fmt.Sprintf(" for %s", d.Ref)
+	}
+	switch d.Outcome {
+	case DeliveryOutcomeBuildTriggered:
+		message += fmt.Sprintf(" triggered build %s", d.Build)
+	case DeliveryOutcomeSkipped:
+		message += " skipped"
+	case DeliveryOutcomeRejected:
+		message += " rejected"
+	case DeliveryOutcomeFailed:
+		message += " failed"
+	}
+	if len(d.Reason) > 0 {
+		message += ": " + d.Reason
+	}
+	return message
+}
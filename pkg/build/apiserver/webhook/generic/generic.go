@@ -27,37 +27,39 @@ func New() *WebHookPlugin {
 	return &WebHookPlugin{}
 }
 
-// Extract services generic webhooks.
-func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, proceed bool, err error) {
+// Extract services generic webhooks. It is the only plugin returning values for
+// the parameters of the build configuration, read from the parameters field of
+// the payload.
+func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, parameters map[string]string, proceed bool, err error) {
 	klog.V(4).Infof("Verifying build request for BuildConfig %s/%s", buildCfg.Namespace, buildCfg.Name)
 	if err = verifyRequest(req); err != nil {
-		return revision, envvars, dockerStrategyOptions, false, err
+		return revision, envvars, dockerStrategyOptions, parameters, false, err
 	}
 
 	contentType := req.Header.Get("Content-Type")
 	if len(contentType) != 0 {
 		contentType, _, err = mime.ParseMediaType(contentType)
 		if err != nil {
-			return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(fmt.Sprintf("error parsing Content-Type: %s", err))
+			return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(fmt.Sprintf("error parsing Content-Type: %s", err))
 		}
 	}
 
 	if req.Body == nil {
-		return revision, envvars, dockerStrategyOptions, true, nil
+		return revision, envvars, dockerStrategyOptions, parameters, true, nil
 	}
 
 	if contentType != "application/json" && contentType != "application/yaml" {
 		warning := webhook.NewWarning("invalid Content-Type on payload, ignoring payload and continuing with build")
-		return revision, envvars, dockerStrategyOptions, true, warning
+		return revision, envvars, dockerStrategyOptions, parameters, true, warning
 	}
 
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(err.Error())
+		return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(err.Error())
 	}
 
 	if len(body) == 0 {
-		return revision, envvars, dockerStrategyOptions, true, nil
+		return revision, envvars, dockerStrategyOptions, parameters, true, nil
 	}
 
 	internalData := &buildapi.GenericWebHookEvent{}
@@ -66,16 +68,24 @@ func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.
 		body, err = yaml.ToJSON(body)
 		if err != nil {
 			warning := webhook.NewWarning(fmt.Sprintf("error converting payload to json: %v, ignoring payload and continuing with build", err))
-			return revision, envvars, dockerStrategyOptions, true, warning
+			return revision, envvars, dockerStrategyOptions, parameters, true, warning
 		}
 	}
 	if err = json.Unmarshal(body, &versionedData); err != nil {
 		warning := webhook.NewWarning(fmt.Sprintf("error unmarshalling payload: %v, ignoring payload and continuing with build", err))
-		return revision, envvars, dockerStrategyOptions, true, warning
+		return revision, envvars, dockerStrategyOptions, parameters, true, warning
 	}
 
 	if err := v1.Convert_v1_GenericWebHookEvent_To_build_GenericWebHookEvent(versionedData, internalData, nil); err != nil {
-		return revision, envvars, dockerStrategyOptions, false, errors.NewBadRequest(err.Error())
+		return revision, envvars, dockerStrategyOptions, parameters, false, errors.NewBadRequest(err.Error())
+	}
+
+	// parameters are not part of the versioned event, which is shared with clients
+	payload := &struct {
+		Parameters map[string]string `json:"parameters"`
+	}{}
+	if err := json.Unmarshal(body, payload); err == nil {
+		parameters = payload.Parameters
 	}
 
 	if len(versionedData.Env) > 0 && trigger.AllowEnv {
@@ -86,33 +96,35 @@ func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.
 	}
 	if buildCfg.Spec.Source.Git == nil {
 		// everything below here is specific to git-based builds
-		return revision, envvars, dockerStrategyOptions, true, nil
+		return revision, envvars, dockerStrategyOptions, parameters, true, nil
 	}
 	if internalData.Git == nil {
 		warning := webhook.NewWarning("no git information found in payload, ignoring and continuing with build")
-		return revision, envvars, dockerStrategyOptions, true, warning
+		return revision, envvars, dockerStrategyOptions, parameters, true, warning
 	}
 
 	if internalData.Git.Refs != nil {
 		for _, ref := range versionedData.Git.Refs {
 			if webhook.GitRefMatches(ref.Ref, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
+				webhook.SetDeliveryEvent(req, "", ref.Ref)
 				revision = &buildv1.SourceRevision{
 					Git: &ref.GitSourceRevision,
 				}
-				return revision, envvars, dockerStrategyOptions, true, nil
+				return revision, envvars, dockerStrategyOptions, parameters, true, nil
 			}
 		}
 		warning := webhook.NewWarning(fmt.Sprintf("skipping build. None of the supplied refs matched %q", buildCfg.Spec.Source.Git.Ref))
-		return revision, envvars, dockerStrategyOptions, false, warning
+		return revision, envvars, dockerStrategyOptions, parameters, false, warning
 	}
+	webhook.SetDeliveryEvent(req, "", internalData.Git.Ref)
 	if !webhook.GitRefMatches(internalData.Git.Ref, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
 		warning := webhook.NewWarning(fmt.Sprintf("skipping build. Branch reference from %q does not match configuration", internalData.Git.Ref))
-		return revision, envvars, dockerStrategyOptions, false, warning
+		return revision, envvars, dockerStrategyOptions, parameters, false, warning
 	}
 	revision = &buildv1.SourceRevision{
 		Git: &versionedData.Git.GitSourceRevision,
 	}
-	return revision, envvars, dockerStrategyOptions, true, nil
+	return revision, envvars, dockerStrategyOptions, parameters, true, nil
 }
 
 // GetTriggers retrieves the WebHookTriggers for this webhook type (if any)
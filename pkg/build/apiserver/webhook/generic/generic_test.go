@@ -87,7 +87,7 @@ func TestVerifyRequestForMethod(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unsupported HTTP method") {
 		t.Errorf("Expected unsupported HTTP method, got %v!", err)
@@ -131,7 +131,7 @@ func TestExtractWithEmptyPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
 	}
@@ -166,7 +166,7 @@ func TestExtractWithUnmatchedRefGitPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	build, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	build, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	matchWarning(t, err, `skipping build. Branch reference from "refs/heads/master" does not match configuration`)
 	if proceed {
@@ -200,7 +200,7 @@ func TestExtractWithGitPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -236,7 +236,7 @@ func TestExtractWithGitPayloadAndUTF8Charset(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -272,7 +272,7 @@ func TestExtractWithGitRefsPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -308,7 +308,7 @@ func TestExtractWithUnmatchedGitRefsPayload(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	matchWarning(t, err, `skipping build. None of the supplied refs matched "other"`)
 	if proceed {
@@ -343,7 +343,7 @@ func TestExtractWithKeyValuePairsJSON(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, envvars, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, envvars, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -384,7 +384,7 @@ func TestExtractWithKeyValuePairsYAML(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, envvars, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, envvars, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -424,7 +424,7 @@ func TestExtractWithKeyValuePairsDisabled(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, envvars, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, envvars, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -462,7 +462,7 @@ func TestGitlabPush(t *testing.T) {
 		},
 	}
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	matchWarning(t, err, "no git information found in payload, ignoring and continuing with build")
 	if !proceed {
@@ -492,7 +492,7 @@ func TestNonJsonPush(t *testing.T) {
 		},
 	}
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 
 	if err != nil {
 		t.Errorf("Expected to be able to trigger a build without a payload error: %v", err)
@@ -533,7 +533,7 @@ func TestExtractWithUnmarshalError(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 	matchWarning(t, err, `error unmarshalling payload: invalid character '\x00' looking for beginning of value, ignoring payload and continuing with build`)
 	if !proceed {
 		t.Error("Expected 'proceed' return value to be 'true'")
@@ -567,7 +567,7 @@ func TestExtractWithUnmarshalErrorYAML(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 	matchWarning(t, err, "error converting payload to json: yaml: control characters are not allowed, ignoring payload and continuing with build")
 	if !proceed {
 		t.Error("Expected 'proceed' return value to be 'true'")
@@ -601,7 +601,7 @@ func TestExtractWithBadContentType(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 	matchWarning(t, err, "invalid Content-Type on payload, ignoring payload and continuing with build")
 	if !proceed {
 		t.Error("Expected 'proceed' return value to be 'true'")
@@ -635,7 +635,7 @@ func TestExtractWithUnparseableContentType(t *testing.T) {
 		},
 	}
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
 	if err == nil || err.Error() != "error parsing Content-Type: mime: expected token after slash" {
 		t.Errorf("Unexpected error %v", err)
 	}
@@ -646,3 +646,35 @@ func TestExtractWithUnparseableContentType(t *testing.T) {
 		t.Error("Expected the 'revision' return value to be nil")
 	}
 }
+
+func TestExtractWithParameters(t *testing.T) {
+	payload := `{"git":{"uri":"https://github.com/openshift/ruby-hello-world","ref":"master"},"parameters":{"tag":"v2"}}`
+	req, _ := http.NewRequest("POST", "http://someurl.com", strings.NewReader(payload))
+	req.Header.Add("Content-Type", "application/json")
+	buildConfig := &buildv1.BuildConfig{
+		Spec: buildv1.BuildConfigSpec{
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{
+					Type:           buildv1.GenericWebHookBuildTriggerType,
+					GenericWebHook: &buildv1.WebHookTrigger{Secret: "secret100"},
+				},
+			},
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Git: &buildv1.GitBuildSource{
+						Ref: "master",
+					},
+				},
+				Strategy: mockBuildStrategy,
+			},
+		},
+	}
+	plugin := New()
+	_, _, _, parameters, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GenericWebHook, req)
+	if err != nil || !proceed {
+		t.Fatalf("Expected to proceed without error, got %v, %v", proceed, err)
+	}
+	if parameters["tag"] != "v2" {
+		t.Errorf("Expected the parameters to be returned, got %v", parameters)
+	}
+}
@@ -0,0 +1,161 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/library-go/pkg/build/buildutil"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+)
+
+// WebHookPlugin used for processing Gitea and Forgejo webhook requests.
+type WebHookPlugin struct{}
+
+// New returns gitea webhook plugin.
+func New() *WebHookPlugin {
+	return &WebHookPlugin{}
+}
+
+type user struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type commit struct {
+	ID        string `json:"id,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Author    user   `json:"author,omitempty"`
+	Committer user   `json:"committer,omitempty"`
+}
+
+// NOTE - older Gitea releases do not send head_commit, in which case the last
+// entry of the commits array is the latest commit
+type pushEvent struct {
+	Ref        string   `json:"ref,omitempty"`
+	After      string   `json:"after,omitempty"`
+	Commits    []commit `json:"commits,omitempty"`
+	HeadCommit *commit  `json:"head_commit,omitempty"`
+}
+
+// Extract services webhooks from Gitea and Forgejo servers
+func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, parameters map[string]string, proceed bool, err error) {
+	klog.V(4).Infof("Verifying build request for BuildConfig %s/%s", buildCfg.Namespace, buildCfg.Name)
+	if err = verifyRequest(req); err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
+	}
+	method := getEvent(req.Header)
+	webhook.SetDeliveryEvent(req, method, "")
+	if method != "push" {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(fmt.Sprintf("Unknown X-Gitea-Event or X-Forgejo-Event %s", method))
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
+	}
+	if err = verifySignature(req, trigger, body); err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
+	}
+	var event pushEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
+	}
+	webhook.SetDeliveryEvent(req, "", event.Ref)
+	if !webhook.GitRefMatches(event.Ref, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
+		klog.V(2).Infof("Skipping build for BuildConfig %s/%s.  Branch reference from '%s' does not match configuration", buildCfg.Namespace, buildCfg.Name, event.Ref)
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
+	}
+
+	head := event.HeadCommit
+	if head == nil && len(event.Commits) > 0 {
+		head = &event.Commits[len(event.Commits)-1]
+	}
+	if head == nil {
+		head = &commit{ID: event.After}
+	}
+
+	revision = &buildv1.SourceRevision{
+		Git: &buildv1.GitSourceRevision{
+			Commit:    head.ID,
+			Author:    buildv1.SourceControlUser{Name: head.Author.Name, Email: head.Author.Email},
+			Committer: buildv1.SourceControlUser{Name: head.Committer.Name, Email: head.Committer.Email},
+			Message:   head.Message,
+		},
+	}
+	return revision, envvars, dockerStrategyOptions, parameters, true, err
+}
+
+// GetTriggers retrieves the WebHookTriggers for this webhook type (if any)
+func (p *WebHookPlugin) GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error) {
+	triggers := buildutil.FindTriggerPolicy(buildv1.GiteaWebHookBuildTriggerType, buildConfig)
+	webhookTriggers := []*buildv1.WebHookTrigger{}
+	for _, trigger := range triggers {
+		if trigger.GiteaWebHook != nil {
+			webhookTriggers = append(webhookTriggers, trigger.GiteaWebHook)
+		}
+	}
+	if len(webhookTriggers) == 0 {
+		return nil, webhook.ErrHookNotEnabled
+	}
+	return webhookTriggers, nil
+}
+
+func verifyRequest(req *http.Request) error {
+	if method := req.Method; method != "POST" {
+		return webhook.MethodNotSupported
+	}
+	contentType := req.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return errors.NewBadRequest(fmt.Sprintf("non-parseable Content-Type %s (%s)", contentType, err))
+	}
+	if mediaType != "application/json" {
+		return errors.NewBadRequest(fmt.Sprintf("unsupported Content-Type %s", contentType))
+	}
+	if len(getEvent(req.Header)) == 0 {
+		return errors.NewBadRequest("missing X-Gitea-Event or X-Forgejo-Event")
+	}
+	return nil
+}
+
+// verifySignature checks the HMAC-SHA256 payload signature Gitea and Forgejo
+// compute with the webhook secret. Requests for a trigger protected by a secret
+// must be signed.
+func verifySignature(req *http.Request, trigger *buildv1.WebHookTrigger, body []byte) error {
+	if !webhook.HasSecret(trigger) {
+		return nil
+	}
+	signature := getSignature(req.Header)
+	secret, ok := webhook.SecretFrom(req.Context())
+	if len(signature) == 0 || !ok {
+		return webhook.ErrSecretMismatch
+	}
+	if !webhook.VerifyHMACSHA256(secret, body, signature) {
+		return webhook.ErrSecretMismatch
+	}
+	return nil
+}
+
+func getEvent(header http.Header) string {
+	event := header.Get("X-Gitea-Event")
+	if len(event) == 0 {
+		event = header.Get("X-Forgejo-Event")
+	}
+	return event
+}
+
+func getSignature(header http.Header) string {
+	signature := header.Get("X-Gitea-Signature")
+	if len(signature) == 0 {
+		signature = header.Get("X-Forgejo-Signature")
+	}
+	return signature
+}
@@ -0,0 +1,231 @@
+package gitea
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/webhook"
+)
+
+var mockBuildStrategy = buildv1.BuildStrategy{
+	SourceStrategy: &buildv1.SourceBuildStrategy{
+		From: corev1.ObjectReference{
+			Kind: "DockerImage",
+			Name: "repository/image",
+		},
+	},
+}
+
+func newBuildConfig(ref string) *buildv1.BuildConfig {
+	return &buildv1.BuildConfig{
+		Spec: buildv1.BuildConfigSpec{
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{
+					Type: buildv1.GiteaWebHookBuildTriggerType,
+					GiteaWebHook: &buildv1.WebHookTrigger{
+						Secret: "secret100",
+					},
+				},
+			},
+			CommonSpec: buildv1.CommonSpec{
+				Source: buildv1.BuildSource{
+					Git: &buildv1.GitBuildSource{
+						URI: "http://localhost:3000/gitea/webhooks.git",
+						Ref: ref,
+					},
+				},
+				Strategy: mockBuildStrategy,
+			},
+		},
+	}
+}
+
+func postFile(t *testing.T, eventHeader, eventName, filename string) *http.Request {
+	data, err := ioutil.ReadFile("testdata/" + filename)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", filename, err)
+	}
+	req, err := http.NewRequest("POST", "http://some.url", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error creating POST request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add(eventHeader, eventName)
+	req.Header.Add("X-Gitea-Signature", sign(t, "secret100", filename))
+	return req.WithContext(webhook.WithSecret(req.Context(), "secret100"))
+}
+
+func sign(t *testing.T, secret, filename string) string {
+	data, err := ioutil.ReadFile("testdata/" + filename)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", filename, err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestGetTriggers(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	triggers, err := New().GetTriggers(buildConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].Secret != "secret100" {
+		t.Errorf("Expected the gitea trigger, got %#v", triggers)
+	}
+
+	buildConfig.Spec.Triggers[0].Type = buildv1.GenericWebHookBuildTriggerType
+	if _, err := New().GetTriggers(buildConfig); err != webhook.ErrHookNotEnabled {
+		t.Errorf("Expected %v, got %v", webhook.ErrHookNotEnabled, err)
+	}
+}
+
+func TestVerifyRequestForMethod(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req, _ := http.NewRequest("GET", "http://someurl.com", nil)
+	revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+	if err != webhook.MethodNotSupported {
+		t.Errorf("Expected unsupported HTTP method, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+	if revision != nil {
+		t.Error("Expected the 'revision' return value to be nil")
+	}
+}
+
+func TestMissingEvent(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req, _ := http.NewRequest("POST", "http://someurl.com", nil)
+	req.Header.Add("Content-Type", "application/json")
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+	if err == nil || !strings.Contains(err.Error(), "missing X-Gitea-Event or X-Forgejo-Event") {
+		t.Errorf("Expected missing X-Gitea-Event, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+}
+
+func TestWrongEvent(t *testing.T) {
+	buildConfig := newBuildConfig("")
+	req := postFile(t, "X-Gitea-Event", "issues", "pushevent.json")
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+	if err == nil || !strings.Contains(err.Error(), "Unknown X-Gitea-Event or X-Forgejo-Event") {
+		t.Errorf("Expected Unknown X-Gitea-Event, got %v", err)
+	}
+	if proceed {
+		t.Error("Expected 'proceed' return value to be 'false'")
+	}
+}
+
+func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
+	for _, header := range []string{"X-Gitea-Event", "X-Forgejo-Event"} {
+		buildConfig := newBuildConfig("")
+		req := postFile(t, header, "push", "pushevent.json")
+		revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+		if err != nil {
+			t.Errorf("%s: error while extracting build info: %v", header, err)
+		}
+		if !proceed {
+			t.Errorf("%s: the 'proceed' return value should equal 'true'", header)
+		}
+		if revision == nil {
+			t.Fatalf("%s: expecting the revision to not be nil", header)
+		}
+		if revision.Git.Commit != "bffeb74224043ba2feb48d137756c8a9331c449a" {
+			t.Errorf("%s: expecting the revision to contain the commit id from the push event, got %q", header, revision.Git.Commit)
+		}
+		if revision.Git.Author.Email != "someone@gitea.io" {
+			t.Errorf("%s: expecting the revision to contain the author from the push event, got %#v", header, revision.Git.Author)
+		}
+	}
+}
+
+func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
+	buildConfig := newBuildConfig("my_other_branch")
+	req := postFile(t, "X-Gitea-Event", "push", "pushevent-not-master-branch.json")
+	revision, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+	if err != nil {
+		t.Errorf("Error while extracting build info: %v", err)
+	}
+	if !proceed {
+		t.Error("The 'proceed' return value should equal 'true'")
+	}
+	if revision == nil {
+		t.Fatal("Expecting the revision to not be nil")
+	}
+}
+
+func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
+	buildConfig := newBuildConfig("wrongref")
+	req := postFile(t, "X-Gitea-Event", "push", "pushevent.json")
+	_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+	if err != nil {
+		t.Errorf("Error while extracting build info: %v", err)
+	}
+	if proceed {
+		t.Errorf("Expecting to not continue from this event because the branch is not for this buildConfig '%s'", buildConfig.Spec.Source.Git.Ref)
+	}
+}
+
+func TestExtractVerifiesSignature(t *testing.T) {
+	testCases := map[string]struct {
+		header    string
+		signature string
+		proceed   bool
+	}{
+		"valid gitea signature": {
+			header:    "X-Gitea-Signature",
+			signature: sign(t, "secret100", "pushevent.json"),
+			proceed:   true,
+		},
+		"valid forgejo signature": {
+			header:    "X-Forgejo-Signature",
+			signature: sign(t, "secret100", "pushevent.json"),
+			proceed:   true,
+		},
+		"signature with another secret": {
+			header:    "X-Gitea-Signature",
+			signature: sign(t, "othersecret", "pushevent.json"),
+		},
+		"malformed signature": {
+			header:    "X-Gitea-Signature",
+			signature: "not-hex",
+		},
+		"missing signature": {},
+	}
+	for name, tc := range testCases {
+		buildConfig := newBuildConfig("")
+		req := postFile(t, "X-Gitea-Event", "push", "pushevent.json")
+		req.Header.Del("X-Gitea-Signature")
+		if len(tc.header) > 0 {
+			req.Header.Add(tc.header, tc.signature)
+		}
+		_, _, _, _, proceed, err := New().Extract(buildConfig, buildConfig.Spec.Triggers[0].GiteaWebHook, req)
+
+		if proceed != tc.proceed {
+			t.Errorf("%s: expected proceed %t, got %t", name, tc.proceed, proceed)
+		}
+		if !tc.proceed && err != webhook.ErrSecretMismatch {
+			t.Errorf("%s: expected %v, got %v", name, webhook.ErrSecretMismatch, err)
+		}
+	}
+}
@@ -38,29 +38,31 @@ type pushEvent struct {
 }
 
 // Extract services webhooks from github.com
-func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, proceed bool, err error) {
+func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (revision *buildv1.SourceRevision, envvars []corev1.EnvVar, dockerStrategyOptions *buildv1.DockerStrategyOptions, parameters map[string]string, proceed bool, err error) {
 	klog.V(4).Infof("Verifying build request for BuildConfig %s/%s", buildCfg.Namespace, buildCfg.Name)
 	if err = verifyRequest(req); err != nil {
-		return revision, envvars, dockerStrategyOptions, proceed, err
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
 	}
 	method := getEvent(req.Header)
+	webhook.SetDeliveryEvent(req, method, "")
 	if method != "ping" && method != "push" {
-		return revision, envvars, dockerStrategyOptions, proceed, errors.NewBadRequest(fmt.Sprintf("Unknown X-GitHub-Event or X-Gogs-Event %s", method))
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(fmt.Sprintf("Unknown X-GitHub-Event or X-Gogs-Event %s", method))
 	}
 	if method == "ping" {
-		return revision, envvars, dockerStrategyOptions, proceed, err
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
 	}
 	body, err := ioutil.ReadAll(req.Body)
 	if err != nil {
-		return revision, envvars, dockerStrategyOptions, proceed, errors.NewBadRequest(err.Error())
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
 	}
 	var event pushEvent
 	if err = json.Unmarshal(body, &event); err != nil {
-		return revision, envvars, dockerStrategyOptions, proceed, errors.NewBadRequest(err.Error())
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, errors.NewBadRequest(err.Error())
 	}
+	webhook.SetDeliveryEvent(req, "", event.Ref)
 	if !webhook.GitRefMatches(event.Ref, webhook.DefaultConfigRef, &buildCfg.Spec.Source) {
 		klog.V(2).Infof("Skipping build for BuildConfig %s/%s.  Branch reference from '%s' does not match configuration", buildCfg.Namespace, buildCfg.Name, event)
-		return revision, envvars, dockerStrategyOptions, proceed, err
+		return revision, envvars, dockerStrategyOptions, parameters, proceed, err
 	}
 
 	revision = &buildv1.SourceRevision{
@@ -71,7 +73,7 @@ func (p *WebHookPlugin) Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.
 			Message:   event.HeadCommit.Message,
 		},
 	}
-	return revision, envvars, dockerStrategyOptions, true, err
+	return revision, envvars, dockerStrategyOptions, parameters, true, err
 }
 
 // GetTriggers retrieves the WebHookTriggers for this webhook type (if any)
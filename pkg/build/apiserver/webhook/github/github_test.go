@@ -47,7 +47,7 @@ func GivenRequest(method string) *http.Request {
 func TestVerifyRequestForMethod(t *testing.T) {
 	req := GivenRequest("GET")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitHubWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitHubWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unsupported HTTP method") {
 		t.Errorf("Expected unsupported HTTP method, got %v", err)
@@ -64,7 +64,7 @@ func TestMissingEvent(t *testing.T) {
 	req := GivenRequest("POST")
 	req.Header.Add("Content-Type", "application/json")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "missing X-GitHub-Event or X-Gogs-Event") {
 		t.Errorf("Expected missing X-GitHub-Event or X-Gogs-Event, got %v", err)
@@ -82,7 +82,7 @@ func TestWrongGitHubEvent(t *testing.T) {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("X-GitHub-Event", "wrong")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "Unknown X-GitHub-Event or X-Gogs-Event") {
 		t.Errorf("Expected missing Unknown X-GitHub-Event or X-Gogs-Event, got %v", err)
@@ -98,7 +98,7 @@ func TestWrongGitHubEvent(t *testing.T) {
 func TestJsonPingEvent(t *testing.T) {
 	req := postFile("X-GitHub-Event", "ping", "pingevent.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -111,7 +111,7 @@ func TestJsonPingEvent(t *testing.T) {
 func TestJsonPushEventError(t *testing.T) {
 	req := post("X-GitHub-Event", "push", []byte{}, "http://some.url", http.StatusBadRequest, t)
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unexpected end of JSON input") {
 		t.Errorf("Expected unexpected end of JSON input, got %v", err)
@@ -127,7 +127,7 @@ func TestJsonPushEventError(t *testing.T) {
 func TestJsonGitHubPushEvent(t *testing.T) {
 	req := postFile("X-GitHub-Event", "push", "pushevent.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -140,7 +140,7 @@ func TestJsonGitHubPushEvent(t *testing.T) {
 func TestJsonGitHubPushEventWithCharset(t *testing.T) {
 	req := postFileWithCharset("X-GitHub-Event", "push", "pushevent.json", "http://some.url", "application/json; charset=utf-8", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -153,7 +153,7 @@ func TestJsonGitHubPushEventWithCharset(t *testing.T) {
 func TestJsonGogsPushEvent(t *testing.T) {
 	req := postFile("X-Gogs-Event", "push", "pushevent.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -257,7 +257,7 @@ func TestExtractForAPingEvent(t *testing.T) {
 	context := setup(t, "pingevent.json", "ping", "")
 
 	//execute
-	_, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	_, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -273,7 +273,7 @@ func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
 	context := setup(t, "pushevent.json", "push", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -295,7 +295,7 @@ func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
 	//setup
 	context := setup(t, "pushevent-not-master-branch.json", "push", "my_other_branch")
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -317,7 +317,7 @@ func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
 	context := setup(t, "pushevent.json", "push", "wrongref")
 
 	//execute
-	_, _, _, proceed, _ := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	_, _, _, _, proceed, _ := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 	if proceed {
 		t.Errorf("Expecting to not continue from this event because the branch is not for this buildConfig '%s'", context.buildCfg.Spec.Source.Git.Ref)
 	}
@@ -47,7 +47,7 @@ func GivenRequest(method string) *http.Request {
 func TestVerifyRequestForMethod(t *testing.T) {
 	req := GivenRequest("GET")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unsupported HTTP method") {
 		t.Errorf("Expected unsupported HTTP method, got %v", err)
@@ -64,7 +64,7 @@ func TestMissingEvent(t *testing.T) {
 	req := GivenRequest("POST")
 	req.Header.Add("Content-Type", "application/json")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "missing X-Gitlab-Event") {
 		t.Errorf("Expected missing X-Gitlab-Event, got %v", err)
@@ -82,7 +82,7 @@ func TestWrongGitLabEvent(t *testing.T) {
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("X-Gitlab-Event", "wrong")
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "Unknown X-Gitlab-Event") {
 		t.Errorf("Expected missing Unknown X-Gitlab-Event, got %v", err)
@@ -98,7 +98,7 @@ func TestWrongGitLabEvent(t *testing.T) {
 func TestJsonPushEventError(t *testing.T) {
 	req := post("X-Gitlab-Event", "Push Hook", []byte{}, "http://some.url", http.StatusBadRequest, t)
 	plugin := New()
-	revision, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	revision, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err == nil || !strings.Contains(err.Error(), "unexpected end of JSON input") {
 		t.Errorf("Expected unexpected end of JSON input, got %v", err)
@@ -114,7 +114,7 @@ func TestJsonPushEventError(t *testing.T) {
 func TestJsonGitLabPushEvent(t *testing.T) {
 	req := postFile("X-Gitlab-Event", "Push Hook", "pushevent.json", "http://some.url", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -127,7 +127,7 @@ func TestJsonGitLabPushEvent(t *testing.T) {
 func TestJsonGitLabPushEventWithCharset(t *testing.T) {
 	req := postFileWithCharset("X-Gitlab-Event", "Push Hook", "pushevent.json", "http://some.url", "application/json; charset=utf-8", http.StatusOK, t)
 	plugin := New()
-	_, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
+	_, _, _, _, proceed, err := plugin.Extract(buildConfig, buildConfig.Spec.Triggers[0].GitLabWebHook, req)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -231,7 +231,7 @@ func TestExtractProvidesValidBuildForAPushEvent(t *testing.T) {
 	context := setup(t, "pushevent.json", "Push Hook", "")
 
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -253,7 +253,7 @@ func TestExtractProvidesValidBuildForAPushEventOtherThanMaster(t *testing.T) {
 	//setup
 	context := setup(t, "pushevent-not-master-branch.json", "Push Hook", "my_other_branch")
 	//execute
-	revision, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	revision, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 
 	//validation
 	if err != nil {
@@ -275,7 +275,7 @@ func TestExtractSkipsBuildForUnmatchedBranches(t *testing.T) {
 	context := setup(t, "pushevent.json", "Push Hook", "wrongref")
 
 	//execute
-	_, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
+	_, _, _, _, proceed, err := context.plugin.Extract(context.buildCfg, buildConfig.Spec.Triggers[0].GitLabWebHook, context.req)
 	if err != nil {
 		t.Errorf("Error while extracting build info: %s", err)
 	}
@@ -3,6 +3,8 @@ package webhook
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"strings"
@@ -35,9 +37,11 @@ var (
 type Plugin interface {
 	// Method extracts build information and returns:
 	// - newly created build object or nil if default is to be created
+	// - the values requested for the parameters of the build configuration, which
+	//   only the generic webhook supports; other plugins return nil
 	// - information whether to trigger the build itself
 	// - eventual error.
-	Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision, []corev1.EnvVar, *buildv1.DockerStrategyOptions, bool, error)
+	Extract(buildCfg *buildv1.BuildConfig, trigger *buildv1.WebHookTrigger, req *http.Request) (*buildv1.SourceRevision, []corev1.EnvVar, *buildv1.DockerStrategyOptions, map[string]string, bool, error)
 	GetTriggers(buildConfig *buildv1.BuildConfig) ([]*buildv1.WebHookTrigger, error)
 }
 
@@ -90,6 +94,38 @@ func CheckSecret(ctx context.Context, namespace, userSecret string, triggers []*
 	return nil, ErrSecretMismatch
 }
 
+// HasSecret returns true if trigger is protected by a secret, either inline or
+// through a secret reference.
+func HasSecret(trigger *buildv1.WebHookTrigger) bool {
+	return trigger != nil && (len(trigger.Secret) > 0 || trigger.SecretReference != nil)
+}
+
+type secretKey struct{}
+
+// WithSecret returns a copy of ctx carrying the webhook secret accepted for the
+// request, so plugins can verify payload signatures computed with it.
+func WithSecret(ctx context.Context, secret string) context.Context {
+	return context.WithValue(ctx, secretKey{}, secret)
+}
+
+// SecretFrom returns the accepted webhook secret carried by ctx, if any.
+func SecretFrom(ctx context.Context) (string, bool) {
+	secret, ok := ctx.Value(secretKey{}).(string)
+	return secret, ok
+}
+
+// VerifyHMACSHA256 checks that signature is the hex encoded HMAC-SHA256 of body
+// keyed with secret.
+func VerifyHMACSHA256(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
 func GenerateBuildTriggerInfo(revision *buildv1.SourceRevision, hookType string) (buildTriggerCauses []buildv1.BuildTriggerCause) {
 	hiddenSecret := "<secret>"
 	switch {
@@ -122,6 +158,28 @@ func GenerateBuildTriggerInfo(revision *buildv1.SourceRevision, hookType string)
 					},
 				},
 			})
+	case hookType == "gitea":
+		buildTriggerCauses = append(buildTriggerCauses,
+			buildv1.BuildTriggerCause{
+				Message: apiserverbuildutil.BuildTriggerCauseGiteaMsg,
+				GiteaWebHook: &buildv1.GiteaWebHookCause{
+					CommonWebHookCause: buildv1.CommonWebHookCause{
+						Revision: revision,
+						Secret:   hiddenSecret,
+					},
+				},
+			})
+	case hookType == "azuredevops":
+		buildTriggerCauses = append(buildTriggerCauses,
+			buildv1.BuildTriggerCause{
+				Message: apiserverbuildutil.BuildTriggerCauseAzureDevOpsMsg,
+				AzureDevOpsWebHook: &buildv1.AzureDevOpsWebHookCause{
+					CommonWebHookCause: buildv1.CommonWebHookCause{
+						Revision: revision,
+						Secret:   hiddenSecret,
+					},
+				},
+			})
 	case hookType == "bitbucket":
 		buildTriggerCauses = append(buildTriggerCauses,
 			buildv1.BuildTriggerCause{
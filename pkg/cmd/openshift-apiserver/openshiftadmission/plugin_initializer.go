@@ -17,6 +17,7 @@ import (
 	kexternalinformers "k8s.io/client-go/informers"
 	kubeclientgoclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/component-base/featuregate"
 	aggregatorapiserver "k8s.io/kube-aggregator/pkg/apiserver"
 	controlplaneadmission "k8s.io/kubernetes/pkg/controlplane/apiserver/admission"
@@ -45,6 +46,7 @@ type InformerAccess interface {
 	GetOpenshiftQuotaInformers() quotainformer.SharedInformerFactory
 	GetOpenshiftRouteInformers() routeinformers.SharedInformerFactory
 	GetOpenshiftSecurityInformers() securityv1informer.SharedInformerFactory
+	GetBuildStrategyPolicyInformer() cache.SharedIndexInformer
 }
 
 func NewPluginInitializer(
@@ -108,7 +110,7 @@ func NewPluginInitializer(
 		nil,
 	)
 
-	openshiftPluginInitializer := openshiftapiserveradmission.NewOpenShiftInformersInitializer(informers.GetOpenshiftConfigInformers(), informers.GetOpenshiftRouteInformers())
+	openshiftPluginInitializer := openshiftapiserveradmission.NewOpenShiftInformersInitializer(informers.GetOpenshiftConfigInformers(), informers.GetOpenshiftRouteInformers(), informers.GetBuildStrategyPolicyInformer())
 
 	webhookAuthResolverWrapper := func(delegate webhook.AuthenticationInfoResolver) webhook.AuthenticationInfoResolver {
 		return &webhook.AuthenticationInfoResolverDelegator{
@@ -261,6 +261,9 @@ func NewOpenshiftAPIConfig(config *openshiftcontrolplanev1.OpenShiftAPIServerCon
 			ConfigInformers:                    informers.configInformers,
 			RuleResolver:                       ruleResolver,
 			SubjectLocator:                     subjectLocator,
+			BuildLogArchiveConfig:              config.BuildLogArchiveConfig,
+			BuildProvenanceConfig:              config.BuildProvenanceConfig,
+			BinaryBuildUploadConfig:            config.BinaryBuildUploadConfig,
 			RegistryHostnameRetriever:          registryHostnameRetriever,
 			AllowedRegistriesForImport:         config.ImagePolicyConfig.AllowedRegistriesForImport,
 			MaxImagesBulkImportedPerRepository: config.ImagePolicyConfig.MaxImagesBulkImportedPerRepository,
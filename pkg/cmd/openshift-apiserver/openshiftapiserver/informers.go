@@ -3,8 +3,10 @@ package openshiftapiserver
 import (
 	"time"
 
+	buildv1 "github.com/openshift/api/build/v1"
 	authorizationv1client "github.com/openshift/client-go/authorization/clientset/versioned"
 	authorizationv1informer "github.com/openshift/client-go/authorization/informers/externalversions"
+	buildv1client "github.com/openshift/client-go/build/clientset/versioned"
 	configv1client "github.com/openshift/client-go/config/clientset/versioned"
 	configv1informer "github.com/openshift/client-go/config/informers/externalversions"
 	imagev1client "github.com/openshift/client-go/image/clientset/versioned"
@@ -17,8 +19,11 @@ import (
 	routev1informer "github.com/openshift/client-go/route/informers/externalversions"
 	securityv1client "github.com/openshift/client-go/security/clientset/versioned"
 	securityv1informer "github.com/openshift/client-go/security/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	kexternalinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 )
 
 // informerHolder is a convenient way for us to keep track of the informers, but
@@ -35,6 +40,9 @@ type InformerHolder struct {
 	routeInformers         routev1informer.SharedInformerFactory
 	securityInformers      securityv1informer.SharedInformerFactory
 	operatorInformers      operatorinformers.SharedInformerFactory
+
+	// buildStrategyPolicyInformer has no generated informer factory yet.
+	buildStrategyPolicyInformer cache.SharedIndexInformer
 }
 
 // NewInformers is only exposed for the build's integration testing until it can be fixed more appropriately.
@@ -43,6 +51,10 @@ func NewInformers(kubeInformers kexternalinformers.SharedInformerFactory, kubeCl
 	if err != nil {
 		return nil, err
 	}
+	buildClient, err := buildv1client.NewForConfig(loopbackClientConfig)
+	if err != nil {
+		return nil, err
+	}
 	configClient, err := configv1client.NewForConfig(nonProtobufConfig(kubeClientConfig))
 	if err != nil {
 		return nil, err
@@ -81,6 +93,12 @@ func NewInformers(kubeInformers kexternalinformers.SharedInformerFactory, kubeCl
 		routeInformers:         routev1informer.NewSharedInformerFactory(routerClient, defaultInformerResyncPeriod),
 		securityInformers:      securityv1informer.NewSharedInformerFactory(securityClient, defaultInformerResyncPeriod),
 		operatorInformers:      operatorinformers.NewSharedInformerFactory(operatorClient, defaultInformerResyncPeriod),
+		buildStrategyPolicyInformer: cache.NewSharedIndexInformer(
+			cache.NewListWatchFromClient(buildClient.BuildV1().RESTClient(), "buildstrategypolicies", metav1.NamespaceAll, fields.Everything()),
+			&buildv1.BuildStrategyPolicy{},
+			defaultInformerResyncPeriod,
+			cache.Indexers{},
+		),
 	}, nil
 }
 
@@ -114,6 +132,9 @@ func (i *InformerHolder) GetOpenshiftRouteInformers() routev1informer.SharedInfo
 func (i *InformerHolder) GetOpenshiftSecurityInformers() securityv1informer.SharedInformerFactory {
 	return i.securityInformers
 }
+func (i *InformerHolder) GetBuildStrategyPolicyInformer() cache.SharedIndexInformer {
+	return i.buildStrategyPolicyInformer
+}
 
 // Start initializes all requested informers.
 func (i *InformerHolder) Start(stopCh <-chan struct{}) {
@@ -125,4 +146,5 @@ func (i *InformerHolder) Start(stopCh <-chan struct{}) {
 	i.routeInformers.Start(stopCh)
 	i.securityInformers.Start(stopCh)
 	i.operatorInformers.Start(stopCh)
+	go i.buildStrategyPolicyInformer.Run(stopCh)
 }
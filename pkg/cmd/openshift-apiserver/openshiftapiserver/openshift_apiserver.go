@@ -43,6 +43,8 @@ import (
 	authorizationapiserver "github.com/openshift/openshift-apiserver/pkg/authorization/apiserver"
 	"github.com/openshift/openshift-apiserver/pkg/bootstrappolicy"
 	buildapiserver "github.com/openshift/openshift-apiserver/pkg/build/apiserver"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/logarchive"
+	"github.com/openshift/openshift-apiserver/pkg/build/apiserver/provenance"
 	"github.com/openshift/openshift-apiserver/pkg/cmd/openshift-apiserver/openshiftapiserver/configprocessing"
 	apisimage "github.com/openshift/openshift-apiserver/pkg/image/apis/image"
 	imageapiserver "github.com/openshift/openshift-apiserver/pkg/image/apiserver"
@@ -81,6 +83,12 @@ type OpenshiftAPIExtraConfig struct {
 	RuleResolver   rbacregistryvalidation.AuthorizationRuleResolver
 	SubjectLocator rbacauthorizer.SubjectLocator
 
+	// for Builds
+	BuildLogArchiveConfig openshiftcontrolplanev1.BuildLogArchiveConfig
+	BuildProvenanceConfig openshiftcontrolplanev1.BuildProvenanceConfig
+	// BinaryBuildUploadConfig configures the staging of resumable binary build uploads.
+	BinaryBuildUploadConfig openshiftcontrolplanev1.BinaryBuildUploadConfig
+
 	// for Images
 	// RegistryHostnameRetriever retrieves the internal and external hostname of
 	// the integrated registry, or false if no such registry is available.
@@ -191,9 +199,11 @@ func (c *completedConfig) withAppsAPIServer(delegateAPIServer genericapiserver.D
 	cfg := &oappsapiserver.AppsServerConfig{
 		GenericConfig: &genericapiserver.RecommendedConfig{Config: shallowCopyAndSanitizeGenericConfig(c.GenericConfig.Config), SharedInformerFactory: c.GenericConfig.SharedInformerFactory},
 		ExtraConfig: oappsapiserver.ExtraConfig{
-			KubeAPIServerClientConfig: c.ExtraConfig.KubeAPIServerClientConfig,
-			Codecs:                    legacyscheme.Codecs,
-			Scheme:                    legacyscheme.Scheme,
+			KubeAPIServerClientConfig:  c.ExtraConfig.KubeAPIServerClientConfig,
+			RegistryHostnameRetriever:  c.ExtraConfig.RegistryHostnameRetriever,
+			AllowedRegistriesForImport: c.ExtraConfig.AllowedRegistriesForImport,
+			Codecs:                     legacyscheme.Codecs,
+			Scheme:                     legacyscheme.Scheme,
 		},
 	}
 	// server is required to install OpenAPI to register and serve openapi spec for its types
@@ -231,10 +241,22 @@ func (c *completedConfig) withAuthorizationAPIServer(delegateAPIServer genericap
 }
 
 func (c *completedConfig) withBuildAPIServer(delegateAPIServer genericapiserver.DelegationTarget) (genericapiserver.DelegationTarget, error) {
+	buildLogArchive, err := logarchive.FromConfig(c.ExtraConfig.BuildLogArchiveConfig)
+	if err != nil {
+		return nil, err
+	}
+	buildProvenanceSigner, err := provenance.SignerFromConfig(c.ExtraConfig.BuildProvenanceConfig)
+	if err != nil {
+		return nil, err
+	}
 	cfg := &buildapiserver.BuildServerConfig{
 		GenericConfig: &genericapiserver.RecommendedConfig{Config: shallowCopyAndSanitizeGenericConfig(c.GenericConfig.Config), SharedInformerFactory: c.GenericConfig.SharedInformerFactory},
 		ExtraConfig: buildapiserver.ExtraConfig{
 			KubeAPIServerClientConfig: c.ExtraConfig.KubeAPIServerClientConfig,
+			BuildLogArchive:           buildLogArchive,
+			BuildLogArchiveRetention:  c.ExtraConfig.BuildLogArchiveConfig.Retention.Duration,
+			BuildProvenanceSigner:     buildProvenanceSigner,
+			BinaryBuildUploadConfig:   c.ExtraConfig.BinaryBuildUploadConfig,
 			Codecs:                    legacyscheme.Codecs,
 			Scheme:                    legacyscheme.Scheme,
 		},
@@ -372,6 +394,7 @@ func (c *completedConfig) withTemplateAPIServer(delegateAPIServer genericapiserv
 		GenericConfig: &genericapiserver.RecommendedConfig{Config: shallowCopyAndSanitizeGenericConfig(c.GenericConfig.Config), SharedInformerFactory: c.GenericConfig.SharedInformerFactory},
 		ExtraConfig: templateapiserver.ExtraConfig{
 			KubeAPIServerClientConfig: c.ExtraConfig.KubeAPIServerClientConfig,
+			RESTMapper:                c.ExtraConfig.RESTMapper,
 			Codecs:                    legacyscheme.Codecs,
 			Scheme:                    legacyscheme.Scheme,
 		},
@@ -3,6 +3,7 @@ package template
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kubernetes/pkg/apis/core"
 )
 
@@ -77,8 +78,37 @@ type Parameter struct {
 
 	// Optional: Indicates the parameter must have a value.  Defaults to false.
 	Required bool
+
+	// Optional: Type of the value of the parameter, string if unset. Empty
+	// values are not constrained, use Required to reject them.
+	Type ParameterType
+
+	// Optional: Pattern is a regular expression the value must match.
+	Pattern string
+
+	// Optional: Enum lists the values allowed.
+	Enum []string
+
+	// Optional: Min and Max bound the value of int and quantity parameters,
+	// and the length of the value of string, dnsLabel, url and base64
+	// parameters.
+	Min *intstr.IntOrString
+	Max *intstr.IntOrString
 }
 
+// ParameterType is the type of the value of a template parameter.
+type ParameterType string
+
+const (
+	ParameterTypeString   ParameterType = "string"
+	ParameterTypeInt      ParameterType = "int"
+	ParameterTypeBool     ParameterType = "bool"
+	ParameterTypeQuantity ParameterType = "quantity"
+	ParameterTypeDNSLabel ParameterType = "dnsLabel"
+	ParameterTypeURL      ParameterType = "url"
+	ParameterTypeBase64   ParameterType = "base64"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -169,6 +199,11 @@ const (
 	// TemplateInstanceInstantiateFailure indicates the failure of the template
 	// instantiation
 	TemplateInstanceInstantiateFailure TemplateInstanceConditionType = "InstantiateFailure"
+	// TemplateInstanceUpgraded indicates the result of the latest upgrade of
+	// the template instantiation to a new template through
+	// templateinstances/upgrade. It is Unknown while an upgrade is in progress,
+	// or if it was interrupted.
+	TemplateInstanceUpgraded TemplateInstanceConditionType = "Upgraded"
 )
 
 // TemplateInstanceObject references an object created by a TemplateInstance.
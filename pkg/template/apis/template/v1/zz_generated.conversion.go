@@ -13,6 +13,7 @@ import (
 	apicorev1 "k8s.io/api/core/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	core "k8s.io/kubernetes/pkg/apis/core"
 	corev1 "k8s.io/kubernetes/pkg/apis/core/v1"
 )
@@ -265,6 +266,11 @@ func autoConvert_v1_Parameter_To_template_Parameter(in *v1.Parameter, out *templ
 	out.Generate = in.Generate
 	out.From = in.From
 	out.Required = in.Required
+	out.Type = template.ParameterType(in.Type)
+	out.Pattern = in.Pattern
+	out.Enum = *(*[]string)(unsafe.Pointer(&in.Enum))
+	out.Min = (*intstr.IntOrString)(unsafe.Pointer(in.Min))
+	out.Max = (*intstr.IntOrString)(unsafe.Pointer(in.Max))
 	return nil
 }
 
@@ -281,6 +287,11 @@ func autoConvert_template_Parameter_To_v1_Parameter(in *template.Parameter, out
 	out.Generate = in.Generate
 	out.From = in.From
 	out.Required = in.Required
+	out.Type = v1.ParameterType(in.Type)
+	out.Pattern = in.Pattern
+	out.Enum = *(*[]string)(unsafe.Pointer(&in.Enum))
+	out.Min = (*intstr.IntOrString)(unsafe.Pointer(in.Min))
+	out.Max = (*intstr.IntOrString)(unsafe.Pointer(in.Max))
 	return nil
 }
 
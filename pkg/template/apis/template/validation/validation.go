@@ -1,10 +1,19 @@
 package validation
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 	kapihelper "k8s.io/kubernetes/pkg/apis/core/helper"
@@ -15,7 +24,8 @@ import (
 
 var ParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
 
-// ValidateParameter tests if required fields in the Parameter are set.
+// ValidateParameter tests if required fields in the Parameter are set and its
+// constraints are valid.
 func ValidateParameter(param *templateapi.Parameter, fldPath *field.Path) (allErrs field.ErrorList) {
 	if len(param.Name) == 0 {
 		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
@@ -24,6 +34,7 @@ func ValidateParameter(param *templateapi.Parameter, fldPath *field.Path) (allEr
 	if !ParameterNameRegexp.MatchString(param.Name) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), param.Name, fmt.Sprintf("does not match %v", ParameterNameRegexp)))
 	}
+	allErrs = append(allErrs, validateParameterConstraints(param, fldPath)...)
 	return
 }
 
@@ -51,6 +62,167 @@ func validateTemplateBody(template *templateapi.Template) (allErrs field.ErrorLi
 	for i := range template.Parameters {
 		allErrs = append(allErrs, ValidateParameter(&template.Parameters[i], field.NewPath("parameters").Index(i))...)
 	}
+	allErrs = append(allErrs, ValidateParameterValues(template)...)
+	return
+}
+
+var supportedParameterTypes = sets.NewString(
+	string(templateapi.ParameterTypeString),
+	string(templateapi.ParameterTypeInt),
+	string(templateapi.ParameterTypeBool),
+	string(templateapi.ParameterTypeQuantity),
+	string(templateapi.ParameterTypeDNSLabel),
+	string(templateapi.ParameterTypeURL),
+	string(templateapi.ParameterTypeBase64),
+)
+
+// HasParameterConstraints returns whether the value of param is constrained.
+func HasParameterConstraints(param *templateapi.Parameter) bool {
+	return len(param.Type) > 0 || len(param.Pattern) > 0 || len(param.Enum) > 0 || param.Min != nil || param.Max != nil
+}
+
+// validateParameterConstraints checks the type, pattern, enum, min and max of a
+// parameter.
+func validateParameterConstraints(param *templateapi.Parameter, fldPath *field.Path) (allErrs field.ErrorList) {
+	if len(param.Type) > 0 && !supportedParameterTypes.Has(string(param.Type)) {
+		return field.ErrorList{field.NotSupported(fldPath.Child("type"), param.Type, supportedParameterTypes.List())}
+	}
+	if len(param.Pattern) > 0 {
+		if _, err := regexp.Compile(param.Pattern); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("pattern"), param.Pattern, err.Error()))
+		}
+	}
+	if param.Type == templateapi.ParameterTypeBool {
+		if param.Min != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("min"), "not supported for bool parameters"))
+		}
+		if param.Max != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("max"), "not supported for bool parameters"))
+		}
+	} else {
+		min, err := parseParameterBound(param.Type, param.Min)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("min"), param.Min.String(), err.Error()))
+		}
+		max, err := parseParameterBound(param.Type, param.Max)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), param.Max.String(), err.Error()))
+		}
+		if min != nil && max != nil && min.Cmp(*max) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), param.Max.String(), "must be greater than or equal to min"))
+		}
+	}
+	if len(allErrs) == 0 {
+		for i, value := range param.Enum {
+			for _, msg := range ValidateParameterValue(param, value) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("enum").Index(i), value, msg))
+			}
+		}
+	}
+	return
+}
+
+// parseParameterBound returns the min or max bound of a parameter constraint of the
+// given type: a quantity for quantity parameters, an integer value or length
+// otherwise.
+func parseParameterBound(parameterType templateapi.ParameterType, bound *intstr.IntOrString) (*resource.Quantity, error) {
+	if bound == nil {
+		return nil, nil
+	}
+	if parameterType == templateapi.ParameterTypeQuantity {
+		quantity, err := resource.ParseQuantity(bound.String())
+		if err != nil {
+			return nil, err
+		}
+		return &quantity, nil
+	}
+	if bound.Type == intstr.Int {
+		return resource.NewQuantity(int64(bound.IntVal), resource.DecimalSI), nil
+	}
+	value, err := strconv.ParseInt(bound.StrVal, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("must be an integer")
+	}
+	return resource.NewQuantity(value, resource.DecimalSI), nil
+}
+
+// ValidateParameterValue checks value against the constraints of param and
+// returns the reasons it is invalid.
+func ValidateParameterValue(param *templateapi.Parameter, value string) []string {
+	var size *resource.Quantity
+	switch param.Type {
+	case templateapi.ParameterTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return []string{"must be an integer"}
+		}
+		size = resource.NewQuantity(i, resource.DecimalSI)
+	case templateapi.ParameterTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return []string{"must be a boolean"}
+		}
+	case templateapi.ParameterTypeQuantity:
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return []string{"must be a quantity, e.g. 512Mi"}
+		}
+		size = &quantity
+	case templateapi.ParameterTypeDNSLabel:
+		if msgs := utilvalidation.IsDNS1123Label(value); len(msgs) > 0 {
+			return msgs
+		}
+	case templateapi.ParameterTypeURL:
+		if u, err := url.Parse(value); err != nil || !u.IsAbs() || len(u.Host) == 0 {
+			return []string{"must be an absolute URL"}
+		}
+	case templateapi.ParameterTypeBase64:
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return []string{"must be base64 encoded"}
+		}
+	}
+
+	var msgs []string
+	if param.Type != templateapi.ParameterTypeBool {
+		unit := ""
+		if size == nil {
+			size = resource.NewQuantity(int64(utf8.RuneCountInString(value)), resource.DecimalSI)
+			unit = " characters long"
+		}
+		if min, err := parseParameterBound(param.Type, param.Min); err == nil && min != nil && size.Cmp(*min) < 0 {
+			msgs = append(msgs, fmt.Sprintf("must be at least %s%s", min.String(), unit))
+		}
+		if max, err := parseParameterBound(param.Type, param.Max); err == nil && max != nil && size.Cmp(*max) > 0 {
+			msgs = append(msgs, fmt.Sprintf("must be at most %s%s", max.String(), unit))
+		}
+	}
+	if len(param.Pattern) > 0 {
+		if pattern, err := regexp.Compile(param.Pattern); err == nil && !pattern.MatchString(value) {
+			msgs = append(msgs, fmt.Sprintf("must match %q", param.Pattern))
+		}
+	}
+	if len(param.Enum) > 0 && !sets.NewString(param.Enum...).Has(value) {
+		msgs = append(msgs, fmt.Sprintf("must be one of %s", strings.Join(param.Enum, ", ")))
+	}
+	return msgs
+}
+
+// ValidateParameterValues checks the values of the parameters of a template against
+// their constraints. Parameters without a value or with invalid constraints are
+// not checked.
+func ValidateParameterValues(template *templateapi.Template) (allErrs field.ErrorList) {
+	for i := range template.Parameters {
+		param := &template.Parameters[i]
+		if len(param.Value) == 0 || !HasParameterConstraints(param) {
+			continue
+		}
+		fldPath := field.NewPath("parameters").Index(i)
+		if len(validateParameterConstraints(param, fldPath)) > 0 {
+			continue
+		}
+		for _, msg := range ValidateParameterValue(param, param.Value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), param.Value, msg))
+		}
+	}
 	return
 }
 
@@ -100,6 +272,21 @@ func ValidateTemplateInstanceUpdate(templateInstance, oldTemplateInstance *templ
 	return
 }
 
+// ValidateTemplateInstanceUpgrade tests if required fields in the TemplateInstance are set
+// after an upgrade to a new template, which may only change spec.template
+func ValidateTemplateInstanceUpgrade(templateInstance, oldTemplateInstance *templateapi.TemplateInstance) (allErrs field.ErrorList) {
+	allErrs = validation.ValidateObjectMetaUpdate(&templateInstance.ObjectMeta, &oldTemplateInstance.ObjectMeta, field.NewPath("metadata"))
+	allErrs = append(allErrs, ValidateTemplateInstance(templateInstance)...)
+
+	if !kapihelper.Semantic.DeepEqual(templateInstance.Spec.Secret, oldTemplateInstance.Spec.Secret) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.secret"), "field is immutable"))
+	}
+	if !kapihelper.Semantic.DeepEqual(templateInstance.Spec.Requester, oldTemplateInstance.Spec.Requester) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec.requester"), "field is immutable"))
+	}
+	return
+}
+
 var ValidateBrokerTemplateInstanceName = apimachineryvalidation.NameIsDNSSubdomain
 
 // ValidateBrokerTemplateInstance tests if required fields in the BrokerTemplateInstance are set.
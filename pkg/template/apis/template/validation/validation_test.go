@@ -5,6 +5,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	kapi "k8s.io/kubernetes/pkg/apis/core"
 
@@ -197,6 +198,145 @@ func TestValidateTemplate(t *testing.T) {
 	}
 }
 
+func TestValidateTemplateParameterConstraints(t *testing.T) {
+	intOrString := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+	var tests = []struct {
+		name           string
+		param          templateapi.Parameter
+		value          string
+		expectedFields []string
+	}{
+		{
+			name:  "no value",
+			param: templateapi.Parameter{Type: templateapi.ParameterTypeQuantity, Min: intOrString("64Mi"), Max: intOrString("1Gi")},
+		},
+		{
+			name:  "valid quantity",
+			param: templateapi.Parameter{Type: templateapi.ParameterTypeQuantity, Min: intOrString("64Mi"), Max: intOrString("1Gi")},
+			value: "512Mi",
+		},
+		{
+			name:           "invalid quantity",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeQuantity},
+			value:          "abc",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "quantity out of range",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeQuantity, Min: intOrString("64Mi"), Max: intOrString("1Gi")},
+			value:          "2Gi",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:  "valid int",
+			param: templateapi.Parameter{Type: templateapi.ParameterTypeInt, Min: intOrString("1"), Max: &intstr.IntOrString{Type: intstr.String, StrVal: "10"}},
+			value: "10",
+		},
+		{
+			name:           "int below min",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeInt, Min: intOrString("1")},
+			value:          "0",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "invalid bool",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeBool},
+			value:          "yes",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "invalid DNS label",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeDNSLabel},
+			value:          "my_host",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:  "valid URL",
+			param: templateapi.Parameter{Type: templateapi.ParameterTypeURL},
+			value: "https://example.com/path",
+		},
+		{
+			name:           "relative URL",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeURL},
+			value:          "/path",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "invalid base64",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeBase64},
+			value:          "not base64!",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "string too long and not matching the pattern",
+			param:          templateapi.Parameter{Pattern: "^[a-z]+$", Max: intOrString("3")},
+			value:          "abcD",
+			expectedFields: []string{"parameters[0].value", "parameters[0].value"},
+		},
+		{
+			name:           "value not in enum",
+			param:          templateapi.Parameter{Enum: []string{"small", "large"}},
+			value:          "medium",
+			expectedFields: []string{"parameters[0].value"},
+		},
+		{
+			name:           "unknown type",
+			param:          templateapi.Parameter{Type: "float"},
+			value:          "1.5",
+			expectedFields: []string{"parameters[0].type"},
+		},
+		{
+			name:           "invalid pattern",
+			param:          templateapi.Parameter{Pattern: "("},
+			value:          "abc",
+			expectedFields: []string{"parameters[0].pattern"},
+		},
+		{
+			name:           "min greater than max",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeInt, Min: intOrString("10"), Max: intOrString("1")},
+			value:          "5",
+			expectedFields: []string{"parameters[0].max"},
+		},
+		{
+			name:           "bounds on a bool",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeBool, Min: intOrString("1")},
+			expectedFields: []string{"parameters[0].min"},
+		},
+		{
+			name:           "invalid enum value",
+			param:          templateapi.Parameter{Type: templateapi.ParameterTypeInt, Enum: []string{"1", "two"}},
+			expectedFields: []string{"parameters[0].enum[1]"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			param := test.param
+			param.Name = "PARAM"
+			param.Value = test.value
+			template := &templateapi.Template{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "template",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Parameters: []templateapi.Parameter{param},
+			}
+			errs := ValidateTemplate(template)
+			if len(errs) != len(test.expectedFields) {
+				t.Fatalf("expected %d errors, got %v", len(test.expectedFields), errs)
+			}
+			for i, err := range errs {
+				if err.Field != test.expectedFields[i] {
+					t.Errorf("expected an error on %s, got %v", test.expectedFields[i], err)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateTemplateInstance(t *testing.T) {
 	var tests = []struct {
 		templateInstance  templateapi.TemplateInstance
@@ -463,6 +603,85 @@ func TestValidateTemplateInstanceUpdate(t *testing.T) {
 	}
 }
 
+func TestValidateTemplateInstanceUpgrade(t *testing.T) {
+	oldTemplateInstance := &templateapi.TemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "test",
+			Namespace:       "test",
+			ResourceVersion: "1",
+		},
+		Spec: templateapi.TemplateInstanceSpec{
+			Template: templateapi.Template{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "test",
+				},
+				Parameters: []templateapi.Parameter{
+					{
+						Name: "test",
+					},
+				},
+			},
+			Secret: &kapi.LocalObjectReference{
+				Name: "test",
+			},
+			Requester: &templateapi.TemplateInstanceRequester{
+				Username: "test",
+			},
+		},
+	}
+
+	var tests = []struct {
+		modifyTemplateInstance func(*templateapi.TemplateInstance)
+		expectedErrorType      field.ErrorType
+	}{
+		{
+			modifyTemplateInstance: func(new *templateapi.TemplateInstance) {
+				new.Spec.Template.Parameters = append(new.Spec.Template.Parameters, templateapi.Parameter{Name: "new"})
+				new.Spec.Template.Objects = []runtime.Object{&kapi.Service{}}
+			},
+		},
+		{
+			modifyTemplateInstance: func(new *templateapi.TemplateInstance) {
+				new.Spec.Template.Parameters[0].Name = "b@d"
+			},
+			expectedErrorType: field.ErrorTypeInvalid,
+		},
+		{
+			modifyTemplateInstance: func(new *templateapi.TemplateInstance) {
+				new.Spec.Secret.Name = "new"
+			},
+			expectedErrorType: field.ErrorTypeForbidden,
+		},
+		{
+			modifyTemplateInstance: func(new *templateapi.TemplateInstance) {
+				new.Spec.Requester.Username = "new"
+			},
+			expectedErrorType: field.ErrorTypeForbidden,
+		},
+	}
+
+	for i, test := range tests {
+		newTemplateInstance := oldTemplateInstance.DeepCopy()
+		test.modifyTemplateInstance(newTemplateInstance)
+		errs := ValidateTemplateInstanceUpgrade(newTemplateInstance, oldTemplateInstance)
+		if test.expectedErrorType == "" {
+			if len(errs) != 0 {
+				t.Errorf("%d: Unexpected non-empty error list: %v", i, errs.ToAggregate())
+			}
+		} else {
+			if len(errs) == 0 {
+				t.Errorf("%d: Unexpected empty error list", i)
+			}
+			for _, err := range errs {
+				if err.Type != test.expectedErrorType {
+					t.Errorf("%d: Unexpected error type: %v", i, errs.ToAggregate())
+				}
+			}
+		}
+	}
+}
+
 func TestValidateBrokerTemplateInstance(t *testing.T) {
 	var tests = []struct {
 		brokerTemplateInstance templateapi.BrokerTemplateInstance
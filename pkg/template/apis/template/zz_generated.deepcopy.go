@@ -7,6 +7,7 @@ package template
 
 import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	core "k8s.io/kubernetes/pkg/apis/core"
 )
 
@@ -116,6 +117,21 @@ func (in ExtraValue) DeepCopy() ExtraValue {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -137,7 +153,9 @@ func (in *Template) DeepCopyInto(out *Template) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make([]Parameter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Objects != nil {
 		in, out := &in.Objects, &out.Objects
@@ -3,12 +3,14 @@ package apiserver
 import (
 	"sync"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
 
 	templateapiv1 "github.com/openshift/api/template/v1"
@@ -20,6 +22,7 @@ import (
 
 type ExtraConfig struct {
 	KubeAPIServerClientConfig *restclient.Config
+	RESTMapper                meta.RESTMapper
 
 	// TODO these should all become local eventually
 	Scheme *runtime.Scheme
@@ -99,11 +102,16 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 		return nil, err
 	}
 
+	coreClient, err := corev1client.NewForConfig(c.ExtraConfig.KubeAPIServerClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	templateStorage, err := templateetcd.NewREST(c.GenericConfig.RESTOptionsGetter)
 	if err != nil {
 		return nil, err
 	}
-	templateInstanceStorage, templateInstanceStatusStorage, err := templateinstanceetcd.NewREST(c.GenericConfig.RESTOptionsGetter, authorizationClient)
+	templateInstanceStorage, templateInstanceStatusStorage, templateInstanceUpgradeStorage, err := templateinstanceetcd.NewREST(c.GenericConfig.RESTOptionsGetter, authorizationClient, coreClient, c.ExtraConfig.KubeAPIServerClientConfig, c.ExtraConfig.RESTMapper)
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +125,7 @@ func (c *completedConfig) newV1RESTStorage() (map[string]rest.Storage, error) {
 	v1Storage["templates"] = templateStorage
 	v1Storage["templateinstances"] = templateInstanceStorage
 	v1Storage["templateinstances/status"] = templateInstanceStatusStorage
+	v1Storage["templateinstances/upgrade"] = templateInstanceUpgradeStorage
 	v1Storage["brokertemplateinstances"] = brokerTemplateInstanceStorage
 	return v1Storage, nil
 }
@@ -74,6 +74,19 @@ func (s *REST) Create(ctx context.Context, obj runtime.Object, _ rest.ValidateOb
 		"expression": generator.NewExpressionValueGenerator(rand.New(rand.NewSource(time.Now().UnixNano()))),
 	}
 	processor := templateprocessing.NewProcessor(generators)
+	if errs := processor.GenerateParameterValues(externalTemplate); len(errs) > 0 {
+		klog.V(1).Infof(errs.ToAggregate().Error())
+		return nil, errors.NewInvalid(template.Kind("Template"), tpl.Name, errs)
+	}
+	// check the generated values against the parameter constraints before they are
+	// substituted
+	generatedTemplate := tpl.DeepCopy()
+	for i := range externalTemplate.Parameters {
+		generatedTemplate.Parameters[i].Value = externalTemplate.Parameters[i].Value
+	}
+	if errs := templatevalidation.ValidateParameterValues(generatedTemplate); len(errs) > 0 {
+		return nil, errors.NewInvalid(template.Kind("Template"), tpl.Name, errs)
+	}
 	if errs := processor.Process(externalTemplate); len(errs) > 0 {
 		klog.V(1).Infof(errs.ToAggregate().Error())
 		return nil, errors.NewInvalid(template.Kind("Template"), tpl.Name, errs)
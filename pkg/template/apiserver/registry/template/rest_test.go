@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -53,6 +54,31 @@ func TestNewRESTInvalidParameter(t *testing.T) {
 	}
 }
 
+func TestNewRESTParameterConstraints(t *testing.T) {
+	storage := NewREST()
+	newTemplate := func(value string) *template.Template {
+		return &template.Template{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Parameters: []template.Parameter{
+				{Name: "MEMORY", Value: value, Type: template.ParameterTypeQuantity},
+				{Name: "SUFFIX", Generate: "expression", From: "[a-z]{8}", Pattern: "^[0-9]+$"},
+			},
+		}
+	}
+
+	_, err := storage.Create(nil, newTemplate("abc"), rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if statusErr, ok := err.(*errors.StatusError); !ok || statusErr.ErrStatus.Details.Causes[0].Field != "parameters[0].value" {
+		t.Errorf("expected an invalid parameter value, got %v", err)
+	}
+
+	_, err = storage.Create(nil, newTemplate("512Mi"), rest.ValidateAllObjectFunc, &metav1.CreateOptions{})
+	if statusErr, ok := err.(*errors.StatusError); !ok || statusErr.ErrStatus.Details.Causes[0].Field != "parameters[1].value" {
+		t.Errorf("expected an invalid generated parameter value, got %v", err)
+	}
+}
+
 func TestNewRESTTemplateLabels(t *testing.T) {
 	testLabels := map[string]string{
 		"label1": "value1",
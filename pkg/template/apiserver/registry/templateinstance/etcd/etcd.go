@@ -3,12 +3,15 @@ package etcd
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/registry/generic"
 	"k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/apiserver/pkg/registry/rest"
 	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
 	"k8s.io/kubernetes/pkg/printers"
 	printerstorage "k8s.io/kubernetes/pkg/printers/storage"
 
@@ -27,8 +30,10 @@ type REST struct {
 var _ rest.StandardStorage = &REST{}
 
 // NewREST returns a RESTStorage object that will work against templateinstances.
-func NewREST(optsGetter generic.RESTOptionsGetter, authorizationClient authorizationclient.AuthorizationV1Interface) (*REST, *StatusREST, error) {
-	strategy := templateinstance.NewStrategy(authorizationClient)
+// Upgrades change the objects of templateinstances with clients impersonating their
+// requesters built from clientConfig.
+func NewREST(optsGetter generic.RESTOptionsGetter, authorizationClient authorizationclient.AuthorizationV1Interface, secretsClient corev1client.SecretsGetter, clientConfig *restclient.Config, restMapper meta.RESTMapper) (*REST, *StatusREST, *UpgradeREST, error) {
+	strategy := templateinstance.NewStrategy(authorizationClient, secretsClient)
 
 	store := &registry.Store{
 		NewFunc:                   func() runtime.Object { return &templateapi.TemplateInstance{} },
@@ -45,13 +50,16 @@ func NewREST(optsGetter generic.RESTOptionsGetter, authorizationClient authoriza
 
 	options := &generic.StoreOptions{RESTOptions: optsGetter}
 	if err := store.CompleteWithOptions(options); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	statusStore := *store
 	statusStore.UpdateStrategy = templateinstance.StatusStrategy
 
-	return &REST{store}, &StatusREST{&statusStore}, nil
+	upgradeStore := *store
+	upgradeStore.UpdateStrategy = templateinstance.NewUpgradeStrategy(strategy)
+
+	return &REST{store}, &StatusREST{&statusStore}, newUpgradeREST(&upgradeStore, clientConfig, restMapper), nil
 }
 
 // StatusREST implements the REST endpoint for changing the status of a templateInstance.
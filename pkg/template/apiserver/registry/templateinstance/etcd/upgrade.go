@@ -0,0 +1,506 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/client-go/dynamic"
+	restclient "k8s.io/client-go/rest"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"github.com/openshift/api/template"
+	templatev1 "github.com/openshift/api/template/v1"
+	"github.com/openshift/library-go/pkg/template/generator"
+	"github.com/openshift/library-go/pkg/template/templateprocessing"
+
+	"github.com/openshift/openshift-apiserver/pkg/client/impersonatingclient"
+	templateapi "github.com/openshift/openshift-apiserver/pkg/template/apis/template"
+	templatev1conversion "github.com/openshift/openshift-apiserver/pkg/template/apis/template/v1"
+	templatevalidation "github.com/openshift/openshift-apiserver/pkg/template/apis/template/validation"
+)
+
+const (
+	// upgradeFieldManager manages the fields of the objects applied by upgrades.
+	upgradeFieldManager = "templateinstance-upgrade"
+	// instantiateFieldManager is the field manager of the templateinstance
+	// controller of openshift-controller-manager, which creates the objects of
+	// templateinstances when they are instantiated.
+	instantiateFieldManager = "openshift-controller-manager"
+
+	upgradingReason       = "Upgrading"
+	upgradedReason        = "Upgraded"
+	upgradeFailedReason   = "UpgradeFailed"
+	upgradeConflictReason = "UpgradeConflict"
+)
+
+// UpgradeREST implements templateinstances/upgrade, which upgrades a templateinstance
+// in place to the template of the posted templateinstance. The objects of the
+// processed template are applied as the requester, and the objects created for the
+// previous template that the new one does not have are deleted.
+type UpgradeREST struct {
+	store      *registry.Store
+	restMapper meta.RESTMapper
+	// newClient returns a client impersonating the requester of a templateinstance.
+	newClient func(requester user.Info) (dynamic.Interface, error)
+}
+
+var _ rest.NamedCreater = &UpgradeREST{}
+
+func newUpgradeREST(store *registry.Store, clientConfig *restclient.Config, restMapper meta.RESTMapper) *UpgradeREST {
+	return &UpgradeREST{
+		store:      store,
+		restMapper: restMapper,
+		newClient: func(requester user.Info) (dynamic.Interface, error) {
+			config := impersonatingclient.NewImpersonatingConfig(requester, *clientConfig)
+			return dynamic.NewForConfig(&config)
+		},
+	}
+}
+
+// New returns the templateinstance holding the template to upgrade to.
+func (r *UpgradeREST) New() runtime.Object {
+	return &templateapi.TemplateInstance{}
+}
+
+func (r *UpgradeREST) Destroy() {}
+
+// Create upgrades the named templateinstance to the template of obj and returns the
+// upgraded templateinstance. The progress of the upgrade is recorded in its Upgraded
+// condition and its status objects after each object is applied or deleted, and its
+// result in its Upgraded condition once it completes: spec.template is only changed
+// if all the objects were upgraded, while the status objects are those left by the
+// upgrade even if it failed.
+func (r *UpgradeREST) Create(ctx context.Context, name string, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+	upgrade, ok := obj.(*templateapi.TemplateInstance)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("not a templateinstance: %#v", obj))
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, upgrade.DeepCopyObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := r.store.Get(ctx, name, &metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	old := existing.(*templateapi.TemplateInstance)
+	if len(upgrade.ResourceVersion) > 0 && upgrade.ResourceVersion != old.ResourceVersion {
+		return nil, errors.NewConflict(template.Resource("templateinstances"), name, fmt.Errorf(registry.OptimisticLockErrorMsg))
+	}
+	if !hasTrueCondition(old, templateapi.TemplateInstanceReady) {
+		return nil, errors.NewBadRequest(fmt.Sprintf("templateinstance %s/%s cannot be upgraded until it is ready", old.Namespace, name))
+	}
+
+	templateInstance := old.DeepCopy()
+	templateInstance.Spec.Template = upgrade.Spec.Template
+	if err := rest.BeforeUpdate(r.store.UpdateStrategy, ctx, templateInstance, old); err != nil {
+		return nil, err
+	}
+
+	client, err := r.newClient(requesterUserInfo(old.Spec.Requester))
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	objects, err := processTemplate(ctx, client, templateInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := func(statusObjects []templateapi.TemplateInstanceObject, message string) {
+		if len(options.DryRun) > 0 {
+			return
+		}
+		_, _, err := r.store.Update(ctx, name, rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, _, oldObj runtime.Object) (runtime.Object, error) {
+			current := oldObj.DeepCopyObject().(*templateapi.TemplateInstance)
+			current.Status.Objects = statusObjects
+			setCondition(current, templateapi.TemplateInstanceCondition{
+				Type:    templateapi.TemplateInstanceUpgraded,
+				Status:  kapi.ConditionUnknown,
+				Reason:  upgradingReason,
+				Message: message,
+			})
+			return current, nil
+		}), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{})
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("unable to record the progress of the upgrade of templateinstance %s/%s: %v", old.Namespace, name, err))
+		}
+	}
+	statusObjects, message, upgradeErr := applyUpgrade(ctx, client, r.restMapper, old.Status.Objects, objects, options.DryRun, progress)
+	condition := templateapi.TemplateInstanceCondition{
+		Type:    templateapi.TemplateInstanceUpgraded,
+		Status:  kapi.ConditionTrue,
+		Reason:  upgradedReason,
+		Message: message,
+	}
+	if upgradeErr != nil {
+		condition.Status = kapi.ConditionFalse
+		condition.Reason = upgradeFailedReason
+		if errors.IsConflict(upgradeErr) {
+			condition.Reason = upgradeConflictReason
+		}
+		condition.Message = fmt.Sprintf("%v; %s before the failure, spec.template was not changed", upgradeErr, message)
+	}
+
+	updated, _, err := r.store.Update(ctx, name, rest.DefaultUpdatedObjectInfo(nil, func(ctx context.Context, _, oldObj runtime.Object) (runtime.Object, error) {
+		current := oldObj.DeepCopyObject().(*templateapi.TemplateInstance)
+		if upgradeErr == nil {
+			current.Spec.Template = templateInstance.Spec.Template
+		}
+		current.Status.Objects = statusObjects
+		setCondition(current, condition)
+		return current, nil
+	}), rest.ValidateAllObjectFunc, rest.ValidateAllObjectUpdateFunc, false, &metav1.UpdateOptions{DryRun: options.DryRun})
+	if upgradeErr != nil {
+		return nil, upgradeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// processTemplate returns the objects of the template of templateInstance processed
+// with the parameter values of its secret, read with client. Parameter values are
+// not generated, as the objects being updated hold the values generated when the
+// template was instantiated.
+func processTemplate(ctx context.Context, client dynamic.Interface, templateInstance *templateapi.TemplateInstance) ([]*unstructured.Unstructured, error) {
+	tpl := templateInstance.Spec.Template.DeepCopy()
+	fromSecret := sets.NewString()
+	if templateInstance.Spec.Secret != nil {
+		obj, err := client.Resource(corev1.SchemeGroupVersion.WithResource("secrets")).Namespace(templateInstance.Namespace).Get(ctx, templateInstance.Spec.Secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		secret := &corev1.Secret{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, secret); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		for i, param := range tpl.Parameters {
+			if value, ok := secret.Data[param.Name]; ok {
+				tpl.Parameters[i].Value = string(value)
+				fromSecret.Insert(param.Name)
+			}
+		}
+	}
+
+	var allErrs field.ErrorList
+	for i := range tpl.Parameters {
+		param := &tpl.Parameters[i]
+		paramPath := field.NewPath("spec", "template", "parameters").Index(i)
+		if len(param.Value) == 0 && len(param.Generate) > 0 {
+			allErrs = append(allErrs, field.Forbidden(paramPath.Child("generate"), "values are not generated on upgrade, set the parameter in the secret"))
+		}
+		if len(param.Value) == 0 || !templatevalidation.HasParameterConstraints(param) {
+			continue
+		}
+		// values held in the secret are not echoed back
+		for _, msg := range templatevalidation.ValidateParameterValue(param, param.Value) {
+			if fromSecret.Has(param.Name) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "secret").Key(param.Name), field.OmitValueType{}, msg))
+			} else {
+				allErrs = append(allErrs, field.Invalid(paramPath.Child("value"), param.Value, msg))
+			}
+		}
+	}
+	if len(allErrs) > 0 {
+		return nil, errors.NewInvalid(template.Kind("TemplateInstance"), templateInstance.Name, allErrs)
+	}
+
+	if tpl.ObjectLabels == nil {
+		tpl.ObjectLabels = map[string]string{}
+	}
+	tpl.ObjectLabels[templateapi.TemplateInstanceOwner] = string(templateInstance.UID)
+
+	externalTemplate := &templatev1.Template{}
+	if err := templatev1conversion.Convert_template_Template_To_v1_Template(tpl, externalTemplate, nil); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	processor := templateprocessing.NewProcessor(map[string]generator.Generator{})
+	if errs := processor.Process(externalTemplate); len(errs) > 0 {
+		return nil, errors.NewInvalid(template.Kind("TemplateInstance"), templateInstance.Name, errs)
+	}
+
+	objects := []*unstructured.Unstructured{}
+	for _, item := range externalTemplate.Objects {
+		object, ok := item.Object.(*unstructured.Unstructured)
+		if !ok {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item.Object)
+			if err != nil {
+				return nil, errors.NewInternalError(err)
+			}
+			object = &unstructured.Unstructured{Object: content}
+		}
+		if len(object.GetName()) == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("%s objects without a name cannot be upgraded", object.GetKind()))
+		}
+		if len(object.GetNamespace()) == 0 {
+			object.SetNamespace(templateInstance.Namespace)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// objectKey identifies an object across the versions of its API.
+type objectKey struct {
+	group, kind, namespace, name string
+}
+
+func objectKeyFor(ref kapi.ObjectReference) objectKey {
+	gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+	return objectKey{group: gv.Group, kind: ref.Kind, namespace: ref.Namespace, name: ref.Name}
+}
+
+// describeObject returns the kind, namespace and name of the object ref refers to.
+func describeObject(ref kapi.ObjectReference) string {
+	if len(ref.Namespace) == 0 {
+		return ref.Kind + " " + ref.Name
+	}
+	return ref.Kind + " " + ref.Namespace + "/" + ref.Name
+}
+
+// applyUpgrade applies objects and deletes the previous objects that are not among
+// them with client, returning the objects of the upgraded templateinstance and a
+// summary of the changes. progress is called with the objects of the
+// templateinstance and the summary after each object is applied or deleted.
+//
+// Objects are applied without forcing, so that the fields another manager changed
+// are reported as conflicts rather than overwritten; the previous objects are only
+// deleted once all the objects were applied. On error, the objects returned are
+// those of the templateinstance when the error occurred and the summary describes
+// the changes made before it.
+func applyUpgrade(ctx context.Context, client dynamic.Interface, restMapper meta.RESTMapper, previous []templateapi.TemplateInstanceObject, objects []*unstructured.Unstructured, dryRun []string, progress func([]templateapi.TemplateInstanceObject, string)) ([]templateapi.TemplateInstanceObject, string, error) {
+	current := append([]templateapi.TemplateInstanceObject{}, previous...)
+	indexOf := func(key objectKey) int {
+		for i := range current {
+			if objectKeyFor(current[i].Ref) == key {
+				return i
+			}
+		}
+		return -1
+	}
+	resourceFor := func(gvk schema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, bool, error) {
+		mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, false, err
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			return client.Resource(mapping.Resource), false, nil
+		}
+		return client.Resource(mapping.Resource).Namespace(namespace), true, nil
+	}
+
+	created, updated, deleted := 0, 0, 0
+	summary := func() string {
+		return fmt.Sprintf("%d objects created, %d updated and %d deleted", created, updated, deleted)
+	}
+	applied := map[objectKey]bool{}
+	for _, object := range objects {
+		gvk := object.GroupVersionKind()
+		ref := kapi.ObjectReference{
+			Kind:       gvk.Kind,
+			APIVersion: gvk.GroupVersion().String(),
+			Namespace:  object.GetNamespace(),
+			Name:       object.GetName(),
+		}
+		resource, namespaced, err := resourceFor(gvk, object.GetNamespace())
+		if err != nil {
+			return current, summary(), fmt.Errorf("failed to apply %s: %w", describeObject(ref), err)
+		}
+		if !namespaced {
+			object.SetNamespace("")
+			ref.Namespace = ""
+		}
+		key := objectKeyFor(ref)
+
+		force := false
+		if indexOf(key) >= 0 {
+			owned, err := takeOwnership(ctx, resource, object.GetName(), dryRun)
+			if err != nil {
+				return current, summary(), fmt.Errorf("failed to take ownership of %s: %w", describeObject(ref), err)
+			}
+			// a dry run does not persist the change of ownership, so the fields
+			// set at instantiation are applied over instead
+			force = owned && len(dryRun) > 0
+		}
+		result, err := resource.Apply(ctx, object.GetName(), object, metav1.ApplyOptions{FieldManager: upgradeFieldManager, Force: force, DryRun: dryRun})
+		if err != nil {
+			return current, summary(), fmt.Errorf("failed to apply %s: %w", describeObject(ref), err)
+		}
+
+		ref.UID = result.GetUID()
+		applied[key] = true
+		if i := indexOf(key); i >= 0 {
+			current[i].Ref = ref
+			updated++
+		} else {
+			current = append(current, templateapi.TemplateInstanceObject{Ref: ref})
+			created++
+		}
+		progress(current, fmt.Sprintf("applied %s, %s", describeObject(ref), summary()))
+	}
+
+	for _, object := range previous {
+		key := objectKeyFor(object.Ref)
+		if applied[key] {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(object.Ref.APIVersion)
+		if err != nil {
+			return current, summary(), fmt.Errorf("failed to delete %s: %w", describeObject(object.Ref), err)
+		}
+		resource, _, err := resourceFor(gv.WithKind(object.Ref.Kind), object.Ref.Namespace)
+		if err != nil {
+			return current, summary(), fmt.Errorf("failed to delete %s: %w", describeObject(object.Ref), err)
+		}
+		propagationPolicy := metav1.DeletePropagationBackground
+		deleteOptions := metav1.DeleteOptions{PropagationPolicy: &propagationPolicy, DryRun: dryRun}
+		if len(object.Ref.UID) > 0 {
+			deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(object.Ref.UID))
+		}
+		if err := resource.Delete(ctx, object.Ref.Name, deleteOptions); err != nil && !errors.IsNotFound(err) {
+			return current, summary(), fmt.Errorf("failed to delete %s: %w", describeObject(object.Ref), err)
+		}
+		if i := indexOf(key); i >= 0 {
+			current = append(current[:i], current[i+1:]...)
+		}
+		deleted++
+		progress(current, fmt.Sprintf("deleted %s, %s", describeObject(object.Ref), summary()))
+	}
+
+	return current, summary(), nil
+}
+
+// takeOwnership moves the fields the templateinstance controller set when it
+// created the named object to upgradeFieldManager, so that applying the upgraded
+// object removes the fields the new template dropped and only reports conflicts
+// with the fields other managers changed. It returns whether there were fields to
+// move, which are only moved if dryRun is empty. Objects which do not exist are
+// created by the apply.
+func takeOwnership(ctx context.Context, resource dynamic.ResourceInterface, name string, dryRun []string) (bool, error) {
+	object, err := resource.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var managedFields []metav1.ManagedFieldsEntry
+	var owned *metav1.ManagedFieldsEntry
+	fields := &fieldpath.Set{}
+	moved := false
+	for _, entry := range object.GetManagedFields() {
+		switch {
+		case entry.Manager == instantiateFieldManager && entry.Operation == metav1.ManagedFieldsOperationUpdate && len(entry.Subresource) == 0:
+			moved = true
+		case entry.Manager == upgradeFieldManager && entry.Operation == metav1.ManagedFieldsOperationApply && len(entry.Subresource) == 0:
+			entry := entry
+			owned = &entry
+		default:
+			managedFields = append(managedFields, entry)
+			continue
+		}
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		entryFields := &fieldpath.Set{}
+		if err := entryFields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return false, err
+		}
+		fields = fields.Union(entryFields)
+	}
+	if !moved || len(dryRun) > 0 {
+		return moved, nil
+	}
+
+	raw, err := fields.ToJSON()
+	if err != nil {
+		return false, err
+	}
+	if owned == nil {
+		owned = &metav1.ManagedFieldsEntry{
+			Manager:    upgradeFieldManager,
+			Operation:  metav1.ManagedFieldsOperationApply,
+			APIVersion: object.GetAPIVersion(),
+			FieldsType: "FieldsV1",
+		}
+	}
+	now := metav1.Now()
+	owned.Time = &now
+	owned.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+	managedFields = append(managedFields, *owned)
+
+	// the resource version guards against a change of the managed fields since
+	// they were read
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": object.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+	if err != nil {
+		return false, err
+	}
+	if _, err := resource.Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func hasTrueCondition(templateInstance *templateapi.TemplateInstance, conditionType templateapi.TemplateInstanceConditionType) bool {
+	for _, condition := range templateInstance.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == kapi.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// setCondition replaces the condition of the same type of templateInstance, keeping
+// its last transition time if its status did not change.
+func setCondition(templateInstance *templateapi.TemplateInstance, condition templateapi.TemplateInstanceCondition) {
+	condition.LastTransitionTime = metav1.Now()
+	for i, current := range templateInstance.Status.Conditions {
+		if current.Type != condition.Type {
+			continue
+		}
+		if current.Status == condition.Status {
+			condition.LastTransitionTime = current.LastTransitionTime
+		}
+		templateInstance.Status.Conditions[i] = condition
+		return
+	}
+	templateInstance.Status.Conditions = append(templateInstance.Status.Conditions, condition)
+}
+
+// requesterUserInfo returns the user the requester of a templateinstance identifies.
+func requesterUserInfo(requester *templateapi.TemplateInstanceRequester) user.Info {
+	extra := map[string][]string{}
+	for k, v := range requester.Extra {
+		extra[k] = []string(v)
+	}
+	return &user.DefaultInfo{
+		Name:   requester.Username,
+		UID:    requester.UID,
+		Groups: requester.Groups,
+		Extra:  extra,
+	}
+}
@@ -0,0 +1,269 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+
+	templateapi "github.com/openshift/openshift-apiserver/pkg/template/apis/template"
+)
+
+func configMap(name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test"},
+		"data":       data,
+	}}
+}
+
+func configMapObject(name string, uid types.UID) templateapi.TemplateInstanceObject {
+	return templateapi.TemplateInstanceObject{Ref: kapi.ObjectReference{Kind: "ConfigMap", APIVersion: "v1", Namespace: "test", Name: name, UID: uid}}
+}
+
+func testRESTMapper() meta.RESTMapper {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	return restMapper
+}
+
+func TestProcessTemplate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	corev1.AddToScheme(scheme)
+	client := dynamicfake.NewSimpleDynamicClient(scheme, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: "test"},
+		Data:       map[string][]byte{"VALUE": []byte("from-secret")},
+	})
+
+	templateInstance := &templateapi.TemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "test", UID: "instance-uid"},
+		Spec: templateapi.TemplateInstanceSpec{
+			Template: templateapi.Template{
+				Parameters: []templateapi.Parameter{{Name: "VALUE", Value: "default"}},
+				Objects:    []runtime.Object{configMap("config", map[string]interface{}{"value": "${VALUE}"})},
+			},
+			Secret: &kapi.LocalObjectReference{Name: "params"},
+		},
+	}
+	objects, err := processTemplate(context.TODO(), client, templateInstance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected one object, got %#v", objects)
+	}
+	if value, _, _ := unstructured.NestedString(objects[0].Object, "data", "value"); value != "from-secret" {
+		t.Errorf("expected the value of the secret to be substituted, got %q", value)
+	}
+	if owner := objects[0].GetLabels()[templateapi.TemplateInstanceOwner]; owner != "instance-uid" {
+		t.Errorf("expected the object to be labelled with its owner, got %q", owner)
+	}
+
+	templateInstance.Spec.Template.Parameters[0].Pattern = "^[0-9]+$"
+	_, err = processTemplate(context.TODO(), client, templateInstance)
+	if !errors.IsInvalid(err) {
+		t.Fatalf("expected the value of the secret to be rejected, got %v", err)
+	}
+	if causes := err.(*errors.StatusError).ErrStatus.Details.Causes; len(causes) != 1 || causes[0].Field != "spec.secret[VALUE]" || strings.Contains(causes[0].Message, "from-secret") {
+		t.Errorf("expected a redacted error on the secret, got %v", err)
+	}
+	templateInstance.Spec.Template.Parameters[0].Pattern = ""
+
+	templateInstance.Spec.Template.Parameters = append(templateInstance.Spec.Template.Parameters, templateapi.Parameter{Name: "PASSWORD", Generate: "expression", From: "[a-z]{8}"})
+	if _, err := processTemplate(context.TODO(), client, templateInstance); !errors.IsInvalid(err) {
+		t.Errorf("expected a generated parameter to be rejected, got %v", err)
+	}
+}
+
+func TestApplyUpgrade(t *testing.T) {
+	previous := []templateapi.TemplateInstanceObject{configMapObject("kept", "kept-uid"), configMapObject("removed", "removed-uid")}
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(corev1.Resource("configmaps"), action.(clientgotesting.GetAction).GetName())
+	})
+	client.PrependReactor("patch", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		name := action.(clientgotesting.PatchAction).GetName()
+		switch name {
+		case "failed":
+			return true, nil, fmt.Errorf("apply failed")
+		case "conflicting":
+			return true, nil, errors.NewConflict(corev1.Resource("configmaps"), name, fmt.Errorf(`Apply failed with 1 conflict: conflict with "kubectl-edit": .data.value`))
+		}
+		object := configMap(name, nil)
+		object.SetUID(types.UID(name + "-uid"))
+		return true, object, nil
+	})
+	client.PrependReactor("delete", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	var progress []string
+	recordProgress := func(objects []templateapi.TemplateInstanceObject, message string) {
+		progress = append(progress, fmt.Sprintf("%d objects: %s", len(objects), message))
+	}
+	objects, message, err := applyUpgrade(context.TODO(), client, testRESTMapper(), previous, []*unstructured.Unstructured{configMap("kept", nil), configMap("created", nil)}, nil, recordProgress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []templateapi.TemplateInstanceObject{configMapObject("kept", "kept-uid"), configMapObject("created", "created-uid")}
+	if !reflect.DeepEqual(objects, expected) {
+		t.Errorf("expected objects %#v, got %#v", expected, objects)
+	}
+	if message != "1 objects created, 1 updated and 1 deleted" {
+		t.Errorf("unexpected message %q", message)
+	}
+	var verbs []string
+	for _, action := range client.Actions() {
+		verbs = append(verbs, action.GetVerb()+" "+action.(interface{ GetName() string }).GetName())
+	}
+	if expectedVerbs := []string{"get kept", "patch kept", "patch created", "delete removed"}; !reflect.DeepEqual(verbs, expectedVerbs) {
+		t.Errorf("expected actions %v, got %v", expectedVerbs, verbs)
+	}
+	expectedProgress := []string{
+		"2 objects: applied ConfigMap test/kept, 0 objects created, 1 updated and 0 deleted",
+		"3 objects: applied ConfigMap test/created, 1 objects created, 1 updated and 0 deleted",
+		"2 objects: deleted ConfigMap test/removed, 1 objects created, 1 updated and 1 deleted",
+	}
+	if !reflect.DeepEqual(progress, expectedProgress) {
+		t.Errorf("expected progress %v, got %v", expectedProgress, progress)
+	}
+
+	client.ClearActions()
+	progress = nil
+	objects, message, err = applyUpgrade(context.TODO(), client, testRESTMapper(), previous, []*unstructured.Unstructured{configMap("created", nil), configMap("failed", nil)}, nil, recordProgress)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	expected = append(previous, configMapObject("created", "created-uid"))
+	if !reflect.DeepEqual(objects, expected) {
+		t.Errorf("expected the objects when the upgrade failed %#v, got %#v", expected, objects)
+	}
+	if message != "1 objects created, 0 updated and 0 deleted" {
+		t.Errorf("expected the changes made before the failure, got %q", message)
+	}
+	if len(progress) != 1 {
+		t.Errorf("expected the progress of the object applied before the failure, got %v", progress)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Errorf("expected no objects to be deleted after a failure, got %v", action)
+		}
+	}
+
+	_, _, err = applyUpgrade(context.TODO(), client, testRESTMapper(), previous, []*unstructured.Unstructured{configMap("conflicting", nil)}, nil, recordProgress)
+	if !errors.IsConflict(err) || !strings.Contains(err.Error(), "ConfigMap test/conflicting") {
+		t.Errorf("expected the conflict to be reported, got %v", err)
+	}
+}
+
+func TestTakeOwnership(t *testing.T) {
+	managedFields := func(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: manager, Operation: operation, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(fields)}}
+	}
+	tests := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		dryRun        []string
+		expected      bool
+		expectedPatch bool
+	}{
+		{
+			name: "instantiated",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(instantiateFieldManager, metav1.ManagedFieldsOperationUpdate, `{"f:data":{".":{},"f:dropped":{},"f:value":{}}}`),
+				managedFields("kubectl-edit", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:edited":{}}}`),
+			},
+			expected:      true,
+			expectedPatch: true,
+		},
+		{
+			name: "instantiated, dry run",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(instantiateFieldManager, metav1.ManagedFieldsOperationUpdate, `{"f:data":{".":{},"f:dropped":{},"f:value":{}}}`),
+			},
+			dryRun:   []string{metav1.DryRunAll},
+			expected: true,
+		},
+		{
+			name: "upgraded",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFields(upgradeFieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:value":{}}}`),
+				managedFields("kubectl-edit", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:edited":{}}}`),
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := configMap("kept", map[string]interface{}{"value": "a", "dropped": "b", "edited": "c"})
+			object.SetResourceVersion("5")
+			object.SetManagedFields(test.managedFields)
+
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			client.PrependReactor("get", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, object.DeepCopy(), nil
+			})
+			var patch []byte
+			client.PrependReactor("patch", "configmaps", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				patch = action.(clientgotesting.PatchAction).GetPatch()
+				return true, object.DeepCopy(), nil
+			})
+
+			resource := client.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace("test")
+			owned, err := takeOwnership(context.TODO(), resource, "kept", test.dryRun)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if owned != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, owned)
+			}
+			if !test.expectedPatch {
+				if patch != nil {
+					t.Errorf("expected no patch, got %s", patch)
+				}
+				return
+			}
+
+			var operations []struct {
+				Op    string          `json:"op"`
+				Path  string          `json:"path"`
+				Value json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(patch, &operations); err != nil {
+				t.Fatal(err)
+			}
+			if len(operations) != 2 || operations[0].Op != "test" || string(operations[0].Value) != `"5"` {
+				t.Fatalf("expected the patch to be guarded by the resource version, got %s", patch)
+			}
+			var entries []metav1.ManagedFieldsEntry
+			if err := json.Unmarshal(operations[1].Value, &entries); err != nil {
+				t.Fatal(err)
+			}
+			managers := map[string]string{}
+			for _, entry := range entries {
+				managers[entry.Manager+"/"+string(entry.Operation)] = string(entry.FieldsV1.Raw)
+			}
+			expected := map[string]string{
+				upgradeFieldManager + "/Apply": `{"f:data":{".":{},"f:dropped":{},"f:value":{}}}`,
+				"kubectl-edit/Update":          `{"f:data":{"f:edited":{}}}`,
+			}
+			if !reflect.DeepEqual(managers, expected) {
+				t.Errorf("expected managed fields %v, got %v", expected, managers)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package templateinstance
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	authorizationv1 "k8s.io/api/authorization/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	kutilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -12,7 +15,9 @@ import (
 	"k8s.io/apiserver/pkg/authentication/user"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/apiserver/pkg/warning"
 	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/kubernetes/pkg/api/legacyscheme"
 	kapihelper "k8s.io/kubernetes/pkg/apis/core/helper"
 
@@ -26,10 +31,11 @@ type templateInstanceStrategy struct {
 	runtime.ObjectTyper
 	names.NameGenerator
 	authorizationClient authorizationclient.AuthorizationV1Interface
+	secretsClient       corev1client.SecretsGetter
 }
 
-func NewStrategy(authorizationClient authorizationclient.AuthorizationV1Interface) *templateInstanceStrategy {
-	return &templateInstanceStrategy{legacyscheme.Scheme, names.SimpleNameGenerator, authorizationClient}
+func NewStrategy(authorizationClient authorizationclient.AuthorizationV1Interface, secretsClient corev1client.SecretsGetter) *templateInstanceStrategy {
+	return &templateInstanceStrategy{legacyscheme.Scheme, names.SimpleNameGenerator, authorizationClient, secretsClient}
 }
 
 // NamespaceScoped is true for templateinstances.
@@ -77,10 +83,64 @@ func (s *templateInstanceStrategy) Validate(ctx context.Context, obj runtime.Obj
 	templateInstance := obj.(*templateapi.TemplateInstance)
 	allErrs := validation.ValidateTemplateInstance(templateInstance)
 	allErrs = append(allErrs, s.validateImpersonation(templateInstance, user)...)
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, s.validateSecretParameterValues(ctx, templateInstance, user)...)
+	}
 
 	return allErrs
 }
 
+// validateSecretParameterValues checks the parameter values held in the secret of a
+// templateinstance against the constraints of its template. Values are redacted
+// from the errors returned. The secret is only read if the user may get it; a
+// warning is returned instead if the values could not be checked.
+func (s *templateInstanceStrategy) validateSecretParameterValues(ctx context.Context, templateInstance *templateapi.TemplateInstance, userinfo user.Info) field.ErrorList {
+	if templateInstance.Spec.Secret == nil {
+		return nil
+	}
+	var params []*templateapi.Parameter
+	for i := range templateInstance.Spec.Template.Parameters {
+		if param := &templateInstance.Spec.Template.Parameters[i]; validation.HasParameterConstraints(param) {
+			params = append(params, param)
+		}
+	}
+	if len(params) == 0 {
+		return nil
+	}
+
+	secretName := templateInstance.Spec.Secret.Name
+	if err := authorizationutil.Authorize(s.authorizationClient.SubjectAccessReviews(), userinfo, &authorizationv1.ResourceAttributes{
+		Namespace: templateInstance.Namespace,
+		Verb:      "get",
+		Resource:  "secrets",
+		Name:      secretName,
+	}); err != nil {
+		warning.AddWarning(ctx, "", fmt.Sprintf("the parameter values in secret %q were not checked against the constraints of the template: you do not have permission to get it", secretName))
+		return nil
+	}
+	secret, err := s.secretsClient.Secrets(templateInstance.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		warning.AddWarning(ctx, "", fmt.Sprintf("the parameter values in secret %q were not checked against the constraints of the template: the secret does not exist", secretName))
+		return nil
+	case err != nil:
+		warning.AddWarning(ctx, "", fmt.Sprintf("the parameter values in secret %q were not checked against the constraints of the template: %v", secretName, err))
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	for _, param := range params {
+		value := string(secret.Data[param.Name])
+		if len(value) == 0 {
+			continue
+		}
+		for _, msg := range validation.ValidateParameterValue(param, value) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "secret").Key(param.Name), field.OmitValueType{}, msg))
+		}
+	}
+	return allErrs
+}
+
 // WarningsOnCreate returns warnings for the creation of the given object.
 func (templateInstanceStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
 	return nil
@@ -207,6 +267,38 @@ func (statusStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Obj
 	return nil
 }
 
+// upgradeStrategy implements behavior for the upgrade of TemplateInstances to a
+// new template, which sets spec.template and status.
+type upgradeStrategy struct {
+	*templateInstanceStrategy
+}
+
+// NewUpgradeStrategy returns the strategy of templateinstances/upgrade, which
+// validates the requester like strategy.
+func NewUpgradeStrategy(strategy *templateInstanceStrategy) *upgradeStrategy {
+	return &upgradeStrategy{strategy}
+}
+
+// PrepareForUpdate keeps the spec.template and status set by the upgrade.
+func (upgradeStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+}
+
+// ValidateUpdate validates the upgrade of a templateinstance, re-checking the
+// impersonation of its requester.
+func (s *upgradeStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	user, ok := apirequest.UserFrom(ctx)
+	if !ok {
+		return field.ErrorList{field.InternalError(field.NewPath(""), errors.New("user not found in context"))}
+	}
+
+	templateInstance := obj.(*templateapi.TemplateInstance)
+	oldTemplateInstance := old.(*templateapi.TemplateInstance)
+	allErrs := validation.ValidateTemplateInstanceUpgrade(templateInstance, oldTemplateInstance)
+	allErrs = append(allErrs, s.validateImpersonationUpdate(templateInstance, oldTemplateInstance, user)...)
+
+	return allErrs
+}
+
 // convertUserToTemplateInstanceRequester copies analogous fields from user.Info to TemplateInstanceRequester
 func convertUserToTemplateInstanceRequester(u user.Info) templateapi.TemplateInstanceRequester {
 	templatereq := templateapi.TemplateInstanceRequester{}
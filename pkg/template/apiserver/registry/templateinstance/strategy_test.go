@@ -0,0 +1,101 @@
+package templateinstance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/warning"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	kapi "k8s.io/kubernetes/pkg/apis/core"
+
+	templateapi "github.com/openshift/openshift-apiserver/pkg/template/apis/template"
+)
+
+type testWarningRecorder struct {
+	warnings []string
+}
+
+func (r *testWarningRecorder) AddWarning(agent, text string) {
+	r.warnings = append(r.warnings, text)
+}
+
+func TestValidateSecretParameterValues(t *testing.T) {
+	tests := []struct {
+		name            string
+		secret          *corev1.Secret
+		denied          bool
+		expectedErrors  int
+		expectedWarning string
+	}{
+		{
+			name:   "valid value",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: "test"}, Data: map[string][]byte{"PORT": []byte("8080")}},
+		},
+		{
+			name:           "invalid value",
+			secret:         &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: "test"}, Data: map[string][]byte{"PORT": []byte("s3cr3t")}},
+			expectedErrors: 1,
+		},
+		{
+			name:            "secret not readable",
+			secret:          &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: "test"}, Data: map[string][]byte{"PORT": []byte("s3cr3t")}},
+			denied:          true,
+			expectedWarning: "you do not have permission to get it",
+		},
+		{
+			name:            "secret not found",
+			expectedWarning: "the secret does not exist",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			if test.secret != nil {
+				objects = append(objects, test.secret)
+			}
+			client := fake.NewSimpleClientset(objects...)
+			client.PrependReactor("create", "subjectaccessreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				return true, &authorizationv1.SubjectAccessReview{Status: authorizationv1.SubjectAccessReviewStatus{Allowed: !test.denied}}, nil
+			})
+			strategy := NewStrategy(client.AuthorizationV1(), client.CoreV1())
+
+			recorder := &testWarningRecorder{}
+			ctx := warning.WithWarningRecorder(context.Background(), recorder)
+			templateInstance := &templateapi.TemplateInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "instance", Namespace: "test"},
+				Spec: templateapi.TemplateInstanceSpec{
+					Template: templateapi.Template{
+						Parameters: []templateapi.Parameter{{Name: "PORT", Type: templateapi.ParameterTypeInt}},
+					},
+					Secret: &kapi.LocalObjectReference{Name: "params"},
+				},
+			}
+			errs := strategy.validateSecretParameterValues(ctx, templateInstance, &user.DefaultInfo{Name: "user"})
+
+			if len(errs) != test.expectedErrors {
+				t.Fatalf("expected %d errors, got %v", test.expectedErrors, errs)
+			}
+			for _, err := range errs {
+				if err.Field != "spec.secret[PORT]" || strings.Contains(err.Error(), "s3cr3t") {
+					t.Errorf("expected a redacted error on the secret, got %v", err)
+				}
+			}
+			if len(test.expectedWarning) == 0 {
+				if len(recorder.warnings) > 0 {
+					t.Errorf("expected no warnings, got %v", recorder.warnings)
+				}
+				return
+			}
+			if len(recorder.warnings) != 1 || !strings.Contains(recorder.warnings[0], test.expectedWarning) {
+				t.Errorf("expected a warning that %s, got %v", test.expectedWarning, recorder.warnings)
+			}
+		})
+	}
+}